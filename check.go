@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+// stale - whether -check found a generated file which is not up to date
+var stale bool
+
+// checkOutput - compare the generated source with the existing file and report the file, and the types whose code
+// changed, if it is not up to date. lists maps the names of the list types in the file to their element types
+func checkOutput(filename, src string, lists map[string]string) {
+	existing, err := ioutil.ReadFile(filename)
+	if err != nil {
+		stale = true
+		fmt.Fprintf(os.Stderr, "%s: missing\n", filename)
+		return
+	}
+	if string(existing) == src {
+		return
+	}
+
+	stale = true
+	types := staleTypes(existing, []byte(src), lists)
+	if len(types) == 0 {
+		fmt.Fprintf(os.Stderr, "%s: stale\n", filename)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s: stale types: %s\n", filename, strings.Join(types, ", "))
+}
+
+// staleTypes - get the element types whose declarations differ between the existing and the generated source. Every
+// top-level declaration belongs to the list type its name (or the type of its receiver) starts with
+func staleTypes(existing, generated []byte, lists map[string]string) []string {
+	before, err := declarationsByList(existing, lists)
+	if err != nil {
+		// the existing file cannot be parsed, so everything in it is stale
+		before = map[string]string{}
+	}
+	after, err := declarationsByList(generated, lists)
+	if err != nil {
+		return nil
+	}
+
+	result := []string{}
+	for listName, typeName := range lists {
+		if before[listName] != after[listName] {
+			result = append(result, typeName)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// declarationsByList - get the source of the top-level declarations of a file, grouped by the list type they belong to
+func declarationsByList(src []byte, lists map[string]string) (map[string]string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]string{}
+	for _, decl := range file.Decls {
+		listName := gen.ListOf(gen.DeclarationName(decl), lists)
+		if listName == "" {
+			continue
+		}
+
+		start := decl.Pos()
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		case *ast.GenDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		}
+		result[listName] += string(src[fset.Position(start).Offset:fset.Position(decl.End()).Offset]) + "\n"
+	}
+	return result, nil
+}
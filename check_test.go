@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStaleTypes(t *testing.T) {
+	lists := map[string]string{"intList": "int", "IList": "int8", "stringList": "string"}
+	existing := `package main
+
+// intList is the type for a list that holds members of type int
+type intList []int
+
+func (l intList) Take(n int) intList {
+	return l[:n]
+}
+
+type IList []int8
+
+func newIListPool(size int) *IListPool {
+	return nil
+}
+
+type stringList []string
+`
+	generated := strings.Replace(existing, "return nil", "return &IListPool{}", 1)
+
+	result := staleTypes([]byte(existing), []byte(generated), lists)
+	if len(result) != 1 || result[0] != "int8" {
+		t.Fail()
+	}
+
+	result = staleTypes([]byte("package main\n\nfunc ("), []byte(generated), lists)
+	if len(result) != 3 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// findGeneratedFiles - find the Go files generated by fungen (including the generated tests, benchmarks and examples)
+// in the directories matching the patterns, like './...', sorted by name
+func findGeneratedFiles(patterns []string) ([]string, error) {
+	found := map[string]bool{}
+	for _, pattern := range patterns {
+		err := walkDirectories(pattern, func(dir string) error {
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				return err
+			}
+			for _, info := range files {
+				if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") {
+					continue
+				}
+				path := filepath.Join(dir, info.Name())
+				file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly|parser.ParseComments)
+				if err != nil {
+					// a file which cannot be parsed was not generated by fungen
+					continue
+				}
+				if generatedByFungen(file) {
+					found[path] = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := []string{}
+	for path := range found {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result, nil
+}
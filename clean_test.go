@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindGeneratedFiles(t *testing.T) {
+	root, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	generated := "// Code generated by fungen; DO NOT EDIT.\n\npackage models\n"
+	files := map[string]string{
+		"models.go":                     "package models\n",
+		"doc.go":                        "// Package models holds the lists generated by fungen and helpers written by hand.\npackage models\n",
+		"legacy_auto.go":                "// Package models - generated by fungen; DO NOT EDIT\n\npackage models\n",
+		"licensed_auto.go":              "// Copyright 2020 The Authors\n\n//go:build linux\n\n// Code generated by fungen; DO NOT EDIT.\n\npackage models\n",
+		"fungen_auto.go":                generated,
+		"fungen_auto_test.go":           generated,
+		"other_auto.go":                 "// Code generated by stringer; DO NOT EDIT.\n\npackage models\n",
+		"broken.go":                     "not go",
+		"lists/int.go":                  generated,
+		"vendor/lib/fungen_auto.go":     generated,
+		"testdata/fungen_auto.go":       generated,
+		"_skipped/fungen_auto.go":       generated,
+		"lists/notes/fungen_auto.go.md": generated,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := findGeneratedFiles([]string{root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the hand-written doc.go mentions fungen, but does not have its header
+	expected := []string{filepath.Join(root, "fungen_auto.go"), filepath.Join(root, "fungen_auto_test.go"), filepath.Join(root, "legacy_auto.go"), filepath.Join(root, "licensed_auto.go")}
+	if !reflect.DeepEqual(found, expected) {
+		t.Error(found)
+	}
+
+	found, err = findGeneratedFiles([]string{root + "/...", filepath.Join(root, "lists")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = append(expected, filepath.Join(root, "lists", "int.go"))
+	if !reflect.DeepEqual(found, expected) {
+		t.Error(found)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+// existingMethods - the methods declared in the other files of the package, by the name of the type of their receiver
+// and their name, with their position, eg: existingMethods["intList"]["Sum"] == "models.go:12:1"
+var existingMethods = map[string]map[string]string{}
+
+// findMethods - get the methods declared in the Go files of the package in a directory, except the test files and the
+// files generated by fungen, by the name of the type of their receiver and their name, with their position
+func findMethods(dir, packageName string) (map[string]map[string]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]map[string]string{}
+	fset := token.NewFileSet()
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil || file.Name.Name != packageName || generatedByFungen(file) {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil {
+				continue
+			}
+			typeName := gen.DeclarationName(fn)
+			if result[typeName] == nil {
+				result[typeName] = map[string]string{}
+			}
+			result[typeName][fn.Name.Name] = fset.Position(fn.Pos()).String()
+		}
+	}
+	return result, nil
+}
+
+// methodCollisions - get the methods of the lists in the generated source which are already declared on the lists in
+// the other files of the package, by list name
+func methodCollisions(src string, lists map[string]string, existing map[string]map[string]string) (map[string]map[string]bool, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]map[string]bool{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil {
+			continue
+		}
+		listName := gen.DeclarationName(fn)
+		if _, ok := lists[listName]; !ok {
+			continue
+		}
+		if _, ok := existing[listName][fn.Name.Name]; !ok {
+			continue
+		}
+		if result[listName] == nil {
+			result[listName] = map[string]bool{}
+		}
+		result[listName][fn.Name.Name] = true
+	}
+	return result, nil
+}
+
+// describeCollisions - describe the collisions, one per line in a fixed order, eg: 'Sum of intList (element type int),
+// declared at models.go:12:1'
+func describeCollisions(collisions map[string]map[string]bool, lists map[string]string, existing map[string]map[string]string) []string {
+	result := []string{}
+	for listName, methods := range collisions {
+		for method := range methods {
+			result = append(result, fmt.Sprintf("%s of %s (element type %s), declared at %s", method, listName, lists[listName], existing[listName][method]))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
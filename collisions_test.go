@@ -0,0 +1,67 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindMethods(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"models.go":      "package models\n\ntype intList []int\n\nfunc (l intList) Sum() int { return 0 }\n\nfunc (l *intList) Push(i int) {}\n\nfunc helper() {}\n",
+		"models_test.go": "package models\n\nfunc (l intList) Test() {}\n",
+		"fungen_auto.go": "// Code generated by fungen; DO NOT EDIT.\n\npackage models\n\nfunc (l intList) Map() {}\n",
+		"other.go":       "package other\n\nfunc (l intList) Other() {}\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	methods, err := findMethods(dir, "models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]map[string]string{"intList": {
+		"Sum":  filepath.Join(dir, "models.go") + ":5:1",
+		"Push": filepath.Join(dir, "models.go") + ":7:1",
+	}}
+	if !reflect.DeepEqual(methods, expected) {
+		t.Error(methods)
+	}
+}
+
+func TestMethodCollisions(t *testing.T) {
+	src := `package models
+
+type intList []int
+
+func (l intList) Sum() int { return 0 }
+
+func (l intList) Len() int { return len(l) }
+
+func (l stringList) Sum() string { return "" }
+`
+	lists := map[string]string{"intList": "int"}
+	existing := map[string]map[string]string{"intList": {"Sum": "models.go:5:1", "Push": "models.go:7:1"}, "stringList": {"Sum": "models.go:9:1"}}
+	collisions, err := methodCollisions(src, lists, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(collisions, map[string]map[string]bool{"intList": {"Sum": true}}) {
+		t.Error(collisions)
+	}
+	described := describeCollisions(collisions, lists, existing)
+	if !reflect.DeepEqual(described, []string{"Sum of intList (element type int), declared at models.go:5:1"}) {
+		t.Error(described)
+	}
+}
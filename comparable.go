@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kulshekhar/fungen/gen"
+	"github.com/kulshekhar/fungen/internal/spec"
+)
+
+// incomparableTypes - the element types whose members cannot be compared although their type expression does not show
+// it, with the reason, found by type-checking the package (see gen.ClassifyTypes)
+var incomparableTypes = map[string]string{}
+
+// orderedTypes - the element types whose members can be ordered although their type expression does not show it, eg:
+// 'time.Duration', with 'number' or 'string', found by type-checking the package (see gen.ClassifyTypes)
+var orderedTypes = map[string]string{}
+
+// requestedOmissions - keep the methods which are not generated because they do not fit the members of their lists (see
+// gen.Omissions) and which are requested: with -methods, the method list of the type or the option selecting them, like
+// -chan. The methods comparing the members which cannot be compared according to the type-checking of the package are
+// kept too, since the type expression does not show it
+func requestedOmissions(omissions []gen.Omission) []gen.Omission {
+	optIn := map[string]bool{}
+	for _, method := range gen.Methods() {
+		optIn[method.Name] = method.OptIn != ""
+	}
+	result := []gen.Omission{}
+	for _, omission := range omissions {
+		_, typed := typeMethods[omission.List]
+		if *methods != "" || typed || optIn[omission.Method] || omission.TypeChecked {
+			result = append(result, omission)
+		}
+	}
+	return result
+}
+
+// skippedWarnings - describe the methods which are not generated because they do not fit the members of their lists,
+// by list and reason, eg: 'Contains, Unique are not generated for TaskList: the members of 'Task' cannot safely be
+// compared (its field 'Run' of type 'func()' cannot be compared)' or 'Sum is not generated for UserList: the members of
+// 'User' are not numbers'
+func skippedWarnings(omissions []gen.Omission) []string {
+	type group struct{ list, reason string }
+	groups := []group{}
+	grouped := map[group][]string{}
+	for _, omission := range omissions {
+		g := group{omission.List, omission.Reason}
+		if _, ok := grouped[g]; !ok {
+			groups = append(groups, g)
+		}
+		grouped[g] = append(grouped[g], omission.Method)
+	}
+	result := []string{}
+	for _, g := range groups {
+		result = append(result, fmt.Sprintf("%s not generated for %s: %s", methodNames(grouped[g]), g.list, g.reason))
+	}
+	return result
+}
+
+// incomparableWarnings - describe the methods needing keys which are not generated with the types of typeMap whose
+// members cannot be compared (see incomparableTypes), eg: 'UniqueBy is not generated with the keys of type 'Task': they
+// cannot safely be compared (its field 'Run' of type 'func()' cannot be compared)'
+func incomparableWarnings(typeMap, incomparable map[string]string, methodsMap map[string]bool) []string {
+	result := []string{}
+	keyed := map[string]bool{}
+	for _, typeName := range spec.SortedTypes(typeMap) {
+		selected := methodsOf(strings.TrimPrefix(typeMap[typeName], "*")+"List", methodsMap)
+		for _, method := range gen.Methods() {
+			if selected[method.Name] && method.Keyed {
+				keyed[method.Name] = true
+			}
+		}
+	}
+
+	keyedMethods := []string{}
+	for _, method := range gen.Methods() {
+		if keyed[method.Name] {
+			keyedMethods = append(keyedMethods, method.Name)
+		}
+	}
+	if len(keyedMethods) == 0 {
+		return result
+	}
+	for _, typeName := range spec.SortedTypes(typeMap) {
+		if reason := incomparable[typeName]; reason != "" {
+			result = append(result, fmt.Sprintf("%s not generated with the keys of type '%s': they cannot safely be compared (%s)", methodNames(keyedMethods), typeName, reason))
+		}
+	}
+	return result
+}
+
+// methodNames - join the names of methods, followed by 'is' or 'are', eg: 'Contains, Unique are'
+func methodNames(methods []string) string {
+	if len(methods) == 1 {
+		return methods[0] + " is"
+	}
+	return strings.Join(methods, ", ") + " are"
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+func TestIncomparableWarnings(t *testing.T) {
+	typeMap := map[string]string{"Task": "Task", "*Task": "TaskPtr", "int": "int"}
+	incomparable := map[string]string{"Task": "its field 'Run' of type 'func()' cannot be compared"}
+	methodsMap := map[string]bool{"Map": true, "Contains": true, "Unique": true, "UniqueBy": true}
+
+	warnings := incomparableWarnings(typeMap, incomparable, methodsMap)
+	expected := []string{
+		"UniqueBy is not generated with the keys of type 'Task': they cannot safely be compared (its field 'Run' of type 'func()' cannot be compared)",
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Error(warnings)
+	}
+
+	if warnings := incomparableWarnings(typeMap, incomparable, map[string]bool{"Contains": true}); len(warnings) != 0 {
+		t.Error(warnings)
+	}
+	if warnings := incomparableWarnings(typeMap, map[string]string{}, methodsMap); len(warnings) != 0 {
+		t.Error(warnings)
+	}
+}
+
+func TestSkippedWarnings(t *testing.T) {
+	spec := gen.Spec{
+		Package:      "models",
+		Types:        map[string]string{"Task": "Task", "*Task": "TaskPtr", "int": "int"},
+		Methods:      []string{"Map", "Contains", "Unique", "Sum"},
+		Incomparable: map[string]string{"Task": "its field 'Run' of type 'func()' cannot be compared"},
+	}
+	omissions, err := gen.Omissions(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*methods = "Map,Contains,Unique,Sum"
+	defer func() { *methods = "" }()
+	expected := []string{
+		"Sum is not generated for TaskPtrList: the members of '*Task' are not numbers",
+		"Contains, Unique are not generated for TaskList: the members of 'Task' cannot safely be compared (its field 'Run' of type 'func()' cannot be compared)",
+		"Sum is not generated for TaskList: the members of 'Task' are not numbers",
+	}
+	if warnings := skippedWarnings(requestedOmissions(omissions)); !reflect.DeepEqual(warnings, expected) {
+		t.Error(warnings)
+	}
+
+	// without -methods, only the methods left out by the type-checking are reported
+	*methods = ""
+	expected = []string{
+		"Contains, Unique are not generated for TaskList: the members of 'Task' cannot safely be compared (its field 'Run' of type 'func()' cannot be compared)",
+	}
+	if warnings := skippedWarnings(requestedOmissions(omissions)); !reflect.DeepEqual(warnings, expected) {
+		t.Error(warnings)
+	}
+
+	spec.Deref = true
+	omissions, _ = gen.Omissions(spec)
+	expected = []string{
+		"Contains, Unique are not generated for TaskPtrList: the members of 'Task' cannot safely be compared (its field 'Run' of type 'func()' cannot be compared)",
+		"Contains, Unique are not generated for TaskList: the members of 'Task' cannot safely be compared (its field 'Run' of type 'func()' cannot be compared)",
+	}
+	if warnings := skippedWarnings(requestedOmissions(omissions)); !reflect.DeepEqual(warnings, expected) {
+		t.Error(warnings)
+	}
+
+	spec.Deref = false
+	spec.Methods = []string{"Contains", "ToSet"}
+	spec.Equality = map[string]gen.Equality{"TaskList": {Eq: "sameTask"}}
+	omissions, _ = gen.Omissions(spec)
+	expected = []string{
+		"ToSet is not generated for TaskList: the members of 'Task' cannot safely be compared (its field 'Run' of type 'func()' cannot be compared)",
+	}
+	if warnings := skippedWarnings(requestedOmissions(omissions)); !reflect.DeepEqual(warnings, expected) {
+		t.Error(warnings)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+// completionShells - the shells which 'fungen completion' emits a completion script for
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// subcommands - the words which can replace the flags as the first argument of fungen
+var subcommands = []string{"completion", "list-methods", "migrate"}
+
+// methodListFlags - the flags whose values are comma-separated lists of methods
+var methodListFlags = map[string]bool{"methods": true, "exclude": true}
+
+// completionFlag - a flag as seen by the completion scripts
+type completionFlag struct {
+	name        string
+	description string
+	boolean     bool
+}
+
+// completionFlags - get the flags of fungen sorted by name, with the first sentence of their usage as description
+func completionFlags() []completionFlag {
+	result := []completionFlag{}
+	flag.VisitAll(func(f *flag.Flag) {
+		description := strings.TrimPrefix(f.Usage, "(Optional) ")
+		if end := strings.Index(description, ". "); end >= 0 {
+			description = description[:end]
+		}
+		result = append(result, completionFlag{f.Name, strings.TrimSuffix(description, "."), isBoolFlag(f.Name)})
+	})
+	sort.Slice(result, func(i, j int) bool { return result[i].name < result[j].name })
+	return result
+}
+
+// completionMethods - get the names of the methods which can be generated, sorted
+func completionMethods() []string {
+	result := []string{}
+	for _, method := range gen.Methods() {
+		result = append(result, method.Name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// completionScript - get the completion script of a shell, which completes the flags, the methods of -methods and
+// -exclude (after the commas too), the subcommands, and the files and directories
+func completionScript(shell string) (string, error) {
+	flags, methods := completionFlags(), completionMethods()
+	switch shell {
+	case "bash":
+		return bashCompletion(flags, methods), nil
+	case "zsh":
+		return zshCompletion(flags, methods), nil
+	case "fish":
+		return fishCompletion(flags, methods), nil
+	}
+	return "", fmt.Errorf("'%s' is not valid, the shells are %s", shell, strings.Join(completionShells, ", "))
+}
+
+// bashCompletion - get the completion script of bash. Since '=' separates the words of bash completion, '-methods=Map'
+// is completed like '-methods Map'
+func bashCompletion(flags []completionFlag, methods []string) string {
+	names, valueFlags := []string{}, []string{}
+	for _, f := range flags {
+		names = append(names, "-"+f.name)
+		if !f.boolean && !methodListFlags[f.name] {
+			valueFlags = append(valueFlags, "-"+f.name, "--"+f.name)
+		}
+	}
+
+	return fmt.Sprintf(`# bash completion for fungen, generated by 'fungen completion bash'
+
+_fungen() {
+	local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+	if [[ "$cur" == "=" ]]; then
+		cur=""
+	elif [[ "$prev" == "=" ]]; then
+		prev="${COMP_WORDS[COMP_CWORD-2]}"
+	fi
+
+	case "$prev" in
+	-methods|--methods|-exclude|--exclude)
+		local prefix=""
+		if [[ "$cur" == *,* ]]; then
+			prefix="${cur%%,*},"
+		fi
+		COMPREPLY=($(compgen -P "$prefix" -W "%[1]s" -- "${cur##*,}"))
+		return
+		;;
+	%[2]s)
+		COMPREPLY=($(compgen -f -- "$cur"))
+		return
+		;;
+	completion)
+		COMPREPLY=($(compgen -W "%[3]s" -- "$cur"))
+		return
+		;;
+	esac
+
+	if [[ "$cur" == -* ]]; then
+		COMPREPLY=($(compgen -W "%[4]s" -- "$cur"))
+	elif [[ $COMP_CWORD -eq 1 ]]; then
+		COMPREPLY=($(compgen -W "%[5]s" -- "$cur") $(compgen -d -- "$cur"))
+	else
+		COMPREPLY=($(compgen -d -- "$cur"))
+	fi
+}
+
+complete -F _fungen fungen
+`, strings.Join(methods, " "), strings.Join(valueFlags, "|"), strings.Join(completionShells, " "), strings.Join(names, " "), strings.Join(subcommands, " "))
+}
+
+// zshCompletion - get the completion script of zsh
+func zshCompletion(flags []completionFlag, methods []string) string {
+	specs := []string{}
+	for _, f := range flags {
+		description := strings.NewReplacer("[", "\\[", "]", "\\]", ":", "\\:", "'", "'\\''").Replace(f.description)
+		switch {
+		case f.boolean:
+			specs = append(specs, fmt.Sprintf("'-%s[%s]'", f.name, description))
+		case methodListFlags[f.name]:
+			specs = append(specs, fmt.Sprintf("'-%s=[%s]:methods:_values -s , method %s'", f.name, description, strings.Join(methods, " ")))
+		default:
+			specs = append(specs, fmt.Sprintf("'-%s=[%s]:value:_files'", f.name, description))
+		}
+	}
+
+	return fmt.Sprintf(`#compdef fungen
+# zsh completion for fungen, generated by 'fungen completion zsh'
+
+_fungen() {
+	if (( CURRENT == 3 )) && [[ "${words[2]}" == completion ]]; then
+		_values shell %[1]s
+		return
+	fi
+	_arguments \
+		%[2]s \
+		'1:: :(%[3]s)' \
+		'*:directory:_files -/'
+}
+
+compdef _fungen fungen
+`, strings.Join(completionShells, " "), strings.Join(specs, " \\\n\t\t"), strings.Join(subcommands, " "))
+}
+
+// fishCompletion - get the completion script of fish
+func fishCompletion(flags []completionFlag, methods []string) string {
+	lines := []string{}
+	for _, f := range flags {
+		description := strings.Replace(f.description, "'", "\\'", -1)
+		switch {
+		case f.boolean:
+			lines = append(lines, fmt.Sprintf("complete -c fungen -o %s -d '%s'", f.name, description))
+		case methodListFlags[f.name]:
+			lines = append(lines, fmt.Sprintf("complete -c fungen -o %s -x -a '(__fungen_methods)' -d '%s'", f.name, description))
+		default:
+			lines = append(lines, fmt.Sprintf("complete -c fungen -o %s -r -d '%s'", f.name, description))
+		}
+	}
+
+	return fmt.Sprintf(`# fish completion for fungen, generated by 'fungen completion fish'
+
+function __fungen_methods
+	set -l prefix (string replace -r '[^,=]*$' '' -- (commandline -ct))
+	for method in %[1]s
+		echo $prefix$method
+	end
+end
+
+complete -c fungen -n '__fish_use_subcommand' -a '%[2]s'
+complete -c fungen -n '__fish_seen_subcommand_from completion' -x -a '%[3]s'
+%[4]s
+`, strings.Join(methods, " "), strings.Join(subcommands, " "), strings.Join(completionShells, " "), strings.Join(lines, "\n"))
+}
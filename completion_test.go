@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletionFlags(t *testing.T) {
+	flags := completionFlags()
+	found := map[string]completionFlag{}
+	for i, f := range flags {
+		if i > 0 && flags[i-1].name >= f.name {
+			t.Fail()
+		}
+		found[f.name] = f
+	}
+	if !found["check"].boolean || found["methods"].boolean || found["methods"].description != "Comma-separated list of methods to generate, eg 'Map,Filter'" {
+		t.Error(found["check"], found["methods"])
+	}
+	if strings.HasPrefix(found["pointer"].description, "(Optional)") {
+		t.Error(found["pointer"])
+	}
+}
+
+func TestCompletionScript(t *testing.T) {
+	for _, shell := range completionShells {
+		script, err := completionScript(shell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range []string{"fungen completion " + shell, "methods", "PFilterMap", "list-methods", "zsh fish"} {
+			if !strings.Contains(script, expected) {
+				t.Errorf("the %s script does not contain '%s'", shell, expected)
+			}
+		}
+	}
+
+	script, _ := completionScript("bash")
+	if !strings.Contains(script, "-o|--o|") || strings.Contains(script, "|-check|") || !strings.Contains(script, "complete -F _fungen fungen") {
+		t.Error(script)
+	}
+
+	script, _ = completionScript("zsh")
+	if !strings.Contains(script, "'-check[Whether to only check that the generated files are up to date instead of writing them]'") {
+		t.Error(script)
+	}
+
+	script, _ = completionScript("fish")
+	if !strings.Contains(script, "complete -c fungen -o methods -x -a '(__fungen_methods)'") {
+		t.Error(script)
+	}
+
+	if _, err := completionScript("ksh"); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kulshekhar/fungen/internal/spec"
+)
+
+// defaultConfigName - the configuration file read when fungen is run without flags
+const defaultConfigName = "fungen.yaml"
+
+// readConfig - read the flags from a configuration file. Only a small subset of YAML is supported: every line is either
+// a 'key: value' pair, where the value is a scalar or an inline list like '[int, string:Str[Map,Filter]]', or a
+// '- item' line which adds an item to the list of the preceding key without a value. The keys are the names of the
+// flags and lists are joined with commas. Comments start with '#' (see stripComment). It also returns the line of every
+// key
+func readConfig(r io.Reader, filename string) (map[string]string, map[string]int, error) {
+	result := map[string]string{}
+	lines := map[string]int{}
+	lists := map[string][]string{}
+	listKey := ""
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "- ") || line == "-" {
+			if listKey == "" {
+				return nil, nil, fmt.Errorf("%s:%d: list item without a key", filename, lineNumber)
+			}
+			lists[listKey] = append(lists[listKey], unquote(strings.TrimSpace(line[1:])))
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("%s:%d: expected 'key: value', got '%s'", filename, lineNumber, line)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if _, ok := result[key]; ok {
+			return nil, nil, fmt.Errorf("%s:%d: duplicate key '%s'", filename, lineNumber, key)
+		}
+
+		listKey = ""
+		switch {
+		case value == "":
+			listKey = key
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			for _, item := range spec.Split(value[1 : len(value)-1]) {
+				if item = strings.TrimSpace(item); item != "" {
+					lists[key] = append(lists[key], unquote(item))
+				}
+			}
+		default:
+			value = unquote(value)
+		}
+		result[key] = value
+		lines[key] = lineNumber
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %s", filename, err)
+	}
+
+	for key, items := range lists {
+		result[key] = spec.Join(items)
+	}
+	return result, lines, nil
+}
+
+// stripComment - remove the comment of a line of a configuration file. Like in YAML, a comment starts with a '#' at
+// the start of the line or after a space or a tab, outside the quoted scalars, so that the '#' of 'suffix: "#"' or of
+// 'prefix: a#b' is kept. A quote only starts a quoted scalar at the start of a value or of an item, so that the quote
+// of 'prefix: it's' is kept too, and a quoted scalar ends with the same quote, not escaped with a '\' in double quotes
+// or doubled in single quotes
+func stripComment(line string) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\' || quote == '\'' && c == '\'' && i+1 < len(line) && line[i+1] == '\'':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t:[,", line[i-1]) >= 0):
+			quote = c
+		}
+	}
+	return line
+}
+
+// unquote - remove the quotes around a YAML scalar
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// loadConfig - read the configuration file (or the standard input, if filename is '-') and set the flags from it. Flags
+// given on the command line take precedence. The positions of the entries are recorded in flagPositions, so that the
+// errors about the flags set from the file give their lines
+func loadConfig(filename string) error {
+	var r io.Reader = os.Stdin
+	if filename == "-" {
+		filename = "<stdin>"
+	} else {
+		file, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	values, lines, err := readConfig(r, filename)
+	if err != nil {
+		return err
+	}
+
+	explicit := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	for key, value := range values {
+		position := fmt.Sprintf("%s:%d", filename, lines[key])
+		if key == "config" || flag.Lookup(key) == nil {
+			return fmt.Errorf("%s: unknown key '%s'", position, key)
+		}
+		if explicit[key] {
+			continue
+		}
+		if err := flag.Set(key, value); err != nil {
+			return fmt.Errorf("%s: invalid value '%s' for '%s': %s", position, value, key, err)
+		}
+		flagPositions[key] = position
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadConfig(t *testing.T) {
+	config := `
+# fungen configuration
+package: models
+filename: "lists_auto.go"   # quoted
+types:
+  - string
+  - 'int:I'
+methods: [Map, "Filter", PMap]
+chunked: true
+`
+	result, lines, err := readConfig(strings.NewReader(config), "fungen.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"package":  "models",
+		"filename": "lists_auto.go",
+		"types":    "string,int:I",
+		"methods":  "Map,Filter,PMap",
+		"chunked":  "true",
+	}
+
+	if len(result) != len(expected) {
+		t.Fail()
+	}
+	for key, value := range expected {
+		if result[key] != value {
+			t.Fail()
+		}
+	}
+	if lines["package"] != 3 || lines["types"] != 5 || lines["chunked"] != 9 {
+		t.Error(lines)
+	}
+}
+
+func TestReadConfigMethodLists(t *testing.T) {
+	result, _, err := readConfig(strings.NewReader("types: [int:I[Map,Filter], string]\n"), "fungen.yaml")
+	if err != nil || result["types"] != "int:I[Map,Filter],string" {
+		t.Fatal(err, result)
+	}
+}
+
+func TestReadConfigComments(t *testing.T) {
+	config := `
+suffix: "#"  # quoted
+prefix: a#b
+filename: 'it''s # kept.go' # comment
+tags: "a \" # b" # comment
+types: ["#", it's, '# x'] # comment
+#methods: Map
+`
+	result, _, err := readConfig(strings.NewReader(config), "fungen.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"suffix":   "#",
+		"prefix":   "a#b",
+		"filename": "it''s # kept.go",
+		"tags":     `a \" # b`,
+		"types":    "#,it's,# x",
+	}
+	if len(result) != len(expected) {
+		t.Error(result)
+	}
+	for key, value := range expected {
+		if result[key] != value {
+			t.Errorf("%s: got '%s', expected '%s'", key, result[key], value)
+		}
+	}
+}
+
+func TestReadConfigErrors(t *testing.T) {
+	configs := map[string]string{
+		"package: models\n- string\n":        "fungen.yaml:2: list item without a key",
+		"package: models\ntypes\n":           "fungen.yaml:2: expected 'key: value', got 'types'",
+		"package: models\npackage: models\n": "fungen.yaml:2: duplicate key 'package'",
+	}
+
+	for config, expected := range configs {
+		_, _, err := readConfig(strings.NewReader(config), "fungen.yaml")
+		if err == nil || err.Error() != expected {
+			t.Fail()
+		}
+	}
+}
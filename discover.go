@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	gotypes "go/types"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// skipAnnotation - the annotation in the doc comment of a list type which excludes it from -discover
+const skipAnnotation = "fungen:skip"
+
+// declaredLists - the list types declared in the package itself, found by -discover. The generated code only adds
+// methods to them, without declaring them again
+var declaredLists = map[string]bool{}
+
+// discoverTypes - find the list types like 'type userList []User' in the Go files of a directory, except the test
+// files, the files generated by fungen and the types annotated with 'fungen:skip'. The element types are resolved by
+// type-checking the package (see listElement), so that 'type userList users' and 'type userList = users', where
+// 'type users []User' or 'type users = []User', are found like 'type userList []User'. It returns a type map like
+// the Lists of spec.Types, eg: 'User' -> 'user', the name of the package, and warnings for the list types which are
+// skipped because their methods cannot be declared, like the aliases of unnamed slice types
+func discoverTypes(dir string) (map[string]string, string, []string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	pkg := ""
+	fset := token.NewFileSet()
+	parsed := []*ast.File{}
+	specs := []*ast.TypeSpec{}
+	candidates := map[string]bool{}
+	for _, info := range files {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if generatedByFungen(file) {
+			continue
+		}
+		pkg = file.Name.Name
+		parsed = append(parsed, file)
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				listName := typeSpec.Name.Name
+				if typeSpec.TypeParams != nil || !strings.HasSuffix(listName, "List") || listName == "List" {
+					continue
+				}
+				if skipped(genDecl.Doc) || skipped(typeSpec.Doc) || skipped(typeSpec.Comment) {
+					continue
+				}
+				specs = append(specs, typeSpec)
+				candidates[listName] = true
+			}
+		}
+	}
+
+	info := &gotypes.Info{Defs: map[*ast.Ident]gotypes.Object{}}
+	config := gotypes.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		// the errors leave the types which cannot be checked invalid, which are then read from the declarations
+		Error: func(error) {},
+	}
+	config.Check(pkg, fset, parsed, info)
+
+	m := map[string]string{}
+	lists := map[string]string{}
+	warnings := []string{}
+	aliased := map[string]bool{}
+	for _, typeSpec := range specs {
+		listName := typeSpec.Name.Name
+		obj, _ := info.Defs[typeSpec.Name].(*gotypes.TypeName)
+		typeName, target, ok := "", "", false
+		if obj != nil {
+			typeName, target, ok = listElement(obj)
+		}
+		if !ok && target != "" {
+			warnings = append(warnings, fmt.Sprintf("%s is skipped: it is an alias of '%s', whose methods cannot be declared in the package, declare it as 'type %s %s' instead", listName, target, listName, target))
+			continue
+		}
+		if !ok || strings.Contains(typeName, "invalid type") {
+			// the type could not be checked, eg: because an import cannot be found
+			typeName, ok, err = declaredElement(fset, typeSpec)
+			if err != nil {
+				return nil, "", nil, err
+			}
+		}
+		if !ok || candidates[target] || aliased[target] {
+			// the aliases of the other list types of the package, and of the named types of other aliases, are the same
+			// lists
+			continue
+		}
+		if target != "" {
+			aliased[target] = true
+		}
+
+		if other, ok := lists[typeName]; ok {
+			return nil, "", nil, fmt.Errorf("%s and %s both hold members of type %s", other, listName, typeName)
+		}
+		lists[typeName] = listName
+		m[typeName] = strings.TrimSuffix(listName, "List")
+	}
+	return m, pkg, warnings, nil
+}
+
+// listElement - get the element type of a list type of the package whose underlying type is a slice, through the
+// aliases, eg: 'string' for 'type idList []ID' where 'type ID = string', with the types of other packages qualified
+// with their import path, eg: 'github.com/user/app/model.User'. The aliases are lists too when they are aliases of the
+// named slice types of the package, whose methods can be declared with the alias, and target is then the name of the
+// named type. It returns false, with the type in target, for the aliases of the other slice types, eg: '[]string'
+func listElement(obj *gotypes.TypeName) (string, string, bool) {
+	qualifier := func(p *gotypes.Package) string {
+		if p == obj.Pkg() {
+			return ""
+		}
+		return p.Path()
+	}
+
+	t, target := obj.Type(), ""
+	if obj.IsAlias() {
+		t = gotypes.Unalias(t)
+		target = gotypes.TypeString(t, qualifier)
+		if named, ok := t.(*gotypes.Named); !ok || named.Obj().Pkg() != obj.Pkg() || named.TypeArgs().Len() > 0 {
+			if _, ok := t.Underlying().(*gotypes.Slice); !ok {
+				target = ""
+			}
+			return "", target, false
+		}
+	}
+	slice, ok := t.Underlying().(*gotypes.Slice)
+	if !ok {
+		return "", "", false
+	}
+	return gotypes.TypeString(unaliased(slice.Elem()), qualifier), target, true
+}
+
+// unaliased - get a type with the aliases it refers to replaced by the types they denote, eg: '*string' for '*ID' where
+// 'type ID = string'
+func unaliased(t gotypes.Type) gotypes.Type {
+	switch u := gotypes.Unalias(t).(type) {
+	case *gotypes.Pointer:
+		return gotypes.NewPointer(unaliased(u.Elem()))
+	case *gotypes.Slice:
+		return gotypes.NewSlice(unaliased(u.Elem()))
+	case *gotypes.Map:
+		return gotypes.NewMap(unaliased(u.Key()), unaliased(u.Elem()))
+	default:
+		return u
+	}
+}
+
+// declaredElement - get the element type of a list type as it is written in its declaration, eg: 'model.User' for
+// 'type userList []model.User', or false if it is not declared with a slice type
+func declaredElement(fset *token.FileSet, typeSpec *ast.TypeSpec) (string, bool, error) {
+	slice, ok := typeSpec.Type.(*ast.ArrayType)
+	if !ok || slice.Len != nil || typeSpec.Assign.IsValid() {
+		return "", false, nil
+	}
+	var typeName bytes.Buffer
+	if err := printer.Fprint(&typeName, fset, slice.Elt); err != nil {
+		return "", false, err
+	}
+	return typeName.String(), true, nil
+}
+
+// fungenHeader - the header line of the files generated by fungen, and the one of the files generated by its older
+// versions, eg: '// Package models - generated by fungen; DO NOT EDIT'
+var fungenHeader = regexp.MustCompile(`^// Code generated by fungen; DO NOT EDIT\.$|^// Package \w+ - generated by fungen; DO NOT EDIT$`)
+
+// generatedByFungen - whether a line of the header of a file, before the package clause, is the header line of fungen
+// (see fungenHeader). The other comments mentioning fungen, like a package comment written by hand, do not count
+func generatedByFungen(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if fungenHeader.MatchString(comment.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// skipped - whether a comment contains the skip annotation
+func skipped(comment *ast.CommentGroup) bool {
+	return comment != nil && strings.Contains(comment.Text(), skipAnnotation)
+}
+
+// addDiscoveredTypes - add the discovered types to the type map, and mark their lists as declared
+func addDiscoveredTypes(typeMap, discovered map[string]string) error {
+	keys := []string{}
+	for typeName := range discovered {
+		keys = append(keys, typeName)
+	}
+	sort.Strings(keys)
+
+	for _, typeName := range keys {
+		if name, ok := typeMap[typeName]; ok && name != discovered[typeName] {
+			return fmt.Errorf("type '%s' is given with -types as '%s' but is declared as %sList", typeName, name, discovered[typeName])
+		}
+		typeMap[typeName] = discovered[typeName]
+		declaredLists[discovered[typeName]+"List"] = true
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiscoverTypes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"models.go": `package models
+
+import "time"
+
+type User struct{}
+
+type ID = string
+
+type counts []int64
+
+type userList []User
+
+type (
+	// fungen:skip
+	nameList []string
+	ptrList  []*User
+	Users    []User
+	arrayList [4]int
+	aliasList = []int
+	idList    []ID
+	countList = counts
+	usersList = userList
+	durationList []time.Duration
+	structList struct{}
+)
+`,
+		"models_test.go": "package models\n\ntype testList []int\n",
+		"fungen_auto.go": "// Code generated by fungen; DO NOT EDIT.\n\npackage models\n\ntype intList []int\n",
+		"notes.txt":      "type textList []int\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, pkg, warnings, err := discoverTypes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"User": "user", "*User": "ptr", "string": "id", "int64": "count", "time.Duration": "duration"}
+	if pkg != "models" || !reflect.DeepEqual(result, expected) {
+		t.Error(result)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "aliasList is skipped: it is an alias of '[]int'") {
+		t.Error(warnings)
+	}
+
+	typeMap := map[string]string{"int": "int"}
+	if addDiscoveredTypes(typeMap, result) != nil || len(typeMap) != 6 || !declaredLists["userList"] || !declaredLists["countList"] || declaredLists["intList"] {
+		t.Fail()
+	}
+	for _, name := range expected {
+		delete(declaredLists, name+"List")
+	}
+
+	if addDiscoveredTypes(map[string]string{"User": "U"}, result) == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+// the exit statuses of fungen, so that the scripts and the build tools running it can tell the errors apart
+const (
+	// exitFailure - the generation failed, or -check found stale files
+	exitFailure = 1
+	// exitParse - a flag, a go:generate directive, an entry of the configuration file or a file read by fungen, like
+	// the -manifest, is not valid. The flag package exits with the same status
+	exitParse = 2
+	// exitIO - a file or a directory cannot be read or written
+	exitIO = 3
+	// exitType - an element type or a method does not fit the package, a requested method does not fit its element type
+	// with -strict, or the generated code does not compile
+	exitType = 4
+)
+
+// flagPositions - where the flags set from the configuration file are given, by flag name, eg: 'fungen.yaml:3'
+var flagPositions = map[string]string{}
+
+// directivePosition - get the position of the go:generate directive running fungen, eg: 'users.go:12', from the
+// $GOFILE and $GOLINE set by go generate, or by 'fungen ./...'. It is empty outside of go generate
+func directivePosition() string {
+	file, line := os.Getenv("GOFILE"), os.Getenv("GOLINE")
+	if file == "" || line == "" || line == "0" {
+		return ""
+	}
+	return file + ":" + line
+}
+
+// positionOf - get where a flag is given: its entry in the configuration file, or the go:generate directive. The
+// errors which are not about a single flag, named "", are at the go:generate directive
+func positionOf(name string) string {
+	if position, ok := flagPositions[name]; ok {
+		return position
+	}
+	return directivePosition()
+}
+
+// flagMessage - prefix an error about a flag with where the flag is given, eg: "users.go:12: unknown method 'Fliter' in
+// -methods", so that the malformed directive or configuration entry can be found
+func flagMessage(name, format string, args ...interface{}) string {
+	message := fmt.Sprintf(format, args...)
+	if position := positionOf(name); position != "" {
+		return position + ": " + message
+	}
+	return message
+}
+
+// failf - report an error and exit with the status
+func failf(status int, format string, args ...interface{}) {
+	log.Printf("Error: "+format, args...)
+	os.Exit(status)
+}
+
+// flagFailf - report an error about a flag with where the flag is given (see flagMessage), and exit with the status
+func flagFailf(name string, status int, format string, args ...interface{}) {
+	failf(status, "%s", flagMessage(name, format, args...))
+}
+
+// statusOf - get the exit status of an error: exitIO if a file cannot be read or written, or else the status given
+func statusOf(err error, status int) int {
+	var pathError *os.PathError
+	if errors.As(err, &pathError) {
+		return exitIO
+	}
+	return status
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestFlagMessage(t *testing.T) {
+	t.Setenv("GOFILE", "")
+	t.Setenv("GOLINE", "")
+	if message := flagMessage("methods", "unknown method '%s' in -methods", "Fliter"); message != "unknown method 'Fliter' in -methods" {
+		t.Error(message)
+	}
+
+	t.Setenv("GOFILE", "users.go")
+	t.Setenv("GOLINE", "12")
+	if message := flagMessage("methods", "unknown method '%s' in -methods", "Fliter"); message != "users.go:12: unknown method 'Fliter' in -methods" {
+		t.Error(message)
+	}
+
+	flagPositions["methods"] = "fungen.yaml:3"
+	defer delete(flagPositions, "methods")
+	if position := positionOf("methods"); position != "fungen.yaml:3" {
+		t.Error(position)
+	}
+	if position := positionOf("types"); position != "users.go:12" {
+		t.Error(position)
+	}
+
+	t.Setenv("GOLINE", "0")
+	if position := directivePosition(); position != "" {
+		t.Error(position)
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	_, err := ioutil.ReadFile("testdata/missing.yaml")
+	if status := statusOf(fmt.Errorf("-manifest parameter %w", err), exitParse); status != exitIO {
+		t.Error(status)
+	}
+	if status := statusOf(fmt.Errorf("not valid"), exitParse); status != exitParse {
+		t.Error(status)
+	}
+}
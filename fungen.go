@@ -3,105 +3,89 @@ package main
 import (
 	"flag"
 	"fmt"
-	"go/format"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
-)
-
-//go:generate $GOPATH/bin/fungen -types "Generator" -methods Filter,Each
+	"sync"
+	"time"
 
-// Generator - one generator (function and information about generate)
-type Generator struct {
-	name         string
-	method       func(_, _, _, _ string) string
-	needSync     bool
-	needMapToMap bool
-}
+	"github.com/kulshekhar/fungen/gen"
+	"github.com/kulshekhar/fungen/internal/spec"
+	"github.com/kulshekhar/fungen/internal/write"
+)
 
 var (
-	packageName = flag.String("package", "main", "(Optional) Name of the package.")
-	types       = flag.String("types", "", "Comma-separated list of type names, eg. 'int,string,CustomType'. The values can themselves be colon (:) separated to specify the names of entities in the generated, eg: int:I,string:Str,CustomType:CT.")
-	methods     = flag.String("methods", "", "Comma-separated list of methods to generate, eg 'Map,Filter'. By default generate all methods.")
-	outputName  = flag.String("filename", "fungen_auto.go", "(Optional) Filename for generated package.")
-	testrun     = flag.Bool("test", false, "whether to display the generated code instead of writing out to a file.")
-	generators  = GeneratorList{
-		{
-			name:         "Map",
-			method:       getMapFunction,
-			needSync:     false,
-			needMapToMap: true,
-		},
-		{
-			name:         "PMap",
-			method:       getPMapFunction,
-			needSync:     true,
-			needMapToMap: true,
-		},
-		{
-			name:     "Filter",
-			method:   getFilterFunction,
-			needSync: false,
-		},
-		{
-			name:     "PFilter",
-			method:   getPFilterFunction,
-			needSync: true,
-		},
-		{
-			name:   "Reduce",
-			method: getReduceFunction,
-		},
-		{
-			name:   "ReduceRight",
-			method: getReduceRightFunction,
-		},
-		{
-			name:   "Take",
-			method: getTakeFunction,
-		},
-		{
-			name:   "TakeWhile",
-			method: getTakeWhileFunction,
-		},
-		{
-			name:   "Drop",
-			method: getDropFunction,
-		},
-		{
-			name:   "DropWhile",
-			method: getDropWhileFunction,
-		},
-		{
-			name:   "Each",
-			method: getEachFunction,
-		},
-		{
-			name:   "EachI",
-			method: getEachIFunction,
-		},
-		{
-			name:   "All",
-			method: getAllFunction,
-		},
-		{
-			name:   "Any",
-			method: getAnyFunction,
-		},
-		{
-			name:         "FilterMap",
-			method:       getFilterMapFunction,
-			needSync:     false,
-			needMapToMap: true,
-		},
-		{
-			name:         "PFilterMap",
-			method:       getPFilterMapFunction,
-			needSync:     true,
-			needMapToMap: true,
-		},
-	}
+	configName    = flag.String("config", "", "(Optional) Configuration file to read the flags from. By default "+defaultConfigName+" is read if fungen is run without flags and the file exists. '-' reads the configuration from the standard input.")
+	packageName   = flag.String("package", "", "(Optional) Name of the package. By default the package of the file containing the go:generate directive ($GOPACKAGE) is used, or 'main' outside of go generate.")
+	discover      = flag.Bool("discover", false, "(Optional) Whether to also generate the methods for the list types declared in the package, like 'type userList []User'. The types whose doc comment contains '"+skipAnnotation+"' are skipped.")
+	types         = flag.String("types", "", "Comma-separated list of type names, eg. 'int,string,CustomType'. The values can themselves be colon (:) separated to specify the names of entities in the generated, eg: int:I,string:Str,CustomType:CT. A type can be followed by '->' and the types its list maps to, up to the next semicolon, eg: 'User -> string,int;bool'.")
+	pointerLists  = flag.Bool("pointer", false, "(Optional) Whether to generate the methods with pointer receivers, eg 'func (lp *stringList) Filter(...)'. Filter, PFilter, Take, Drop, TakeWhile, DropWhile, PSort, Unique, CompactNil, NonEmpty and FilterInPlace then replace the list by their result.")
+	exportLists   = flag.Bool("export", false, "(Optional) Whether the list types named after their element type are exported, eg 'StringList' instead of 'stringList' for 'string'. The names given with 'type:Name' are used as they are.")
+	declareLists  = flag.Bool("declare", true, "(Optional) Whether to declare the list types. With -declare=false the list types are assumed to be declared in the package already and only the methods are generated.")
+	methods       = flag.String("methods", "", "Comma-separated list of methods to generate, eg 'Map,Filter'. By default generate all methods.")
+	exclude       = flag.String("exclude", "", "(Optional) Comma-separated list of methods not to generate, eg 'PFilter,PMap'.")
+	outputName    = flag.String("filename", "fungen_auto.go", "(Optional) Filename for generated package.")
+	outputDir     = flag.String("outdir", "", "(Optional) Directory to write the generated files to, created if needed, to keep the generated code in its own package. By default the package is named after the directory, and the element types of other packages are given with their import path, eg 'github.com/user/app/model.User:User'.")
+	layout        = flag.String("layout", "file", "(Optional) How the generated code is split into files. 'file' writes it into a single file, 'method' writes each method of each list into its own file next to it, eg 'stringlist_filter.go', and only the declarations shared by the methods, like the list types, into the file.")
+	outputPattern = flag.String("o", "", "(Optional) Filename for generated package, overriding -filename. If it contains '{type}', eg '{type}_fungen.go', a file is generated for each type with '{type}' replaced by the name of the type. '-' writes the generated code to the standard output.")
+	methodPrefix  = flag.String("prefix", "", "(Optional) Prefix added to the names of the generated methods, eg 'F' generates 'FMap', 'FFilter', ...")
+	methodSuffix  = flag.String("suffix", "", "(Optional) Suffix added to the names of the generated methods, eg 'F' generates 'MapF', 'FilterF', ...")
+	clean         = flag.Bool("clean", false, "(Optional) Whether to remove the files generated by fungen from the given directories or patterns like './...' (or the current directory and the -outdir) instead of generating, eg. to remove the files of renamed types. With -test the files are only listed.")
+	check         = flag.Bool("check", false, "(Optional) Whether to only check that the generated files are up to date instead of writing them. The stale files and types are reported and the exit status is 1 if any file is stale.")
+	verbose       = flag.Bool("v", false, "(Optional) Whether to report every type, method and file generated, with timing.")
+	quiet         = flag.Bool("q", false, "(Optional) Whether to only report errors.")
+	listOnly      = flag.Bool("list", false, "(Optional) Print the methods which can be generated, with their signatures and descriptions, and exit. Same as 'fungen list-methods'.")
+	printVersion  = flag.Bool("version", false, "(Optional) Print the version of fungen and exit.")
+	buildTags     = flag.String("tags", "", "(Optional) Build constraint written as a //go:build line into the generated files, eg 'linux && !prod'.")
+	headerFile    = flag.String("header-file", "", "(Optional) File whose contents, eg a license block, are prepended to every generated file. It must only contain comments.")
+	templatesDir  = flag.String("templates", "", "(Optional) Directory with text/template files (eg: 'Filter.tmpl') overriding the templates of the built-in methods or adding extra methods.")
+	plugin        = flag.String("plugin", "", "(Optional) Command which receives the generation plan of every generated file as JSON on its standard input and writes extra code to append to the file to its standard output.")
+	reportFormat  = flag.String("report", "", "(Optional) Format of a summary of the run (types processed, methods emitted, files written, bytes, duration, warnings) to write to the -report-file. The only format is 'json'.")
+	reportOutput  = flag.String("report-file", "-", "(Optional) File to write the -report to. '-' writes it to the standard output.")
+	skipExisting  = flag.Bool("skip-existing", false, "(Optional) Whether to skip, with a warning, the generated methods which are already declared on the list types in the other files of the package, instead of failing.")
+	strict        = flag.Bool("strict", false, "(Optional) Whether to fail, with the exit status 4, when requested methods are not generated because they do not fit the element types, eg: 'Sum' for a list of structs, instead of skipping them with a warning.")
+	manifestFile  = flag.String("manifest", "", "(Optional) File recording the SHA-256 hashes of the generated files, in the format of sha256sum, to warn when a generated file was changed since it was generated.")
+	typeCheck     = flag.Bool("typecheck", true, "(Optional) Whether to check that the element types of other packages exist, to leave out the methods comparing the members for the element types which cannot be compared, with a warning, to recognize the named types of numbers and strings for the methods ordering the members, and to type-check the generated code together with the other files of the package before writing it, and fail with the errors in the generated code.")
+	testrun       = flag.Bool("test", false, "whether to display the generated code instead of writing out to a file.")
+	chanStages    = flag.Bool("chan", false, "(Optional) Whether to also generate the channel pipeline stages (MapChan, FilterChan) for the types.")
+	withTests     = flag.Bool("with-tests", false, "(Optional) Whether to also generate a _test.go file with table-driven tests of the generated methods, so that the generated code is covered by the tests of the package.")
+	withFuzz      = flag.Bool("with-fuzz", false, "(Optional) Whether to also generate a _fuzz_test.go file with fuzz tests of the invariants between the generated methods, eg: that Take and Drop split the list, for the lists of the numbers, the strings and the bools.")
+	withProps     = flag.Bool("with-properties", false, "(Optional) Whether to also generate a _property_test.go file with testing/quick property tests of the laws of the generated methods, eg: that Map with the composition of two functions returns the same list as Map with each of them, for the lists of the numbers, the strings and the bools.")
+	docFile       = flag.Bool("doc", false, "(Optional) Whether to also generate a doc.go file in the directory of the output with the package comment listing the generated types, their methods and the generated functions, so that godoc readers get an index of the generated API.")
+	withExamples  = flag.Bool("with-examples", false, "(Optional) Whether to also generate a _example_test.go file with an example of every generated method, so that godoc shows how to use them.")
+	benchmarks    = flag.Bool("bench", false, "(Optional) Whether to also generate a _bench_test.go file that benchmarks the parallel methods against their serial counterparts.")
+	sets          = flag.Bool("set", false, "(Optional) Whether to also generate the set type (eg: 'intSet') for the types whose members can be compared, with the ToSet method of the list.")
+	pairs         = flag.Bool("pair", false, "(Optional) Whether to also generate the pair types of the members of the lists with the members of the other lists (eg: 'stringIntPair', with the First and Second fields, and its list 'stringIntPairList'), with the Zip methods of the lists (eg: 'ZipInt').")
+	safeLists     = flag.Bool("safe", false, "(Optional) Whether to also generate the thread-safe wrapper of the lists (eg: 'safeIntList', embedding a sync.RWMutex), with the methods of the list locking around every call, and Snapshot and ReplaceAll, so that several goroutines can share a list.")
+	concurrent    = flag.Bool("concurrent", false, "(Optional) Whether to also generate the concurrent variant of the map types (eg: 'concurrentUserIndex' for 'userIndex:map[string]User'), backed by a sync.Map, with the typed Load, Store, Delete, ComputeIfAbsent, Range, Keys and Values methods, and the ToConcurrent method of the map.")
+	stacks        = flag.Bool("stack", false, "(Optional) Whether to also generate the stack type (eg: 'intStack', with Push, Pop and Peek) for the types, with the ToStack method of the list.")
+	queues        = flag.Bool("queue", false, "(Optional) Whether to also generate the queue type (eg: 'intQueue', with Enqueue, Dequeue and Peek) for the types, with the ToQueue method of the list.")
+	deques        = flag.Bool("deque", false, "(Optional) Whether to also generate the double-ended queue type (eg: 'intDeque', with PushFront, PushBack, PopFront and PopBack) for the types, with the ToDeque method of the list.")
+	immutable     = flag.Bool("immutable", false, "(Optional) Whether to also generate the immutable list type (eg: 'intImmutableList', with Append, Insert, Remove and Set returning new lists) for the types, with the ToImmutable method of the list.")
+	sortedLists   = flag.Bool("sorted", false, "(Optional) Whether to also generate the sorted list type (eg: 'sortedIntList', kept sorted by the less function given to ToSorted, with Insert, Remove, Contains and Range) for the types, with the ToSorted method of the list.")
+	inPlace       = flag.Bool("inplace", false, "(Optional) Whether to also generate the in-place methods of the lists (FilterInPlace, MapInPlace, ReverseInPlace and SortInPlace), which mutate the list instead of allocating a new one.")
+	options       = flag.Bool("option", false, "(Optional) Whether to also generate the Find, First and Last methods of the lists, returning an option type (eg: 'intOption', with IsSome, Get, GetOr and Map).")
+	results       = flag.Bool("result", false, "(Optional) Whether to also generate the MapResult methods of the lists, returning a result type (eg: 'intResult', with Map, AndThen and UnwrapOr) for every member.")
+	iterators     = flag.Bool("iter", false, "(Optional) Whether to also generate the lazy iterator type (eg: 'intIter', with Next, Map, Filter and Take) for the types, with the Iter and CollectFromIter methods of the list.")
+	seqs          = flag.Bool("seq", false, "(Optional) Whether to also generate the Values and Enumerated methods of the lists, returning an iter.Seq and an iter.Seq2, and the FromSeq functions (eg: 'intListFromSeq'). The generated code then needs Go 1.23 or later.")
+	pipelines     = flag.Bool("pipeline", false, "(Optional) Whether to also generate the lazy pipeline type (eg: 'intListPipeline') for the types.")
+	pooled        = flag.Bool("pool", false, "(Optional) Whether the parallel methods should accept an optional pool of goroutines (eg: '*intListPool') to reuse instead of starting new goroutines.")
+	pluck         = flag.String("pluck", "", "(Optional) Comma-separated list of the struct element types whose exported fields get a Pluck method on their lists, eg 'User' generates 'PluckName() stringList', 'PluckAge() intList', ..., or of single fields, eg 'User.Name,User.Age'. The lists of the types of the fields are generated too.")
+	deref         = flag.Bool("deref", false, "(Optional) Whether the Contains and Unique methods of the lists of pointers (eg: '*User') compare the values the members point to instead of the pointers.")
+	generics      = flag.Bool("generics", false, "(Optional) Whether the methods which have a generic function (Map, Filter, Reduce, ...) should be thin methods calling it, instead of being generated for every type. The generic functions are written to a file of their own, eg: 'fungen_auto_generics.go', and need Go 1.18 or later.")
+	prealloc      = flag.Bool("prealloc", true, "(Optional) Whether Filter and FilterMap allocate their result with the capacity of the list, so that it is not reallocated as it grows. With -prealloc=false the result grows from an empty list, using the least memory when few members are kept.")
+	twoPass       = flag.Bool("twopass", false, "(Optional) Whether Filter counts the members it keeps before copying them to a result of their exact size, using the least memory for the large lists keeping few members. It overrides -prealloc for Filter.")
+	copyResults   = flag.Bool("copy", false, "(Optional) Whether Take, TakeWhile, Drop and DropWhile return a copy of the members they keep, which can be appended to without changing the list, instead of a slice of the list sharing its backing array.")
+	chunked       = flag.Bool("chunked", false, "(Optional) Whether the parallel methods should split the list into runtime.NumCPU() chunks and process each chunk in a single goroutine instead of starting one goroutine per member.")
 )
 
 func usage() {
@@ -112,457 +96,976 @@ func usage() {
 	fmt.Fprintf(os.Stderr, "'fungen -types string,int:I,customType:CT,AnotherType:At' will create types 'stringList []string, IList []int, CTList []customType, AtList []AnotherType'. The 'stringList' type will have the Map, Filter, Reduce, ReduceRight, Take, TakeWhile, Drop, DropWhile, Each, EachI methods on it. Additionally, it will also have MapI, MapCt and MapAt methods. The package of the generated file will be 'main' \n\n")
 	fmt.Fprintf(os.Stderr, "'fungen -methods Map,Filter -types int' will create types 'intList []int' with the Map, Filter methods on them.\n\n")
 
+	fmt.Fprintf(os.Stderr, "'fungen ./...' runs the fungen go:generate directives (or the %s files) of all the packages in the current directory and its subdirectories, in parallel.\n\n", defaultConfigName)
+	fmt.Fprintf(os.Stderr, "'fungen -clean ./...' removes the files generated by fungen in all the packages in the current directory and its subdirectories.\n\n")
+	fmt.Fprintf(os.Stderr, "'fungen list-methods' prints the methods which can be generated, with their signatures and descriptions.\n\n")
+	fmt.Fprintf(os.Stderr, "'fungen migrate ./...' rewrites the calls of the generated methods which have a generic function, eg: 'l.Map(f)', to calls of the generic functions of -generics, eg: 'Map(l, f)', in all the packages in the current directory and its subdirectories.\n\n")
+	fmt.Fprintf(os.Stderr, "'fungen completion bash|zsh|fish' prints the completion script of the shell, eg: 'source <(fungen completion bash)'.\n\n")
+
 	fmt.Fprintf(os.Stderr, "Flags:\n")
 	flag.PrintDefaults()
 }
 
+// parseFlags - parse the command line. Under go generate, a flag which is not valid is reported at the go:generate
+// directive, eg: "users.go:12: flag provided but not defined: -method", instead of with the whole usage
+func parseFlags() {
+	position := directivePosition()
+	if position == "" {
+		flag.Parse()
+		return
+	}
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	flag.CommandLine.Usage = func() {}
+	flag.CommandLine.SetOutput(ioutil.Discard)
+	err := flag.CommandLine.Parse(os.Args[1:])
+	flag.CommandLine.SetOutput(nil)
+	if err == flag.ErrHelp {
+		usage()
+		os.Exit(0)
+	}
+	if err != nil {
+		failf(exitParse, "%s: %s, see 'fungen -h'", position, err)
+	}
+}
+
 func main() {
+	start := time.Now()
 	flag.Usage = usage
-	flag.Parse()
+	parseFlags()
+
+	if *printVersion {
+		fmt.Println("fungen", fungenVersion)
+		return
+	}
+
+	if *templatesDir != "" {
+		var err error
+		if templates, err = gen.LoadTemplates(*templatesDir); err != nil {
+			flagFailf("templates", statusOf(err, exitParse), "-templates parameter %s", err)
+		}
+	}
+
+	if *listOnly || flag.Arg(0) == "list-methods" {
+		fmt.Print(gen.ListMethods(*chunked, *pooled, templates))
+		return
+	}
+
+	if flag.Arg(0) == "completion" {
+		script, err := completionScript(flag.Arg(1))
+		if err != nil {
+			failf(exitParse, "completion parameter %s", err)
+		}
+		fmt.Print(script)
+		return
+	}
+
+	if flag.Arg(0) == "migrate" {
+		patterns := flag.Args()[1:]
+		if len(patterns) == 0 {
+			patterns = []string{"."}
+		}
+		for _, arg := range patterns {
+			if !isDirectoryPattern(arg) {
+				failf(exitParse, "migrate parameter '%s' is not a directory or a pattern like './...'", arg)
+			}
+		}
+		migrated, err := migrateDirectories(patterns)
+		if err != nil {
+			failf(statusOf(err, exitParse), "migrate parameter %s", err)
+		}
+		filenames := []string{}
+		for filename := range migrated {
+			filenames = append(filenames, filename)
+		}
+		sort.Strings(filenames)
+		for _, filename := range filenames {
+			file := migrated[filename]
+			if *check {
+				infof("%s has %d calls to migrate", filename, file.calls)
+				continue
+			}
+			if err := ioutil.WriteFile(filename, []byte(file.src), 0644); err != nil {
+				failf(exitIO, "%s", err)
+			}
+			infof("migrated %d calls in %s", file.calls, filename)
+		}
+		if *check && len(filenames) > 0 {
+			os.Exit(exitFailure)
+		}
+		return
+	}
 
-	if len(*types) == 0 {
+	if *reportFormat != "" && *reportFormat != "json" {
+		flagFailf("report", exitParse, "-report parameter '%s' is not valid, the only format is 'json'", *reportFormat)
+	}
+
+	if *clean {
+		patterns := flag.Args()
+		if len(patterns) == 0 {
+			patterns = []string{"."}
+			if *outputDir != "" {
+				patterns = append(patterns, *outputDir)
+			}
+		}
+		for _, arg := range patterns {
+			if !isDirectoryPattern(arg) {
+				failf(exitParse, "-clean parameter '%s' is not a directory or a pattern like './...'", arg)
+			}
+		}
+		files, err := findGeneratedFiles(patterns)
+		if err != nil {
+			failf(statusOf(err, exitParse), "-clean parameter %s", err)
+		}
+		for _, file := range files {
+			if *testrun {
+				fmt.Println(file)
+				continue
+			}
+			if err := os.Remove(file); err != nil {
+				failf(exitIO, "removing %s: %s", file, err)
+			}
+			infof("removed %s", file)
+		}
+		if *manifestFile != "" && !*testrun {
+			recorded, err := write.ReadManifest(*manifestFile)
+			if err == nil {
+				// the removed files are dropped from the manifest
+				err = write.WriteManifest(*manifestFile, recorded)
+			}
+			if err != nil {
+				flagFailf("manifest", statusOf(err, exitParse), "-manifest parameter %s", err)
+			}
+		}
+		return
+	}
+
+	if flag.NArg() > 0 {
+		if *reportFormat != "" {
+			failf(exitParse, "-report cannot be used with directories")
+		}
+		for _, arg := range flag.Args() {
+			if !isDirectoryPattern(arg) {
+				failf(exitParse, "'%s' is not a directory or a pattern like './...'", arg)
+			}
+		}
+		if status := runDirectories(flag.Args()); status != 0 {
+			os.Exit(status)
+		}
+		return
+	}
+
+	if *configName == "" && flag.NFlag() == 0 {
+		if _, err := os.Stat(defaultConfigName); err == nil {
+			*configName = defaultConfigName
+		}
+	}
+	if *configName != "" {
+		if err := loadConfig(*configName); err != nil {
+			failf(statusOf(err, exitParse), "%s", err)
+		}
+	}
+
+	if len(*types) == 0 && !*discover {
+		if position := positionOf("types"); position != "" {
+			failf(exitParse, "%s: -types or -discover is needed, see 'fungen -h'", position)
+		}
 		flag.Usage()
-		os.Exit(2)
+		os.Exit(exitParse)
 	}
 
-	methodsMap := getMethodsMap(*methods)
+	if !validAffix.MatchString(*methodPrefix + *methodSuffix) {
+		name := "prefix"
+		if validAffix.MatchString(*methodPrefix) {
+			name = "suffix"
+		}
+		flagFailf(name, exitParse, "-prefix and -suffix can only contain letters, digits and underscores")
+	}
 
-	importSync := ""
-	needImportSync := len(generators.Filter(func(gen Generator) bool {
-		selectedMethod, _ := methodsMap[gen.name]
-		return selectedMethod && gen.needSync
-	})) > 0
-	if needImportSync {
-		importSync = `import "sync"`
+	typeMap, mapTypes := map[string]string{}, map[string]string{}
+	if *types != "" {
+		parsed, err := spec.Parse(*types, *exportLists, templates.Methods())
+		if err != nil {
+			flagFailf("types", exitParse, "%s", err)
+		}
+		typeMap, mapTypes = parsed.Lists, parsed.Maps
+		typeMethods, typeEquality, typeTargets = parsed.Methods, parsed.Equality, parsed.Targets
+	}
+	if *discover && *outputDir != "" {
+		flagFailf("discover", exitParse, "-discover cannot be used with -outdir, the methods of the discovered types must be in their package")
+	}
+	if *discover {
+		discovered, pkg, warnings, err := discoverTypes(".")
+		for _, warning := range warnings {
+			warnf("-discover: %s", warning)
+		}
+		if err == nil {
+			err = addDiscoveredTypes(typeMap, discovered)
+		}
+		if err != nil {
+			flagFailf("discover", statusOf(err, exitType), "-discover: %s", err)
+		}
+		if *packageName == "" && os.Getenv("GOPACKAGE") == "" {
+			*packageName = pkg
+		}
+		if len(typeMap) == 0 && len(mapTypes) == 0 {
+			flagFailf("discover", exitType, "-discover: no list types like 'type userList []User' found")
+		}
 	}
 
-	src := fmt.Sprintf(`// Package %[1]s - generated by fungen; DO NOT EDIT
-            package %[1]s
-            
-            %[2]s
-			
-            `, *packageName, importSync)
+	if *pluck != "" {
+		fields, err := getPluckedFields(".", *pluck, typeMap)
+		if err != nil {
+			flagFailf("pluck", statusOf(err, exitType), "-pluck parameter %s", err)
+		}
+		addFieldTypes(typeMap, fields)
+		pluckedFields = fields
+	}
 
-	typeMap := getTypeMap(*types)
+	resolved, err := gen.ResolveTypes(".", typeMap)
+	if err == nil {
+		// the types given with the import path of their package are qualified by gen, this only checks them
+		_, _, err = gen.QualifyTypes(resolved)
+	}
+	if err != nil {
+		flagFailf("types", statusOf(err, exitType), "-types parameter %s", err)
+	}
+	typeMap = resolved
+	if mapTypes, err = gen.ResolveTypes(".", mapTypes); err != nil {
+		flagFailf("types", statusOf(err, exitType), "-types parameter %s", err)
+	}
+	if *typeCheck {
+		checked := map[string]string{}
+		for _, m := range []map[string]string{typeMap, mapTypes} {
+			for typeName, name := range m {
+				checked[typeName] = name
+			}
+		}
+		invalid, err := gen.CheckTypes(checked)
+		if err != nil {
+			warnf("the element types are not checked: %s", err)
+		}
+		if len(invalid) > 0 {
+			flagFailf("types", exitType, "-types parameter %s", strings.Join(invalid, "\n"))
+		}
+	}
+	if !*declareLists {
+		for _, name := range typeMap {
+			declaredLists[strings.TrimPrefix(name, "*")+"List"] = true
+		}
+		for _, name := range mapTypes {
+			declaredLists[name] = true
+		}
+	}
 
-	for k1, v1 := range typeMap {
-		if v1[:1] == "*" {
-			src += generate(k1, v1[1:]+"List", typeMap, methodsMap)
-		} else {
-			src += generate(k1, v1+"List", typeMap, methodsMap)
+	if *packageName == "" && *outputDir != "" {
+		*packageName = filepath.Base(filepath.Clean(*outputDir))
+	}
+	if *packageName == "" {
+		*packageName = os.Getenv("GOPACKAGE")
+	}
+	if *packageName == "" {
+		*packageName = "main"
+	}
+	if !validName.MatchString(*packageName) {
+		flagFailf("package", exitParse, "-package parameter '%s' is not a valid package name", *packageName)
+	}
+
+	if *buildTags != "" {
+		if _, err := constraint.Parse("//go:build " + *buildTags); err != nil {
+			flagFailf("tags", exitParse, "-tags parameter '%s' is not valid: %s", *buildTags, err)
 		}
-		src = f(src)
 	}
 
-	if *testrun {
-		fmt.Println(*outputName)
-		fmt.Println(src)
-	} else {
-		err := ioutil.WriteFile(*outputName, []byte(src), 0644)
+	if *headerFile != "" {
+		header, err := write.ReadHeader(*headerFile)
 		if err != nil {
-			log.Fatalf("writing output: %s", err)
+			flagFailf("header-file", statusOf(err, exitParse), "-header-file parameter %s", err)
 		}
+		customHeader = header
 	}
 
-}
+	if *verbose && *quiet {
+		flagFailf("q", exitParse, "-v and -q cannot be used together")
+	}
+
+	if *pooled && *chunked {
+		flagFailf("chunked", exitParse, "-pool and -chunked cannot be used together")
+	}
 
-func f(s string) string {
-	formatted, err := format.Source([]byte(s))
+	methodsMap, err := spec.Methods(*methods, templates.Methods())
 	if err != nil {
-		log.Fatal(err)
+		flagFailf("methods", exitParse, "%s", err)
+	}
+	for _, method := range templates.Methods() {
+		if optIn := flag.Lookup(method.OptIn); optIn != nil && optIn.Value.String() == "true" {
+			methodsMap[method.Name] = true
+		}
+	}
+	if err := spec.Exclude(methodsMap, *exclude, templates.Methods()); err != nil {
+		flagFailf("exclude", exitParse, "%s", err)
 	}
-	return string(formatted)
-}
 
-func getFileNameForTypes(t string, m map[string]string) string {
-	if len(m) == 0 {
-		return t
+	// the methods of the lists with a method list in -types are selected too
+	usedMethods := map[string]bool{}
+	for method := range methodsMap {
+		usedMethods[method] = true
 	}
-	s := t
-	for k, v := range m {
-		if t == k {
-			continue
+	for _, methodsOfList := range typeMethods {
+		for method := range methodsOfList {
+			usedMethods[method] = true
+		}
+	}
+	chunkedMethods, pooledMethods := 0, 0
+	for _, method := range templates.Methods() {
+		if usedMethods[method.Name] && method.Chunked {
+			chunkedMethods++
+		}
+		if usedMethods[method.Name] && method.Pooled {
+			pooledMethods++
 		}
-		s += "_" + v
 	}
-	return s
-}
 
-func getTypeMap(targets string) map[string]string {
-	m := map[string]string{}
-	if targets == "" {
-		return m
+	output := *outputName
+	if *outputPattern != "" {
+		output = *outputPattern
 	}
 
-	targetParts := strings.Split(targets, ",")
-	for _, t := range targetParts {
-		tParts := strings.Split(t, ":")
-		if len(tParts) == 1 {
-			m[tParts[0]] = tParts[0]
-		} else {
-			m[tParts[0]] = tParts[1]
+	if output == "-" && *benchmarks {
+		flagFailf("bench", exitParse, "-bench cannot be used when writing to the standard output")
+	}
+	if output == "-" && *withTests {
+		flagFailf("with-tests", exitParse, "-with-tests cannot be used when writing to the standard output")
+	}
+	if output == "-" && *withExamples {
+		flagFailf("with-examples", exitParse, "-with-examples cannot be used when writing to the standard output")
+	}
+	if output == "-" && *withFuzz {
+		flagFailf("with-fuzz", exitParse, "-with-fuzz cannot be used when writing to the standard output")
+	}
+	if output == "-" && *withProps {
+		flagFailf("with-properties", exitParse, "-with-properties cannot be used when writing to the standard output")
+	}
+	if output == "-" && *docFile {
+		flagFailf("doc", exitParse, "-doc cannot be used when writing to the standard output")
+	}
+	if output == "-" && *check {
+		flagFailf("check", exitParse, "-check cannot be used when writing to the standard output")
+	}
+	if *layout != "file" && *layout != "method" {
+		flagFailf("layout", exitParse, "-layout parameter '%s' is not valid, expected 'file' or 'method'", *layout)
+	}
+	if output == "-" && *layout == "method" {
+		flagFailf("layout", exitParse, "-layout=method cannot be used when writing to the standard output")
+	}
+	if output == "-" && *generics {
+		flagFailf("generics", exitParse, "-generics cannot be used when writing to the standard output")
+	}
+	if *outputDir != "" && output != "-" {
+		if !*check {
+			if err := os.MkdirAll(*outputDir, 0755); err != nil {
+				flagFailf("outdir", exitIO, "-outdir parameter %s", err)
+			}
 		}
+		output = filepath.Join(*outputDir, output)
 	}
+	if output == "-" && *reportFormat != "" && *reportOutput == "-" {
+		flagFailf("report", exitParse, "-report needs a -report-file when writing to the standard output")
+	}
+	if *chunked && chunkedMethods == 0 {
+		warnf("-chunked has no effect: none of the selected methods has a chunked variant")
+	}
+	if *pooled && pooledMethods == 0 {
+		warnf("-pool has no effect: none of the selected methods has a pooled variant")
+	}
+	if *typeCheck {
+		incomparable, ordered, err := gen.ClassifyTypes(filepath.Dir(output), typeMap)
+		if err != nil {
+			warnf("the element types are not checked for the methods comparing or ordering their members: %s", err)
+		}
+		incomparableTypes, orderedTypes = incomparable, ordered
+	}
+	omissions, err := gen.Omissions(newSpec(typeMap, mapTypes, typeMap, methodsMap))
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+	omissions = requestedOmissions(omissions)
+	skipped := append(skippedWarnings(omissions), incomparableWarnings(typeMap, incomparableTypes, methodsMap)...)
+	if *strict && len(skipped) > 0 {
+		flagFailf("strict", exitType, "-strict: the requested methods do not fit the element types:\n%s", strings.Join(skipped, "\n"))
+	}
+	for _, warning := range skipped {
+		warnf("%s", warning)
+	}
+	addReportOmissions(omissions)
 
-	return m
-}
+	writer.Test, writer.ManifestFile = *testrun, *manifestFile
+	if *manifestFile != "" && !*check {
+		recorded, err := write.ReadManifest(*manifestFile)
+		if err != nil {
+			flagFailf("manifest", statusOf(err, exitParse), "-manifest parameter %s", err)
+		}
+		writer.Manifest = recorded
+	}
 
-// getMethodsMap - get selected methods from -methods option, or return all methods
-func getMethodsMap(methodsStr string) map[string]bool {
-	result := map[string]bool{}
-	if methodsStr == "" {
-		generators.Each(func(gen Generator) {
-			result[gen.name] = true
-		})
-		return result
+	if methods, err := findMethods(filepath.Dir(output), *packageName); err == nil {
+		existingMethods = methods
 	}
 
-	validMethods := map[string]bool{}
-	generators.Each(func(gen Generator) {
-		validMethods[gen.name] = true
-	})
+	if *docFile {
+		checkDocFile(docFilename(output))
+	}
 
-	for _, method := range strings.Split(methodsStr, ",") {
-		if _, ok := validMethods[method]; ok {
-			result[method] = true
-		} else {
-			log.Fatalf("Error: -method parameter '%s' is not valid", method)
+	outputs := []generatedFile{}
+	if !strings.Contains(output, "{type}") {
+		outputs = append(outputs, generateSource(output, typeMap, mapTypes, typeMap, methodsMap))
+	} else {
+		// the files are generated concurrently, and kept in the order of the types, followed by the map types
+		typeNames := spec.SortedTypes(typeMap)
+		outputs = make([]generatedFile, len(typeNames)+len(mapTypes))
+		wg := sync.WaitGroup{}
+		sem := make(chan struct{}, runtime.NumCPU())
+		for i, k1 := range typeNames {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, k1 string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				filename := strings.Replace(output, "{type}", strings.TrimPrefix(typeMap[k1], "*"), -1)
+				outputs[i] = generateSource(filename, map[string]string{k1: typeMap[k1]}, nil, typeMap, methodsMap)
+			}(i, k1)
+		}
+		for i, k1 := range spec.SortedTypes(mapTypes) {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, k1 string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				filename := strings.Replace(output, "{type}", mapTypes[k1], -1)
+				outputs[i] = generateSource(filename, nil, map[string]string{k1: mapTypes[k1]}, typeMap, methodsMap)
+			}(len(typeNames)+i, k1)
+		}
+		wg.Wait()
+	}
+	if *generics {
+		outputs = append(outputs, generateGenerics(genericsFilename(output)))
+	}
+	if *typeCheck {
+		typeCheckOutputs(outputs)
+	}
+	for _, out := range outputs {
+		generateFile(out)
+	}
+	if *docFile {
+		generateDocFile(docFilename(output), outputs)
+	}
+
+	if *manifestFile != "" && !*check && !*testrun && output != "-" {
+		if err := write.WriteManifest(*manifestFile, writer.Manifest); err != nil {
+			flagFailf("manifest", exitIO, "-manifest parameter %s", err)
 		}
 	}
 
-	return result
+	if *reportFormat != "" {
+		report.Types = len(typeMap) + len(mapTypes)
+		writeReport(start)
+	}
+
+	if stale {
+		os.Exit(exitFailure)
+	}
 }
 
-func generate(typeName, listname string, m map[string]string, methodsMap map[string]bool) string {
-	code := fmt.Sprintf(`
-            
-            // %[2]s is the type for a list that holds members of type %[1]s
-            type %[2]s []%[1]s
-            `, typeName, listname)
-
-	generators.Filter(func(gen Generator) bool {
-		_, ok := methodsMap[gen.name]
-		return ok
-	}).Each(func(gen Generator) {
-		if gen.needMapToMap {
-			for k, v := range m {
-				targetTypeName := v
-				if k == typeName {
-					targetTypeName = ""
-				}
-
-				code += gen.method(listname, typeName, k, targetTypeName)
-			}
+// generatedFile - the source generated for the lists of the selected types, before it is written
+type generatedFile struct {
+	filename string
+	src      string
+	spec     gen.Spec
+	lists    map[string]string
+	start    time.Time
+}
+
+// newSpec - get the Spec of the lists of the selected types and of the maps and the arrays in a file, mapping to the
+// lists of the types of typeMap, with the options of the command line
+func newSpec(selected, maps, typeMap map[string]string, methodsMap map[string]bool) gen.Spec {
+	spec := gen.Spec{
+		Package:      *packageName,
+		Header:       generatedHeader(),
+		Types:        selected,
+		Maps:         map[string]string{},
+		Arrays:       map[string]string{},
+		Targets:      typeMap,
+		Methods:      generatedMethods(methodsMap),
+		TypeMethods:  map[string][]string{},
+		Declared:     []string{},
+		Prefix:       *methodPrefix,
+		Suffix:       *methodSuffix,
+		Pointer:      *pointerLists,
+		Safe:         *safeLists,
+		Concurrent:   *concurrent,
+		Chunked:      *chunked,
+		Pooled:       *pooled,
+		Grow:         !*prealloc,
+		TwoPass:      *twoPass,
+		Copy:         *copyResults,
+		Generics:     *generics,
+		Deref:        *deref,
+		Fields:       pluckedFields,
+		Incomparable: incomparableTypes,
+		Ordered:      orderedTypes,
+		Equality:     typeEquality,
+		MapTargets:   typeTargets,
+		Templates:    templates,
+	}
+	for typeName, name := range maps {
+		if strings.HasPrefix(typeName, "map[") {
+			spec.Maps[typeName] = name
 		} else {
-			code += gen.method(listname, typeName, "", "")
+			spec.Arrays[typeName] = name
 		}
-	})
-
-	return code
+	}
+	for listName, methods := range typeMethods {
+		spec.TypeMethods[listName] = generatedMethods(methods)
+	}
+	for listName := range declaredLists {
+		spec.Declared = append(spec.Declared, listName)
+	}
+	sort.Strings(spec.Declared)
+	return spec
 }
 
-func getMapFunction(listName, typeName, targetType, targetTypeName string) string {
-	targetListName := targetType + "List"
-	if targetTypeName == "" {
-		targetListName = listName
-	} else if targetTypeName[:1] == "*" {
-		targetTypeName = targetTypeName[1:]
+// generateSource - generate the source of the lists of the selected types and of the maps in a single file
+func generateSource(filename string, selected, maps, typeMap map[string]string, methodsMap map[string]bool) generatedFile {
+	start := time.Now()
+	fileSpec := newSpec(selected, maps, typeMap, methodsMap)
+	generated, err := gen.Generate(fileSpec)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+	src := string(generated)
+	// the lists, like the plugin plan, have the qualified types of the generated code, eg: 'model.User'
+	selected, _, _ = gen.QualifyTypes(selected)
+	lists := map[string]string{}
+	for _, k1 := range spec.SortedTypes(selected) {
+		listName := strings.TrimPrefix(selected[k1], "*") + "List"
+		lists[listName] = k1
+		debugf("generated %s (%s): %s", listName, k1, strings.Join(generatedMethods(methodsOf(listName, methodsMap)), ", "))
+	}
+	maps, _, _ = gen.QualifyTypes(maps)
+	for _, k1 := range spec.SortedTypes(maps) {
+		lists[maps[k1]] = k1
+		debugf("generated %s (%s)", maps[k1], k1)
 	}
 
-	if targetListName[:1] == "*" {
-		targetListName = targetListName[1:]
+	skipped, err := methodCollisions(src, lists, existingMethods)
+	if err != nil {
+		log.Fatalf("Error: the code generated for %s is not valid: %s", filename, err)
+	}
+	if len(skipped) > 0 {
+		collisions := describeCollisions(skipped, lists, existingMethods)
+		if !*skipExisting {
+			failf(exitType, "the generated methods are already declared in the package, remove them or use -skip-existing to skip them:\n%s", strings.Join(collisions, "\n"))
+		}
+		for _, collision := range collisions {
+			warnf("skipped %s", collision)
+		}
+		fileSpec.Skip = map[string][]string{}
+		for listName, methods := range skipped {
+			fileSpec.Skip[listName] = generatedMethods(methods)
+		}
+		if generated, err = gen.Generate(fileSpec); err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+		src = string(generated)
 	}
 
-	return fmt.Sprintf(`
-        // Map%[4]s is a method on %[1]s that takes a function of type %[2]s -> %[3]s and applies it to every member of %[1]s
-        func (l %[1]s) Map%[4]s(f func(%[2]s) %[3]s) %[5]s {
-            l2 := make(%[5]s, len(l))
-            for i, t := range l {
-                l2[i] = f(t)
-            }
-            return l2
-        }
-        `, listName, typeName, targetType, strings.Title(targetTypeName), targetListName)
+	if *plugin != "" {
+		var code string
+		code, err = runPlugin(*plugin, newPluginPlan(filename, fileSpec))
+		if err == nil {
+			src, err = appendPluginCode(src, code)
+		}
+		if err == nil {
+			generated, err = gen.Format([]byte(src), "the plugin "+*plugin)
+			src = string(generated)
+		}
+		if err != nil {
+			flagFailf("plugin", statusOf(err, exitFailure), "-plugin parameter %s", err)
+		}
+	}
 
+	return generatedFile{filename, src, fileSpec, lists, start}
 }
 
-func getPMapFunction(listName, typeName, targetType, targetTypeName string) string {
-	targetListName := targetType + "List"
-	if targetTypeName == "" {
-		targetListName = listName
-	} else if targetTypeName[:1] == "*" {
-		targetTypeName = targetTypeName[1:]
-	}
-
-	if targetListName[:1] == "*" {
-		targetListName = targetListName[1:]
-	}
-
-	return fmt.Sprintf(`
-        // PMap%[4]s is similar to Map%[4]s except that it executes the function on each member in parallel.
-        func (l %[1]s) PMap%[4]s(f func(%[2]s) %[3]s) %[5]s {
-            wg := sync.WaitGroup{}
-            l2 := make(%[5]s, len(l))
-            for i, t := range l {
-                wg.Add(1)
-                go func(i int, t %[2]s){
-                    l2[i] = f(t)
-                    wg.Done()
-                }(i, t)
-            }
-            wg.Wait()
-            return l2
-        }
-        `, listName, typeName, targetType, strings.Title(targetTypeName), targetListName)
+// genericsFilename - get the name of the file of the generic functions for -generics: the output with '{type}' replaced
+// by 'generics', or with a '_generics' suffix, eg: 'fungen_auto_generics.go'
+func genericsFilename(output string) string {
+	if strings.Contains(output, "{type}") {
+		return strings.Replace(output, "{type}", "generics", -1)
+	}
+	return strings.TrimSuffix(output, ".go") + "_generics.go"
+}
 
+// generateGenerics - generate the source of the generic functions called by the methods with -generics
+func generateGenerics(filename string) generatedFile {
+	start := time.Now()
+	spec := gen.Spec{Package: *packageName, Header: generatedHeader(), Grow: !*prealloc, TwoPass: *twoPass, Copy: *copyResults, Templates: templates}
+	src, err := gen.GenerateGenerics(spec)
+	if err != nil {
+		log.Fatalf("Error: generating %s: %s", filename, err)
+	}
+	return generatedFile{filename, string(src), spec, map[string]string{}, start}
 }
 
-func getFilterFunction(listName, typeName, _, _ string) string {
-	return fmt.Sprintf(`
-        // Filter is a method on %[1]s that takes a function of type %[2]s -> bool returns a list of type %[1]s which contains all members from the original list for which the function returned true
-        func (l %[1]s) Filter(f func(%[2]s) bool) %[1]s {
-            l2 := []%[2]s{}
-            for _, t := range l {
-                if f(t) {
-                    l2 = append(l2, t)
-                }
-            }
-            return l2
-        }
-        `, listName, typeName)
+// docFilename - get the name of the doc.go file of -doc, in the directory of the output
+func docFilename(output string) string {
+	return filepath.Join(filepath.Dir(output), "doc.go")
 }
 
-func getPFilterFunction(listName, typeName, _, _ string) string {
-	return fmt.Sprintf(`
-        // PFilter is similar to the Filter method except that the filter is applied to all the elements in parallel. The order of resulting elements cannot be guaranteed. 
-        func (l %[1]s) PFilter(f func(%[2]s) bool) %[1]s {
-            wg := sync.WaitGroup{}
-            mutex := sync.Mutex{}
-            l2 := []%[2]s{}
-            for _, t := range l {
-                wg.Add(1)
-                go func(t %[2]s){
-                    if f(t) {
-                        mutex.Lock()
-                        l2 = append(l2, t)
-                        mutex.Unlock()
-                    }            
-                    wg.Done()
-                }(t)
-            }
-            wg.Wait()
-            return l2
-        }
-        `, listName, typeName)
+// checkDocFile - check, before anything is generated, that the doc.go file of -doc was not written by hand, so that it
+// is not overwritten, and report the package comments of the other files of the package, since godoc would show them
+// with the generated one
+func checkDocFile(filename string) {
+	existing, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.PackageClauseOnly|parser.ParseComments)
+	if err == nil && !generatedByFungen(existing) {
+		log.Fatalf("Error: -doc cannot overwrite %s, which was not generated by fungen", filename)
+	}
+	if documented, err := packageComments(filepath.Dir(filename), *packageName); err == nil {
+		for _, other := range documented {
+			warnf("-doc: %s has a package comment already, godoc shows it with the one of %s", other, filename)
+		}
+	}
 }
 
-func getEachFunction(listName, typeName, _, _ string) string {
-	return fmt.Sprintf(`
-        // Each is a method on %[1]s that takes a function of type %[2]s -> void and applies the function to each member of the list and then returns the original list.
-        func (l %[1]s) Each(f func(%[2]s)) %[1]s {
-            for _, t := range l {
-                f(t) 
-            }
-            return l
-        }
-        `, listName, typeName)
+// generateDocFile - write the doc.go file of -doc, with the package comment listing the types and the functions of the
+// generated files
+func generateDocFile(filename string, outputs []generatedFile) {
+	start := time.Now()
+	sources := map[string][]byte{}
+	for _, out := range outputs {
+		sources[out.filename] = []byte(out.src)
+	}
+	src, err := gen.GenerateDoc(gen.Spec{Package: *packageName, Header: generatedHeader()}, sources)
+	if err != nil {
+		log.Fatalf("Error: generating %s: %s", filename, err)
+	}
+	addReportFile(filename, string(src), 0)
+	if *check {
+		checkOutput(filename, string(src), map[string]string{})
+	} else if writeOutput(filename, string(src)) {
+		infof("generated %s in %s", filename, time.Since(start))
+	}
 }
 
-func getEachIFunction(listName, typeName, _, _ string) string {
-	return fmt.Sprintf(`
-        // EachI is a method on %[1]s that takes a function of type (int, %[2]s) -> void and applies the function to each member of the list and then returns the original list. The int parameter to the function is the index of the element.
-        func (l %[1]s) EachI(f func(int, %[2]s)) %[1]s {
-            for i, t := range l {
-                f(i, t) 
-            }
-            return l
-        }
-        `, listName, typeName)
+// packageComments - get the Go files of the package in a directory which have a package comment, except the test files
+// and the files generated by fungen
+func packageComments(dir, packageName string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	result := []string{}
+	fset := token.NewFileSet()
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.PackageClauseOnly|parser.ParseComments)
+		if err != nil || file.Name.Name != packageName || generatedByFungen(file) {
+			continue
+		}
+		if file.Doc != nil {
+			result = append(result, filepath.Join(dir, name))
+		}
+	}
+	return result, nil
 }
 
-func getDropWhileFunction(listName, typeName, _, _ string) string {
-	return fmt.Sprintf(`
-        // DropWhile is a method on %[1]s that takes a function of type %[2]s -> bool and returns a list of type %[1]s which excludes the first members from the original list for which the function returned true
-        func (l %[1]s) DropWhile(f func(%[2]s) bool) %[1]s {
-            for i, t := range l {
-                if !f(t) {
-                    return l[i:]
-                }
-            }
-            var l2 %[1]s
-            return l2
-        }
-        `, listName, typeName)
+// typeCheckOutputs - type-check the files generated together with the other files of their package, and fail with the
+// errors in the generated code
+func typeCheckOutputs(outputs []generatedFile) {
+	sources := map[string]string{}
+	lists := map[string]string{}
+	for _, out := range outputs {
+		sources[out.filename] = out.src
+		for listName, typeName := range out.lists {
+			lists[listName] = typeName
+		}
+	}
+	errs, err := typeCheckGenerated(sources, *packageName, lists)
+	if err != nil {
+		warnf("the generated code is not type-checked: %s", err)
+	}
+	if len(errs) > 0 {
+		failf(exitType, "the generated code does not compile:\n%s", strings.Join(errs, "\n"))
+	}
 }
 
-func getTakeWhileFunction(listName, typeName, _, _ string) string {
-	return fmt.Sprintf(`
-        // TakeWhile is a method on %[1]s that takes a function of type %[2]s -> bool and returns a list of type %[1]s which includes only the first members from the original list for which the function returned true
-        func (l %[1]s) TakeWhile(f func(%[2]s) bool) %[1]s {
-            for i, t := range l {
-                if !f(t) {
-                    return l[:i]
-                }
-            }
-            return l
-        }
-        `, listName, typeName)
+// generateFile - write the generated file, and its benchmarks if -bench is set, its tests if -with-tests is set, its
+// examples if -with-examples is set, its fuzz tests if -with-fuzz is set and its property tests if -with-properties is
+// set
+func generateFile(out generatedFile) {
+	filename, lists := out.filename, out.lists
+	types := len(out.spec.Types)
+	for _, file := range layoutFiles(out) {
+		addReportFile(file.filename, file.src, types)
+		if *check {
+			checkOutput(file.filename, file.src, lists)
+		} else {
+			if writeOutput(file.filename, file.src) {
+				infof("generated %s (%d types) in %s", file.filename, types, time.Since(out.start))
+			}
+		}
+	}
+
+	extra := func(enabled bool, suffix string, generate func(gen.Spec) ([]byte, error)) {
+		// the maps have no benchmarks, tests or examples
+		if !enabled || len(out.spec.Types) == 0 {
+			return
+		}
+		start := time.Now()
+		extraFilename := strings.TrimSuffix(filename, ".go") + suffix
+		src, err := generate(out.spec)
+		if err != nil {
+			log.Fatalf("Error: generating %s: %s", extraFilename, err)
+		}
+		addReportFile(extraFilename, string(src), types)
+		if *check {
+			checkOutput(extraFilename, string(src), lists)
+		} else {
+			if writeOutput(extraFilename, string(src)) {
+				infof("generated %s in %s", extraFilename, time.Since(start))
+			}
+		}
+	}
+	extra(*benchmarks, "_bench_test.go", gen.GenerateBenchmarks)
+	extra(*withTests, "_test.go", gen.GenerateTests)
+	extra(*withExamples, "_example_test.go", gen.GenerateExamples)
+	extra(*withFuzz, "_fuzz_test.go", gen.GenerateFuzzTests)
+	extra(*withProps, "_property_test.go", gen.GeneratePropertyTests)
 }
 
-func getTakeFunction(listName, typeName, _, _ string) string {
-	return fmt.Sprintf(`
-        // Take is a method on %[1]s that takes an integer n and returns the first n elements of the original list. If the list contains fewer than n elements then the entire list is returned.
-        func (l %[1]s) Take(n int) %[1]s {
-            if len(l) >= n {
-                return l[:n]
-            }
-            return l
-        }
-        `, listName, typeName)
+// layoutFile - a file of the -layout of a generated file
+type layoutFile struct {
+	filename string
+	src      string
 }
 
-func getDropFunction(listName, typeName, _, _ string) string {
-	return fmt.Sprintf(`
-        // Drop is a method on %[1]s that takes an integer n and returns all but the first n elements of the original list. If the list contains fewer than n elements then an empty list is returned.
-        func (l %[1]s) Drop(n int) %[1]s {
-            if len(l) >= n {
-                return l[n:]
-            }
-            var l2 %[1]s
-            return l2
-        }
-        `, listName, typeName)
+// layoutFiles - get the files a generated file is written to with the -layout: the file itself, or with 'method' the
+// file with the declarations shared by the methods and a file for each method of each list in the same directory,
+// named after the list and the method, eg: 'stringlist_filter.go'
+func layoutFiles(out generatedFile) []layoutFile {
+	if *layout != "method" {
+		return []layoutFile{{out.filename, out.src}}
+	}
+
+	sources, err := gen.SplitMethods(out.spec, []byte(out.src))
+	if err != nil {
+		log.Fatalf("Error: -layout parameter %s", err)
+	}
+	keys := []string{}
+	for key := range sources {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	result := []layoutFile{{out.filename, string(sources[""])}}
+	for _, key := range keys {
+		name := strings.ToLower(strings.Replace(key, ".", "_", 1)) + ".go"
+		result = append(result, layoutFile{filepath.Join(filepath.Dir(out.filename), name), string(sources[key])})
+	}
+	return result
 }
 
-func getReduceFunction(listName, typename, _, _ string) string {
-	return fmt.Sprintf(`
-        // Reduce is a method on %[1]s that takes a function of type (%[2]s, %[2]s) -> %[2]s and returns a %[2]s which is the result of applying the function to all members of the original list starting from the first member
-        func (l %[1]s) Reduce(t1 %[2]s, f func(%[2]s, %[2]s) %[2]s) %[2]s {
-            for _, t := range l {
-                t1 = f(t1, t)
-            }
-            return t1
-        }
-        `, listName, typename)
+// customHeader - the contents of the -header-file
+var customHeader string
+
+// writer - writes the generated files, with the -test and -manifest options
+var writer = write.Output{Manifest: map[string]string{}, Infof: infof, Warnf: warnf}
+
+// writeOutput - write the generated source with the writer, and fail if it cannot be written
+func writeOutput(filename, src string) bool {
+	written, err := writer.File(filename, src)
+	if err != nil {
+		failf(exitIO, "writing output: %s", err)
+	}
+	return written
 }
 
-func getReduceRightFunction(listName, typename, _, _ string) string {
-	return fmt.Sprintf(`
-        // ReduceRight is a method on %[1]s that takes a function of type (%[2]s, %[2]s) -> %[2]s and returns a %[2]s which is the result of applying the function to all members of the original list starting from the last member
-        func (l %[1]s) ReduceRight(t1 %[2]s, f func(%[2]s, %[2]s) %[2]s) %[2]s {
-            for i := len(l) - 1; i >= 0; i-- {
-                t := l[i]
-                t1 = f(t, t1)
-            }
-            return t1
-        }
-        `, listName, typename)
+// generatedHeader - get the header of the generated files: the contents of the -header-file, the build constraint, if
+// -tags is set, and the standard comment marking the files as generated, with the command which generated them and the
+// version of fungen
+func generatedHeader() string {
+	return customHeader + buildConstraint() + fmt.Sprintf(`// Code generated by fungen; DO NOT EDIT.
+        // Command: %s
+        // Version: %s
+
+        `, generatorCommand(os.Args[1:]), fungenVersion)
+}
+
+// noCommandFlags - the flags which do not change the generated code and are left out of the command in the header
+var noCommandFlags = map[string]bool{"check": true, "manifest": true, "report": true, "report-file": true, "strict": true, "test": true, "v": true, "q": true}
+
+// setFlags - the flags whose values are sets, so that the order of their members does not change the generated code
+var setFlags = map[string]bool{"types": true, "methods": true, "exclude": true}
+
+// generatorCommand - get the command line which reproduces the generated code, with the arguments quoted if needed.
+// The flags are sorted by name and the members of the setFlags are sorted, so that the header does not change when the
+// same code is generated with the flags in another order
+func generatorCommand(args []string) string {
+	values := map[string]string{}
+	names := []string{}
+	positional := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+		parts := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)
+		name, value := parts[0], ""
+		if len(parts) == 2 {
+			value = parts[1]
+		} else if isBoolFlag(name) {
+			value = "true"
+		} else if i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		if noCommandFlags[name] {
+			continue
+		}
+		if name == "types" {
+			members := spec.Split(value)
+			sort.Strings(members)
+			value = spec.Join(members)
+		} else if setFlags[name] {
+			members := strings.Split(value, ",")
+			sort.Strings(members)
+			value = strings.Join(members, ",")
+		}
+		if _, ok := values[name]; !ok {
+			names = append(names, name)
+		}
+		values[name] = value
+	}
+	sort.Strings(names)
+
+	command := []string{"fungen"}
+	for _, name := range names {
+		value := values[name]
+		switch {
+		case isBoolFlag(name) && value == "true":
+			command = append(command, "-"+name)
+		case isBoolFlag(name):
+			command = append(command, "-"+name+"="+value)
+		default:
+			command = append(command, "-"+name, quoteArg(value))
+		}
+	}
+	for _, arg := range positional {
+		command = append(command, quoteArg(arg))
+	}
+	return strings.Join(command, " ")
 }
 
-func getAllFunction(listName, typename, _, _ string) string {
-	return fmt.Sprintf(`
-        // All is a method on %[1]s that returns true if all the members of the list satisfy a function or if the list is empty. 
-        func (l %[1]s) All(f func(%[2]s) bool) bool {
-            for _, t := range l {
-                if !f(t) {
-                    return false
-                }
-            }
-            return true
-        }
-        `, listName, typename)
+// isBoolFlag - whether a flag is a boolean flag, which can be given without a value
+func isBoolFlag(name string) bool {
+	f := flag.Lookup(name)
+	if f == nil {
+		return false
+	}
+	value, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && value.IsBoolFlag()
 }
 
-func getAnyFunction(listName, typename, _, _ string) string {
-	return fmt.Sprintf(`
-        // Any is a method on %[1]s that returns true if at least one member of the list satisfies a function. It returns false if the list is empty. 
-        func (l %[1]s) Any(f func(%[2]s) bool) bool {
-            for _, t := range l {
-                if f(t) {
-                    return true
-                }
-            }
-            return false
-        }
-        `, listName, typename)
+// quoteArg - quote an argument of the command line if needed
+func quoteArg(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t\"'$&|!*?{}") {
+		return strconv.Quote(arg)
+	}
+	return arg
 }
 
-func getFilterMapFunction(listName, typeName, targetType, targetTypeName string) string {
-	if targetTypeName == "" {
-		//there's no need for a FilterMap function for the same time as the filter function suffices
+// buildConstraint - get the //go:build line for the -tags option, if it is set
+func buildConstraint() string {
+	if *buildTags == "" {
 		return ""
-	} else if targetTypeName[:1] == "*" {
-		targetTypeName = targetTypeName[1:]
-	}
-
-	targetListName := targetType + "List"
-	if targetListName[:1] == "*" {
-		targetListName = targetListName[1:]
-	}
-
-	return fmt.Sprintf(`
-        // FilterMap%[4]s is a method on %[1]s that applies the filter(s) and map to the list members in a single loop and returns the resulting list.
-        func (l %[1]s) FilterMap%[4]s(fMap func(%[2]s) %[3]s, fFilters ...func(%[2]s) bool) %[5]s {
-            l2 := %[5]s{}
-            for _, t := range l {
-                pass := true
-                for _, f := range fFilters {
-                    if !f(t){
-                        pass = false
-                        break
-                    }
-                }
-                if pass {
-                    l2 = append(l2, fMap(t))
-                }
-            }
-            return l2
-        }
-        `, listName, typeName, targetType, strings.Title(targetTypeName), targetListName)
+	}
+	return "//go:build " + *buildTags + "\n\n"
+}
+
+// generatedMethods - get the names of the selected methods, in the order in which they are generated
+func generatedMethods(methodsMap map[string]bool) []string {
+	result := []string{}
+	for _, method := range templates.Methods() {
+		if methodsMap[method.Name] {
+			result = append(result, method.Name)
+		}
+	}
+	return result
+}
 
+// infof - report progress, unless -q is set. Like all the messages of fungen, it is written to the standard error
+func infof(format string, args ...interface{}) {
+	if !*quiet {
+		log.Printf(format, args...)
+	}
 }
 
-func getPFilterMapFunction(listName, typeName, targetType, targetTypeName string) string {
-	if targetTypeName == "" {
-		//there's no need for a PFilterMap function for the same time as the pfilter function suffices
-		return ""
-	} else if targetTypeName[:1] == "*" {
-		targetTypeName = targetTypeName[1:]
-	}
-
-	targetListName := targetType + "List"
-	if targetListName[:1] == "*" {
-		targetListName = targetListName[1:]
-	}
-
-	return fmt.Sprintf(`
-        // PFilterMap%[4]s is similar to FilterMap%[4]s except that it executes the method on each member in parallel.
-        func (l %[1]s) PFilterMap%[4]s(fMap func(%[2]s) %[3]s, fFilters ...func(%[2]s) bool) %[5]s {
-            l2 := %[5]s{}
-            mutex := sync.Mutex{}
-            wg := sync.WaitGroup{}
-            wg.Add(len(l))
-            
-            for _, t := range l {
-                go func(t %[2]s){
-                    pass := true
-                    for _, f := range fFilters {
-                        if !f(t) {
-                            pass = false
-                            break
-                        }
-                    }
-                    if pass {
-                        mutex.Lock()
-                        l2 = append(l2, fMap(t))
-                        mutex.Unlock()
-                    }
-                    wg.Done()
-                }(t)
-            }
-            wg.Wait()
-            return l2
-        }
-        `, listName, typeName, targetType, strings.Title(targetTypeName), targetListName)
+// debugf - report details, if -v is set
+func debugf(format string, args ...interface{}) {
+	if *verbose {
+		log.Printf(format, args...)
+	}
+}
+
+var (
+	validAffix = regexp.MustCompile(`^\w*$`)
+	validName  = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+)
 
+func getFileNameForTypes(t string, m map[string]string) string {
+	if len(m) == 0 {
+		return t
+	}
+	s := t
+	for _, k := range spec.SortedTypes(m) {
+		if t == k {
+			continue
+		}
+		s += "_" + m[k]
+	}
+	return s
+}
+
+// templates - the templates of -templates, overriding the built-in methods or adding extra methods
+var templates gen.Templates
+
+// typeMethods - the methods selected for the lists of the types given with a method list in -types, by list name. The
+// method list of a type replaces -methods, -exclude and the options adding methods, like -chan, for its list
+var typeMethods = map[string]map[string]bool{}
+
+// typeTargets - the lists the methods mapping to other lists, like Map, map the lists of the types given with Map
+// targets in -types to, instead of all the lists, by list name (see spec.Types)
+var typeTargets = map[string][]string{}
+
+// typeEquality - the functions comparing the members of the lists of the types given with the eq and hash options in
+// -types, by list name
+var typeEquality = map[string]gen.Equality{}
+
+// methodsOf - get the methods selected for a list: its method list in -types, or the methods selected for all the lists
+func methodsOf(listName string, methodsMap map[string]bool) map[string]bool {
+	if selected, ok := typeMethods[listName]; ok {
+		return selected
+	}
+	return methodsMap
 }
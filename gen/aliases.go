@@ -0,0 +1,279 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	localNamesOnce sync.Once
+	localNameSet   map[string]bool
+)
+
+// localNames - the names of the receivers, the parameters and the variables declared in the generated functions, their
+// tests, examples and benchmarks, eg: 'l', 'f' and 't'. An element type, or the package of an element type, named like
+// one of them would be shadowed in the functions declaring it, so the lists use an alias of the type instead (see
+// aliasElements). The names are collected once from the code of every generator and of its variants
+func localNames() map[string]bool {
+	localNamesOnce.Do(func() {
+		types := map[string]string{"int": "int", "*int": "intPtr", "[]int": "ints"}
+		methods := map[string]bool{}
+		generators.Each(func(gen Generator) {
+			methods[gen.name] = true
+		})
+		p := plan{
+			types:      types,
+			targets:    types,
+			maps:       map[string]string{"map[int]int": "intIndex"},
+			arrays:     map[string]string{"[4]int": "vec4"},
+			concurrent: true,
+			methods:    methods,
+			generators: generators,
+			equality:   map[string]Equality{"intList": {Eq: "eq", Hash: "hash"}},
+			fields:     map[string][]Field{"intList": {{Name: "Name", Type: "int"}}},
+		}
+
+		codes := []string{generateTests("p", types, types, p), generateExamples("p", types, types, p, "", ""), generateBenchmarks("p", types, p), generateFuzzTests("p", types, p), generatePropertyTests("p", types, p)}
+		for _, variant := range []plan{p, {deref: true}, {grow: true}, {twoPass: true}, {copy: true}} {
+			variant.types, variant.targets, variant.methods, variant.equality, variant.generators = p.types, p.targets, p.methods, p.equality, p.generators
+			for typeName, name := range types {
+				listName := name + "List"
+				for _, chunked := range []bool{false, true} {
+					code := generate(typeName, listName, types, variant, chunked, !chunked)
+					codes = append(codes, code)
+					if wrapper, err := safeWrapper(code, listName, nil, nil, nil); err == nil {
+						codes = append(codes, wrapper)
+					}
+				}
+			}
+		}
+		codes = append(codes, generateMap("map[int]int", "intIndex", p), generateArray("[4]int", "vec4", p))
+
+		localNameSet = map[string]bool{}
+		for _, code := range codes {
+			file, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+strings.TrimPrefix(strings.TrimSpace(code), "package p"), 0)
+			if err != nil {
+				continue
+			}
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok {
+					collectLocalNames(fn, localNameSet)
+				}
+			}
+		}
+		delete(localNameSet, "_")
+	})
+	return localNameSet
+}
+
+// collectLocalNames - add the names declared in a function to names: its receiver, its parameters and its results,
+// and its variables, its range variables and its labels, and those of the function literals in it
+func collectLocalNames(fn *ast.FuncDecl, names map[string]bool) {
+	ast.Inspect(fn, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.Field:
+			for _, ident := range n.Names {
+				names[ident.Name] = true
+			}
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				for _, expr := range n.Lhs {
+					if ident, ok := expr.(*ast.Ident); ok {
+						names[ident.Name] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, ident := range n.Names {
+				names[ident.Name] = true
+			}
+		case *ast.RangeStmt:
+			for _, expr := range []ast.Expr{n.Key, n.Value} {
+				if ident, ok := expr.(*ast.Ident); ok {
+					names[ident.Name] = true
+				}
+			}
+		case *ast.LabeledStmt:
+			names[n.Label.Name] = true
+		}
+		return true
+	})
+}
+
+// shadowedTypes - get the named types in a type expression which the local names of the generated functions would
+// shadow (see localNames): the types named like one of them, eg: 't' in '[]t', and the types of the packages named
+// like one of them, eg: 'r.Rule' in 'map[string]r.Rule', in the order in which they appear
+func shadowedTypes(typeName string) []string {
+	expr, err := parser.ParseExpr(typeName)
+	if err != nil {
+		return nil
+	}
+	locals := localNames()
+	result := []string{}
+	add := func(name string) {
+		if !contains(result, name) {
+			result = append(result, name)
+		}
+	}
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.Field:
+			// the names of the fields and of the parameters are not types
+			ast.Inspect(n.Type, func(node ast.Node) bool {
+				if selector, ok := node.(*ast.SelectorExpr); ok {
+					if pkg, ok := selector.X.(*ast.Ident); ok && locals[pkg.Name] {
+						add(pkg.Name + "." + selector.Sel.Name)
+					}
+					return false
+				}
+				if ident, ok := node.(*ast.Ident); ok && locals[ident.Name] {
+					add(ident.Name)
+				}
+				return true
+			})
+			return false
+		case *ast.SelectorExpr:
+			if pkg, ok := n.X.(*ast.Ident); ok && locals[pkg.Name] {
+				add(pkg.Name + "." + n.Sel.Name)
+			}
+			return false
+		case *ast.Ident:
+			if locals[n.Name] {
+				add(n.Name)
+			}
+		}
+		return true
+	})
+	return result
+}
+
+// replaceTypes - replace the named types of a type expression by their aliases, eg: '[]t' -> '[]tListMember'
+func replaceTypes(typeName string, aliases map[string]string) string {
+	expr, err := parser.ParseExpr(typeName)
+	if err != nil {
+		return typeName
+	}
+	type replacement struct {
+		start, end int
+		alias      string
+	}
+	replacements := []replacement{}
+	var visit func(node ast.Node) bool
+	visit = func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.Field:
+			ast.Inspect(n.Type, visit)
+			return false
+		case *ast.SelectorExpr:
+			if pkg, ok := n.X.(*ast.Ident); ok {
+				if alias, ok := aliases[pkg.Name+"."+n.Sel.Name]; ok {
+					replacements = append(replacements, replacement{int(n.Pos()) - 1, int(n.End()) - 1, alias})
+				}
+			}
+			return false
+		case *ast.Ident:
+			if alias, ok := aliases[n.Name]; ok {
+				replacements = append(replacements, replacement{int(n.Pos()) - 1, int(n.End()) - 1, alias})
+			}
+		}
+		return true
+	}
+	ast.Inspect(expr, visit)
+	for i := len(replacements) - 1; i >= 0; i-- {
+		r := replacements[i]
+		typeName = typeName[:r.start] + r.alias + typeName[r.end:]
+	}
+	return typeName
+}
+
+// aliasElements - replace the element types shadowed by the local names of the generated functions (see
+// shadowedTypes) by aliases in the types of the plan, eg: '[]t' -> '[]tListMember', so that 'type tList []t' is
+// generated as 'type tList []tListMember' with 'type tListMember = t'. An alias is named after the list, the map or the
+// array of the first type it appears in, eg: 'tListMember' for 't', or 'tpListT' for '*t' if 't' has no list, and it
+// is declared in the file generating that list (see aliasDeclarations)
+func (p *plan) aliasElements() {
+	owners := map[string]string{}
+	for _, m := range []map[string]string{p.targets, p.types, p.maps, p.arrays} {
+		for typeName, name := range m {
+			owners[typeName] = name
+		}
+	}
+	ownerTypes := sortedTypes(owners)
+
+	p.aliased = map[string]string{}
+	p.ownAliases = map[string]bool{}
+	for _, typeName := range ownerTypes {
+		for _, shadowed := range shadowedTypes(typeName) {
+			if _, ok := p.aliased[shadowed]; ok {
+				continue
+			}
+			owner := typeName
+			if _, ok := owners[shadowed]; ok {
+				owner = shadowed
+			}
+			name := strings.TrimPrefix(owners[owner], "*")
+			if _, isMap := p.maps[owner]; !isMap {
+				if _, isArray := p.arrays[owner]; !isArray {
+					name += "List"
+				}
+			}
+			alias := name + "Member"
+			if owner != shadowed {
+				alias = name + strings.Title(strings.Replace(shadowed, ".", "", -1))
+			}
+			p.aliased[shadowed] = alias
+			_, listed := p.types[owner]
+			_, mapped := p.maps[owner]
+			_, arrayed := p.arrays[owner]
+			p.ownAliases[alias] = listed || mapped || arrayed
+		}
+	}
+	if len(p.aliased) == 0 {
+		return
+	}
+
+	replace := func(m map[string]string) map[string]string {
+		result := map[string]string{}
+		for typeName, value := range m {
+			result[replaceTypes(typeName, p.aliased)] = value
+		}
+		return result
+	}
+	p.types, p.targets, p.maps, p.arrays = replace(p.types), replace(p.targets), replace(p.maps), replace(p.arrays)
+	p.incomparable, p.ordered = replace(p.incomparable), replace(p.ordered)
+	fields := map[string][]Field{}
+	for listName, listFields := range p.fields {
+		for _, field := range listFields {
+			fields[listName] = append(fields[listName], Field{Name: field.Name, Type: replaceTypes(field.Type, p.aliased)})
+		}
+	}
+	p.fields = fields
+}
+
+// aliasDeclarations - declare the aliases of the element types (see aliasElements) which belong to the lists, the maps
+// and the arrays generated in the file of the plan
+func (p plan) aliasDeclarations() string {
+	shadowed := []string{}
+	for typeName := range p.aliased {
+		shadowed = append(shadowed, typeName)
+	}
+	sort.Strings(shadowed)
+
+	code := ""
+	for _, typeName := range shadowed {
+		alias := p.aliased[typeName]
+		if !p.ownAliases[alias] {
+			continue
+		}
+		code += fmt.Sprintf(`
+            // %[1]s is an alias of %[2]s, whose name is used by the variables of the generated methods
+            type %[1]s = %[2]s
+            `, alias, typeName)
+	}
+	return code
+}
@@ -0,0 +1,80 @@
+package gen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocalNames(t *testing.T) {
+	names := localNames()
+	for _, name := range []string{"l", "f", "t", "i", "n", "s", "members", "r"} {
+		if !names[name] {
+			t.Errorf("'%s' is not a local name", name)
+		}
+	}
+	for _, name := range []string{"int", "point", "Map", "_"} {
+		if names[name] {
+			t.Errorf("'%s' is a local name", name)
+		}
+	}
+}
+
+func TestShadowedTypes(t *testing.T) {
+	tests := map[string][]string{
+		"int":                     {},
+		"point":                   {},
+		"t":                       {"t"},
+		"*t":                      {"t"},
+		"map[f]t":                 {"f", "t"},
+		"[]r.Rule":                {"r.Rule"},
+		"model.User":              {},
+		"struct{ t int; x t }":    {"t"},
+		"func(n int) (l, error)":  {"l"},
+		"chan map[string][]point": {},
+	}
+	for typeName, expected := range tests {
+		if result := shadowedTypes(typeName); !reflect.DeepEqual(result, expected) {
+			t.Errorf("%s: got %v, expected %v", typeName, result, expected)
+		}
+	}
+
+	aliases := map[string]string{"t": "tListMember", "r.Rule": "rulesListRRule"}
+	replaced := map[string]string{
+		"*t":                   "*tListMember",
+		"map[string]t":         "map[string]tListMember",
+		"[]r.Rule":             "[]rulesListRRule",
+		"struct{ t int; x t }": "struct{ t int; x tListMember }",
+		"point":                "point",
+	}
+	for typeName, expected := range replaced {
+		if result := replaceTypes(typeName, aliases); result != expected {
+			t.Errorf("%s: got %s, expected %s", typeName, result, expected)
+		}
+	}
+}
+
+func TestGenerateShadowedTypes(t *testing.T) {
+	types := map[string]string{"t": "t", "*t": "tp", "[]f": "fs"}
+	src, err := Generate(Spec{Package: "main", Types: types, Methods: []string{"Map", "Filter"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"type tListMember = t", "type tList []tListMember", "type tpList []*tListMember", "type fsListF = f", "type fsList [][]fsListF",
+		"func (l tList) Filter(f func(tListMember) bool) tList {",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("'%s' not found in:\n%s", expected, src)
+		}
+	}
+
+	// the alias of t belongs to tList, which is generated in another file
+	src, err = Generate(Spec{Package: "main", Types: map[string]string{"*t": "tp"}, Targets: types, Methods: []string{"Map"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(src), "type tListMember") || !strings.Contains(string(src), "type tpList []*tListMember") {
+		t.Error(string(src))
+	}
+}
@@ -0,0 +1,86 @@
+package gen
+
+import (
+	"regexp"
+
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+// arrayType - an array type: its length, a number or the name of a constant, and its element type
+var arrayType = regexp.MustCompile(`^\[([0-9]+|[A-Za-z_][A-Za-z0-9_.]*)\](.+)$`)
+
+// splitArrayType - split an array type, eg: '[4]float32', into its length and its element type
+func splitArrayType(typeName string) (string, string, bool) {
+	match := arrayType.FindStringSubmatch(typeName)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// generateArray - generate an array type and its methods, which keep its fixed length: Map returns an array of the
+// same type, the Map methods mapping to the other Targets and ToList return the lists of the Targets, and Each, EachI,
+// Reduce and ReduceRight go over the members in place
+func generateArray(typeName, name string, p plan) string {
+	length, elemType, _ := splitArrayType(typeName)
+
+	code := ""
+	if !p.declared[name] {
+		code += "\n" + render.Declarations(arrayTypeTemplate, struct {
+			TemplateData
+			Length    string
+			ArrayType string
+		}{newTemplateData(name, elemType, "", ""), length, typeName})
+	}
+
+	code += getArrayEachFunction(name, elemType)
+	code += getArrayEachIFunction(name, elemType)
+	code += getArrayMapFunction(name, elemType, elemType, "")
+	for _, targetType := range sortedTypes(p.targets) {
+		if targetType != elemType {
+			code += getArrayMapFunction(name, elemType, targetType, p.targets[targetType])
+		}
+	}
+	code += getArrayReduceFunction(name, elemType)
+	code += getArrayReduceRightFunction(name, elemType)
+	code += getArrayToListFunction(name, elemType, p.listOf(elemType))
+	return code
+}
+
+var arrayTypeTemplate = render.Template("ArrayType")
+
+var arrayEachTemplate = render.Template("ArrayEach")
+
+func getArrayEachFunction(name, elemType string) string {
+	return render.Declarations(arrayEachTemplate, newTemplateData(name, elemType, "", ""))
+}
+
+var arrayEachITemplate = render.Template("ArrayEachI")
+
+func getArrayEachIFunction(name, elemType string) string {
+	return render.Declarations(arrayEachITemplate, newTemplateData(name, elemType, "", ""))
+}
+
+var arrayMapTemplate = render.Template("ArrayMap")
+
+func getArrayMapFunction(name, elemType, targetType, targetTypeName string) string {
+	return render.Declarations(arrayMapTemplate, newTemplateData(name, elemType, targetType, targetTypeName))
+}
+
+var arrayReduceTemplate = render.Template("ArrayReduce")
+
+func getArrayReduceFunction(name, elemType string) string {
+	return render.Declarations(arrayReduceTemplate, newTemplateData(name, elemType, "", ""))
+}
+
+var arrayReduceRightTemplate = render.Template("ArrayReduceRight")
+
+func getArrayReduceRightFunction(name, elemType string) string {
+	return render.Declarations(arrayReduceRightTemplate, newTemplateData(name, elemType, "", ""))
+}
+
+var arrayToListTemplate = render.Template("ArrayToList")
+
+func getArrayToListFunction(name, elemType, listName string) string {
+	return render.Declarations(arrayToListTemplate, TemplateData{ListName: name, TypeName: elemType, TargetType: elemType, TargetListName: listName})
+}
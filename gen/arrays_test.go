@@ -0,0 +1,62 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitArrayType(t *testing.T) {
+	for typeName, expected := range map[string][2]string{
+		"[4]float32":                 {"4", "float32"},
+		"[Size][]model.User":         {"Size", "[]model.User"},
+		"[2]github.com/user/app/m.T": {"2", "github.com/user/app/m.T"},
+		"[geo.Dims]map[string]int":   {"geo.Dims", "map[string]int"},
+	} {
+		length, elemType, ok := splitArrayType(typeName)
+		if !ok || [2]string{length, elemType} != expected {
+			t.Error(typeName, length, elemType)
+		}
+	}
+	for _, typeName := range []string{"[]int", "[4]", "map[string]int", "[4 ]int"} {
+		if _, _, ok := splitArrayType(typeName); ok {
+			t.Error(typeName)
+		}
+	}
+}
+
+func TestGenerateArray(t *testing.T) {
+	spec := Spec{Package: "main", Types: map[string]string{"float32": "float32", "int": "int"}, Arrays: map[string]string{"[4]float32": "vec4", "[2]time.Time": "span"}, Methods: []string{"Map"}}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{
+		"\"time\"",
+		"type vec4 [4]float32",
+		"func (a vec4) Each(f func(float32)) vec4 {",
+		"func (a vec4) EachI(f func(int, float32)) vec4 {",
+		"func (a vec4) Map(f func(float32) float32) vec4 {",
+		"func (a vec4) MapInt(f func(float32) int) intList {",
+		"func (a vec4) Reduce(t1 float32, f func(float32, float32) float32) float32 {",
+		"func (a vec4) ReduceRight(t1 float32, f func(float32, float32) float32) float32 {",
+		"func (a vec4) ToList() float32List {",
+		"func (a span) MapFloat32(f func(time.Time) float32) float32List {",
+		"func (a span) ToList() []time.Time {",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+
+	spec.Declared = []string{"vec4"}
+	spec.Prefix = "F"
+	src, err = Generate(spec)
+	if err != nil || strings.Contains(string(src), "type vec4") || !strings.Contains(string(src), "func (a vec4) FToList(") {
+		t.Error(err, string(src))
+	}
+
+	if _, err := Generate(Spec{Package: "main", Arrays: map[string]string{"[]int": "ints"}}); err == nil {
+		t.Fail()
+	}
+}
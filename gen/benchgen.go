@@ -0,0 +1,66 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// generateBenchmarks - generate benchmarks for every selected parallel method which has a selected serial counterpart. Every method is benchmarked on lists of zero values of several sizes
+func generateBenchmarks(packageName string, m map[string]string, p plan) string {
+	// the imports, like the packages of the element types, are resolved from the code
+	code := fmt.Sprintf(`package %[1]s
+            `, packageName)
+
+	for _, typeName := range sortedTypes(m) {
+		v := m[typeName]
+		listName := v + "List"
+		if v[:1] == "*" {
+			listName = v[1:] + "List"
+		}
+		benchName := strings.Title(listName)
+		methods := p.methodsOf(listName)
+		// the methods which are only generated for the other targets, like FilterMap, are benchmarked with the first of
+		// them
+		targetType, targetSuffix := "", ""
+		for _, k := range sortedTypes(p.targets) {
+			if k != typeName && p.mapsTo(listName, k) {
+				targetType, targetSuffix = k, newTemplateData(listName, typeName, k, p.targets[k]).Suffix
+				break
+			}
+		}
+		benchmarked := p.generators.Filter(func(gen Generator) bool {
+			return methods[gen.name] && gen.serial != "" && gen.benchmark != "" && methods[gen.serial] && (targetType != "" || !strings.Contains(gen.benchmark, "%[2]s"))
+		})
+
+		code += fmt.Sprintf(`
+            func benchmark%[1]s(b *testing.B, f func(%[2]s)) {
+                for _, size := range []int{100, 10000, 1000000} {
+                    l := make(%[2]s, size)
+                    b.Run(strconv.Itoa(size), func(b *testing.B) {
+                        for i := 0; i < b.N; i++ {
+                            f(l)
+                        }
+                    })
+                }
+            }
+            `, benchName, listName)
+
+		benchmarked.Each(func(gen Generator) {
+			serial := p.generators.Filter(func(serialGen Generator) bool {
+				return serialGen.name == gen.serial
+			})[0]
+
+			for _, g := range []Generator{serial, gen} {
+				code += fmt.Sprintf(`
+            func Benchmark%[1]s%[2]s(b *testing.B) {
+                benchmark%[1]s(b, func(l %[3]s) {
+                    %[4]s
+                })
+            }
+            `, benchName, g.name, listName, fmt.Sprintf(g.benchmark, typeName, targetType, targetSuffix))
+			}
+		})
+	}
+
+	return code
+}
@@ -0,0 +1,23 @@
+package gen
+
+import (
+	"testing"
+)
+
+func TestBenchmarksGeneration(t *testing.T) {
+	result, err := addImports(generateBenchmarks("main", map[string]string{"string": "string"}, planOf("Map,PMap,Filter")), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "Benchmarks", result)
+
+	// FilterMap and PFilterMap are benchmarked with the first other target
+	types := map[string]string{"string": "string", "int": "int"}
+	p := planOf("FilterMap,PFilterMap,SortBy,PSort")
+	p.targets = types
+	result, err = addImports(generateBenchmarks("main", types, p), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "SortFilterMapBenchmarks", result)
+}
@@ -0,0 +1,141 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ClassifyTypes - find, by type-checking the element types in their package, the element types whose members cannot be
+// compared with == although their type expression does not show it (see comparableType), eg: the structs with a func
+// field, and the interfaces, whose comparison panics when the dynamic types of the values cannot be compared, with the
+// reason, and the element types whose members can be ordered with <, eg: 'time.Duration', with 'number' for the
+// numbers and 'string' for the strings (see orderedType), by type. The types, given like the Types of a Spec, are
+// type-checked in the package of the non-test Go files of dir, and the pointer types are checked for the types they
+// point to too (see Spec.Deref). An error is returned if the package cannot be type-checked, eg: because an import
+// cannot be found, with the types which could be checked
+func ClassifyTypes(dir string, m map[string]string) (map[string]string, map[string]string, error) {
+	checked := map[string]string{}
+	for _, typeName := range sortedTypes(m) {
+		checked[typeName] = typeName
+		if strings.HasPrefix(typeName, "*") {
+			checked[strings.TrimPrefix(typeName, "*")] = strings.TrimPrefix(typeName, "*")
+		}
+	}
+	qualified, imports, err := QualifyTypes(checked)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	files, pkgName := []*ast.File{}, ""
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil || pkgName != "" && file.Name.Name != pkgName {
+			continue
+		}
+		pkgName = file.Name.Name
+		files = append(files, file)
+	}
+	if pkgName == "" {
+		pkgName = "main"
+	}
+
+	// the types are declared as aliases in a file of the package, which imports their packages
+	src := "package " + pkgName + "\n\nimport (\n"
+	for _, path := range imports {
+		src += "\t" + strconv.Quote(path) + "\n"
+	}
+	for _, name := range sortedTypes(standardPackages) {
+		for typeName := range qualified {
+			if strings.Contains(typeName, name+".") {
+				src += "\t" + strconv.Quote(standardPackages[name]) + "\n"
+				break
+			}
+		}
+	}
+	src += ")\n\n"
+	aliases := map[string]string{}
+	for i, typeName := range sortedTypes(qualified) {
+		alias := fmt.Sprintf("fungenChecked%d", i)
+		aliases[alias] = qualified[typeName]
+		src += "type " + alias + " = " + typeName + "\n"
+	}
+	file, err := parser.ParseFile(fset, filepath.Join(dir, "fungen_checked.go"), src, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	files = append(files, file)
+
+	var importErr error
+	config := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			if typeErr, ok := err.(types.Error); ok && importErr == nil && strings.Contains(typeErr.Msg, "could not import") {
+				importErr = err
+			}
+		},
+	}
+	pkg, _ := config.Check(pkgName, fset, files, nil)
+
+	incomparable, ordered := map[string]string{}, map[string]string{}
+	for alias, typeName := range aliases {
+		obj, ok := pkg.Scope().Lookup(alias).(*types.TypeName)
+		if !ok || obj.Type() == types.Typ[types.Invalid] {
+			continue
+		}
+		if reason := incomparableReason(obj.Type(), types.RelativeTo(pkg)); reason != "" {
+			incomparable[typeName] = reason
+		}
+		if basic, ok := obj.Type().Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+			ordered[typeName] = "string"
+		} else if ok && basic.Info()&(types.IsInteger|types.IsFloat) != 0 {
+			ordered[typeName] = "number"
+		}
+	}
+	return incomparable, ordered, importErr
+}
+
+// incomparableReason - describe why the members of a type cannot be compared with ==, or nothing if they can, eg: 'its
+// field 'Run' of type 'func()' cannot be compared'
+func incomparableReason(t types.Type, qualifier types.Qualifier) string {
+	if types.IsInterface(t) {
+		return "it is an interface, comparing its values panics when their dynamic types cannot be compared"
+	}
+	if types.Comparable(t) {
+		return ""
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		return "slices cannot be compared"
+	case *types.Map:
+		return "maps cannot be compared"
+	case *types.Signature:
+		return "functions cannot be compared"
+	case *types.Array:
+		return fmt.Sprintf("its elements of type '%s' cannot be compared", types.TypeString(u.Elem(), qualifier))
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if field := u.Field(i); !types.Comparable(field.Type()) {
+				return fmt.Sprintf("its field '%s' of type '%s' cannot be compared", field.Name(), types.TypeString(field.Type(), qualifier))
+			}
+		}
+	}
+	return "it cannot be compared"
+}
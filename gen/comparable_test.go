@@ -0,0 +1,136 @@
+package gen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClassifyTypes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package models
+
+import "time"
+
+type Task struct {
+	Name string
+	Run  func() error
+}
+
+type Grid [2][]int
+
+type Event struct {
+	At   time.Time
+	Task *Task
+}
+
+type Shape interface{ Area() float64 }
+
+type Celsius float64
+
+type Name string
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "models.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := map[string]string{"Task": "Task", "*Task": "TaskPtr", "Grid": "Grid", "Event": "Event", "Shape": "Shape", "time.Time": "Time", "error": "err", "int": "int", "Unknown": "Unknown", "Celsius": "Celsius", "*Celsius": "CelsiusPtr", "Name": "Name", "time.Duration": "Duration"}
+	result, ordered, err := ClassifyTypes(dir, m)
+	expected := map[string]string{
+		"Task":  "its field 'Run' of type 'func() error' cannot be compared",
+		"Grid":  "its elements of type '[]int' cannot be compared",
+		"Shape": "it is an interface, comparing its values panics when their dynamic types cannot be compared",
+		"error": "it is an interface, comparing its values panics when their dynamic types cannot be compared",
+	}
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Error(err, result)
+	}
+
+	if !reflect.DeepEqual(ordered, map[string]string{"Celsius": "number", "Name": "string", "int": "number", "time.Duration": "number"}) {
+		t.Error(ordered)
+	}
+
+	result, ordered, err = ClassifyTypes(filepath.Join(dir, "missing"), map[string]string{"[]int": "ints", "func()": "funcs", "string": "string"})
+	if err != nil || !reflect.DeepEqual(result, map[string]string{"[]int": "slices cannot be compared", "func()": "functions cannot be compared"}) || !reflect.DeepEqual(ordered, map[string]string{"string": "string"}) {
+		t.Error(err, result, ordered)
+	}
+}
+
+func TestGenerateIncomparable(t *testing.T) {
+	spec := Spec{
+		Package:      "main",
+		Types:        map[string]string{"Task": "Task", "*Task": "TaskPtr", "Point": "Point"},
+		Incomparable: map[string]string{"Task": "its field 'Run' of type 'func()' cannot be compared"},
+		Deref:        true,
+	}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, unexpected := range []string{"func (l TaskList) Contains(", "func (l TaskList) ToSet(", "func (l TaskPtrList) Unique(", "UniqueByTask(", "PGroupByTask("} {
+		if strings.Contains(code, unexpected) {
+			t.Error(unexpected)
+		}
+	}
+	for _, expected := range []string{"func (l TaskList) SortBy(", "func (l TaskList) UniqueByTaskPtr(", "func (l PointList) Contains("} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+}
+
+func TestGenerateOrdered(t *testing.T) {
+	spec := Spec{
+		Package: "main",
+		Types:   map[string]string{"int": "int", "string": "Str", "bool": "bool", "Celsius": "Celsius", "Name": "Name"},
+		Ordered: map[string]string{"Celsius": "number", "Name": "string"},
+	}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"func (l intList) Sum() int {", "func (l intList) Average() float64 {", "func (l StrList) Max() (string, bool) {", "func (l StrList) Sort() StrList {", "func (l CelsiusList) Sum() Celsius {", "func (l NameList) Min() (Name, bool) {", "func (l StrList) TrimSpaceAll() StrList {", "l2[i] = strings.TrimSpace(t)", "l2[i] = Name(strings.ToLower(string(t)))", "func (l NameList) JoinNonEmpty(sep string) string {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	for _, unexpected := range []string{"func (l StrList) Sum(", "func (l NameList) Average(", "func (l boolList) Min(", "func (l boolList) Sort(", "func (l intList) NonEmpty(", "func (l CelsiusList) ToUpperAll("} {
+		if strings.Contains(code, unexpected) {
+			t.Error(unexpected)
+		}
+	}
+}
+
+func TestGenerateEquality(t *testing.T) {
+	spec := Spec{
+		Package:  "main",
+		Types:    map[string]string{"Task": "Task", "Job": "Job"},
+		Methods:  []string{"Contains", "Unique", "ToSet"},
+		Equality: map[string]Equality{"TaskList": {Eq: "sameTask", Hash: "hashTask"}, "JobList": {Eq: "sameJob"}},
+
+		Incomparable: map[string]string{"Task": "its field 'Tags' of type '[]string' cannot be compared", "Job": "it cannot be compared"},
+	}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"if sameTask(member, t) {", "h := hashTask(t)", "type TaskSet map[uint64]TaskList", "func (l JobList) Unique() JobList {", "if sameJob(member, t) {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	if strings.Contains(code, "JobSet") {
+		t.Error("JobSet")
+	}
+}
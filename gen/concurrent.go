@@ -0,0 +1,29 @@
+package gen
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+// concurrentName - get the name of the concurrent variant of a map (see getConcurrentMapType), eg: 'concurrentUserIndex'
+// for 'userIndex' and 'ConcurrentUserIndex' for 'UserIndex'
+func concurrentName(name string) string {
+	if ast.IsExported(name) {
+		return "Concurrent" + name
+	}
+	return "concurrent" + strings.Title(name)
+}
+
+var concurrentMapTemplate = render.Template("ConcurrentMap")
+
+// getConcurrentMapType - get the concurrent variant of a map, backed by a sync.Map, with typed methods, and the
+// ToConcurrent method of the map. The keys and the values are returned in the lists of their types, like the methods
+// of the map (see generateMap)
+func getConcurrentMapType(name, keyType, valueType, keysType, valuesType string) string {
+	return render.Declarations(concurrentMapTemplate, struct {
+		mapData
+		ConcurrentName string
+	}{mapData{Name: name, KeyType: keyType, ValueType: valueType, KeysType: keysType, ValuesType: valuesType}, concurrentName(name)})
+}
@@ -0,0 +1,41 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConcurrentName(t *testing.T) {
+	if concurrentName("userIndex") != "concurrentUserIndex" || concurrentName("UserIndex") != "ConcurrentUserIndex" {
+		t.Fail()
+	}
+}
+
+func TestGenerateConcurrent(t *testing.T) {
+	spec := Spec{Package: "main", Types: map[string]string{"string": "Str"}, Maps: map[string]string{"map[string]time.Time": "timeIndex"}, Methods: []string{"Map"}, Concurrent: true}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{
+		"\"sync\"",
+		"type concurrentTimeIndex struct {\n\tm sync.Map\n}",
+		"func (m timeIndex) ToConcurrent() *concurrentTimeIndex {",
+		"func (c *concurrentTimeIndex) Load(k string) (time.Time, bool) {",
+		"func (c *concurrentTimeIndex) ComputeIfAbsent(k string, f func(string) time.Time) time.Time {",
+		"func (c *concurrentTimeIndex) Range(f func(string, time.Time) bool) {",
+		"func (c *concurrentTimeIndex) Keys() StrList {",
+		"func (c *concurrentTimeIndex) Values() []time.Time {",
+		"func (c *concurrentTimeIndex) ToMap() timeIndex {",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+
+	spec.Concurrent = false
+	if src, err := Generate(spec); err != nil || strings.Contains(string(src), "concurrentTimeIndex") {
+		t.Error(err, string(src))
+	}
+}
@@ -0,0 +1,105 @@
+package gen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// DeclarationName - get the name of a top-level declaration, or the name of the type of the receiver for a method
+func DeclarationName(decl ast.Decl) string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return d.Name.Name
+		}
+		recv := d.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		if ident, ok := recv.(*ast.Ident); ok {
+			return ident.Name
+		}
+	case *ast.GenDecl:
+		if len(d.Specs) == 0 {
+			return ""
+		}
+		switch spec := d.Specs[0].(type) {
+		case *ast.TypeSpec:
+			return spec.Name.Name
+		case *ast.ValueSpec:
+			return spec.Names[0].Name
+		}
+	}
+	return ""
+}
+
+// ListOf - get the longest list name that the declaration name starts with. The names of the generated benchmarks, tests
+// and constructors (eg: 'BenchmarkIntListMap', 'TestIntListMap', 'newIntListPool') contain the list name after a prefix
+// and with a capital first letter
+func ListOf(name string, lists map[string]string) string {
+	for _, prefix := range []string{"Benchmark", "benchmark", "Test", "Example", "new"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+
+	result := ""
+	for listName := range lists {
+		if len(listName) <= len(result) {
+			continue
+		}
+		if strings.HasPrefix(name, listName) || strings.HasPrefix(name, strings.Title(listName)) {
+			result = listName
+		}
+	}
+	return result
+}
+
+// removeMethods - remove the skipped methods of the lists from a generated source, with their doc comments, and the
+// functions of the lists which refer to them (eg: the benchmarks, tests and examples of the methods). A function refers
+// to a method if it is a function of its list (see ListOf) and it selects a member with the name of the method
+func removeMethods(src string, lists map[string]string, skipped map[string]map[string]bool) (string, error) {
+	if len(skipped) == 0 {
+		return src, nil
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	edits := []edit{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		remove := false
+		if fn.Recv != nil {
+			remove = skipped[DeclarationName(fn)][fn.Name.Name]
+		} else if methods := skipped[ListOf(fn.Name.Name, lists)]; len(methods) > 0 && fn.Body != nil {
+			ast.Inspect(fn.Body, func(node ast.Node) bool {
+				if selector, ok := node.(*ast.SelectorExpr); ok && methods[selector.Sel.Name] {
+					remove = true
+				}
+				return !remove
+			})
+		}
+		if !remove {
+			continue
+		}
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		edits = append(edits, edit{fset.Position(start).Offset, fset.Position(fn.End()).Offset, ""})
+	}
+
+	// the edits are applied from the end so that the offsets of the others stay valid
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, e := range edits {
+		src = src[:e.start] + e.text + src[e.end:]
+	}
+	return src, nil
+}
@@ -0,0 +1,33 @@
+package gen
+
+import "testing"
+
+func TestRemoveMethods(t *testing.T) {
+	src := `package models
+
+type intList []int
+
+// Sum returns the sum
+func (l intList) Sum() int { return 0 }
+
+// Len returns the length
+func (l intList) Len() int { return len(l) }
+
+func TestIntListSum(t *testing.T) {
+	intList{}.Sum()
+}
+
+func TestIntListLen(t *testing.T) {
+	intList{}.Len()
+}
+`
+	lists := map[string]string{"intList": "int"}
+	result, err := removeMethods(src, lists, map[string]map[string]bool{"intList": {"Sum": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "RemoveMethods", result)
+	if result, _ := removeMethods(src, lists, nil); result != src {
+		t.Fail()
+	}
+}
@@ -0,0 +1,91 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// docWidth - the width the lines of the package comment of GenerateDoc are wrapped at
+const docWidth = 100
+
+// GenerateDoc - generate the source of a doc.go file with the package comment of the package of a Spec, which lists the
+// types declared in the generated sources, by file name, with their exported methods, and the generated functions, so
+// that godoc readers get an index of the generated API. The sources are the files generated for the package, with their
+// tests left out
+func GenerateDoc(spec Spec, sources map[string][]byte) ([]byte, error) {
+	filenames := []string{}
+	for filename := range sources {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	methods := map[string][]string{}
+	types, functions := []string{}, []string{}
+	for _, filename := range filenames {
+		file, err := parser.ParseFile(token.NewFileSet(), filename, sources[filename], 0)
+		if err != nil {
+			return nil, fmt.Errorf("the code generated for %s is not valid: %s", filename, err)
+		}
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if typeSpec, ok := spec.(*ast.TypeSpec); ok && !contains(types, typeSpec.Name.Name) {
+						types = append(types, typeSpec.Name.Name)
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					functions = append(functions, d.Name.Name)
+				} else if typeName := DeclarationName(d); d.Name.IsExported() && !contains(methods[typeName], d.Name.Name) {
+					methods[typeName] = append(methods[typeName], d.Name.Name)
+				}
+			}
+		}
+	}
+	sort.Strings(types)
+	sort.Strings(functions)
+
+	comment := []string{fmt.Sprintf("Package %s has the code generated by fungen: the types and their methods, and the functions.", spec.Package)}
+	if len(types) > 0 {
+		comment = append(comment, "", "# Types", "")
+		for _, typeName := range types {
+			item := typeName
+			if names := methods[typeName]; len(names) > 0 {
+				sort.Strings(names)
+				item += ": " + strings.Join(names, ", ")
+			}
+			comment = append(comment, wrapDocItem(item)...)
+		}
+	}
+	if len(functions) > 0 {
+		comment = append(comment, "", "# Functions", "")
+		comment = append(comment, wrapDocItem(strings.Join(functions, ", "))...)
+	}
+
+	code := spec.Header
+	for _, line := range comment {
+		code += strings.TrimRight("// "+line, " ") + "\n"
+	}
+	code += "package " + spec.Package + "\n"
+	return Format([]byte(code), "the package comment")
+}
+
+// wrapDocItem - wrap an item of a list of the package comment of GenerateDoc at docWidth, with the lines after the first
+// one indented like gofmt indents them, eg: '  - intList: All, Any, ...'
+func wrapDocItem(item string) []string {
+	lines := []string{}
+	line := "  -"
+	for _, word := range strings.Fields(item) {
+		if len(line)+1+len(word) > docWidth && strings.TrimSpace(line) != "-" {
+			lines = append(lines, line)
+			line = "   "
+		}
+		line += " " + word
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,60 @@
+package gen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDoc(t *testing.T) {
+	spec := Spec{Package: "models", Header: "// Code generated by fungen; DO NOT EDIT.\n\n"}
+	sources := map[string][]byte{
+		"b.go": []byte("package models\n\ntype intList []int\n\nfunc (l intList) Map(f func(int) int) intList { return l }\n\nfunc (l *intList) Filter(f func(int) bool) {}\n\nfunc (l intList) search() {}\n\nfunc intListFromChan() {}\n"),
+		"a.go": []byte("package models\n\ntype (\n\tintListFuture struct{}\n\tStrList []string\n)\n\nfunc (f *intListFuture) Wait() intList { return nil }\n\nfunc Map[T any](l []T) []T { return l }\n"),
+	}
+	src, err := GenerateDoc(spec, sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `// Code generated by fungen; DO NOT EDIT.
+
+// Package models has the code generated by fungen: the types and their methods, and the functions.
+//
+// # Types
+//
+//   - StrList
+//   - intList: Filter, Map
+//   - intListFuture: Wait
+//
+// # Functions
+//
+//   - Map, intListFromChan
+package models
+`
+	if string(src) != expected {
+		t.Errorf("got:\n%s\nexpected:\n%s", src, expected)
+	}
+
+	if _, err := GenerateDoc(spec, map[string][]byte{"a.go": []byte("package models\n\nfunc (")}); err == nil || !strings.Contains(err.Error(), "a.go") {
+		t.Error(err)
+	}
+}
+
+func TestWrapDocItem(t *testing.T) {
+	words := []string{}
+	for i := 0; i < 30; i++ {
+		words = append(words, "Method,")
+	}
+	lines := wrapDocItem("intList: " + strings.Join(words, " "))
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "  - intList: Method,") || !strings.HasPrefix(lines[1], "    Method,") {
+		t.Errorf("%q", lines)
+	}
+	for _, line := range lines {
+		if len(line) > docWidth {
+			t.Errorf("%q is longer than %d", line, docWidth)
+		}
+	}
+	if lines := wrapDocItem("StrList"); !reflect.DeepEqual(lines, []string{"  - StrList"}) {
+		t.Errorf("%q", lines)
+	}
+}
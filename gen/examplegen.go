@@ -0,0 +1,656 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// exampleData - the data which the example of a method is generated with: the names of the types and the members of
+// the list the example starts with. The members are the literals of testValues, or zero values for the types without
+// literals, in which case the examples only print the results which do not depend on the members, like lengths
+type exampleData struct {
+	listName, typeName, targetType, suffix string
+	values                                 []string
+	literal                                bool
+}
+
+// show - get the output of fmt.Println for the members of the example list at the indexes
+func (data exampleData) show(indexes ...int) string {
+	members := []string{}
+	for _, i := range indexes {
+		members = append(members, strings.Trim(data.values[i], `"`))
+	}
+	return "[" + strings.Join(members, " ") + "]"
+}
+
+// member - get the output of fmt.Println for a member of the example list
+func (data exampleData) member(i int) string {
+	return strings.Trim(data.values[i], `"`)
+}
+
+// generateExamples - generate the examples of the selected methods for the lists of the types, for -with-examples.
+// The examples are named after the methods and functions they show (see exampleName), so that godoc shows them with
+// the methods and functions
+func generateExamples(packageName string, m map[string]string, selected map[string]string, p plan, prefix, suffix string) string {
+	code := fmt.Sprintf(`package %[1]s
+            `, packageName)
+
+	for _, typeName := range sortedTypes(selected) {
+		listName := strings.TrimPrefix(selected[typeName], "*") + "List"
+		methods := p.methodsOf(listName)
+		shown := p.generators.Filter(func(gen Generator) bool {
+			return methods[gen.name] && gen.example != nil
+		})
+		values := testValues(typeName)
+
+		list := fmt.Sprintf("l := %s{%s}", listName, strings.Join(values, ", "))
+		if values[0] == "zero" {
+			list = fmt.Sprintf("var zero %s\n%s", typeName, list)
+		}
+
+		shown.Each(func(gen Generator) {
+			targets := map[string]string{typeName: ""}
+			if gen.needMapToMap {
+				targets = map[string]string{}
+				for k, v := range m {
+					if p.mapsTo(listName, k) {
+						targets[k] = v
+					}
+				}
+				targets[typeName] = ""
+			}
+
+			for _, targetType := range sortedTypes(targets) {
+				if gen.keyed && !p.keyable(targetType) {
+					continue
+				}
+				data := exampleData{
+					listName:   listName,
+					typeName:   typeName,
+					targetType: targetType,
+					suffix:     getTestSuffix(targets[targetType]),
+					values:     values,
+					literal:    values[0] != "zero",
+				}
+				body := gen.example(data)
+				if body == "" {
+					continue
+				}
+
+				name := exampleName(gen.method(listName, typeName, targetType, targets[targetType]), prefix, suffix)
+				code += fmt.Sprintf(`
+            func Example%[1]s() {
+                %[2]s
+                %[3]s
+            }
+            `, name, list, body)
+			}
+		})
+
+		for _, field := range p.fields[listName] {
+			name := exampleName(getPluckFunction(listName, typeName, field, p.listOf(field.Type)), prefix, suffix)
+			code += fmt.Sprintf(`
+            func Example%[1]s() {
+                %[2]s
+                fmt.Println(len(l.Pluck%[3]s()))
+                // Output:
+                // 3
+            }
+            `, name, list, field.Name)
+		}
+	}
+
+	return code
+}
+
+// exampleName - get the name of the example of the first function declared by the code of a method, after 'Example':
+// 'intList_Map' for a method on a list, with the -prefix and -suffix, 'intListPipeline_Map' for a method on another
+// type and 'intListFromChan' for a function
+func exampleName(code, prefix, suffix string) string {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+code, 0)
+	if err != nil {
+		return ""
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if fn.Recv == nil || len(fn.Recv.List) == 0 {
+			return fn.Name.Name
+		}
+		recv := fn.Recv.List[0]
+		if len(recv.Names) == 1 && recv.Names[0].Name == "l" {
+			return DeclarationName(fn) + "_" + prefix + fn.Name.Name + suffix
+		}
+		return DeclarationName(fn) + "_" + fn.Name.Name
+	}
+	return ""
+}
+
+// output - get the comment with the expected output of an example
+func output(lines ...string) string {
+	return "\n// Output:\n// " + strings.Join(lines, "\n// ")
+}
+
+// getIdentity - get the function of an example mapping the members to themselves, or to zero values of the target type
+func getIdentity(data exampleData) string {
+	if data.suffix == "" {
+		return fmt.Sprintf("func(t %[1]s) %[1]s { return t }", data.typeName)
+	}
+	return getZero(data)
+}
+
+// getZero - get the function of an example mapping the members to zero values of the target type
+func getZero(data exampleData) string {
+	return fmt.Sprintf("func(%[1]s) %[2]s {\nvar u %[2]s\nreturn u\n}", data.typeName, data.targetType)
+}
+
+// getMappedOutput - get the code printing the result of mapping the example list with getIdentity, which is the list
+// itself, or only its length if it is mapped to another type or has no literals, with its output. The result is printed
+// by print, eg: 'fmt.Println(%s, err)', whose other values are printed as extra
+func getMappedOutput(data exampleData, call, print, extra string) string {
+	if data.suffix == "" && data.literal {
+		return fmt.Sprintf(print, call) + output(data.show(0, 1, 2)+extra)
+	}
+	return fmt.Sprintf(print, "len("+call+")") + output("3"+extra)
+}
+
+// getPredicate - get the function of an example which is true for the members of the example list except the second
+// one, or a function which is always true if the type has no literals
+func getPredicate(data exampleData) string {
+	if !data.literal {
+		return fmt.Sprintf("func(%s) bool { return true }", data.typeName)
+	}
+	return fmt.Sprintf("func(t %s) bool { return t != %s }", data.typeName, data.values[1])
+}
+
+func getMapExample(data exampleData) string {
+	return getMappedOutput(data, fmt.Sprintf("l.Map%s(%s)", data.suffix, getIdentity(data)), "fmt.Println(%s)", "")
+}
+
+func getPMapExample(data exampleData) string {
+	return getMappedOutput(data, fmt.Sprintf("l.PMap%s(%s)", data.suffix, getIdentity(data)), "fmt.Println(%s)", "")
+}
+
+func getPMapRateExample(data exampleData) string {
+	return getMappedOutput(data, fmt.Sprintf("l.PMapRate%s(%s, 100)", data.suffix, getIdentity(data)), "fmt.Println(%s)", "")
+}
+
+func getPMapTimeoutExample(data exampleData) string {
+	call := fmt.Sprintf("result, timedOut := l.PMapTimeout%s(time.Second, %s)\n", data.suffix, getIdentity(data))
+	return call + getMappedOutput(data, "result", "fmt.Println(%s, timedOut)", " false")
+}
+
+func getPMapRetryExample(data exampleData) string {
+	f := fmt.Sprintf("func(t %[1]s) (%[1]s, error) { return t, nil }", data.typeName)
+	if data.suffix != "" {
+		f = fmt.Sprintf("func(%[1]s) (%[2]s, error) {\nvar u %[2]s\nreturn u, nil\n}", data.typeName, data.targetType)
+	}
+	call := fmt.Sprintf("result, err := l.PMapRetry%s(%s, 3, time.Millisecond)\n", data.suffix, f)
+	return call + getMappedOutput(data, "result", "fmt.Println(%s, err)", " <nil>")
+}
+
+func getPFlatMapExample(data exampleData) string {
+	if data.suffix == "" && data.literal {
+		return fmt.Sprintf("fmt.Println(l.PFlatMap(func(t %[1]s) []%[1]s { return []%[1]s{t, t} }))", data.typeName) + output(data.show(0, 0, 1, 1, 2, 2))
+	}
+	return fmt.Sprintf("fmt.Println(len(l.PFlatMap%[1]s(func(%[2]s) []%[3]s { return make([]%[3]s, 2) })))", data.suffix, data.typeName, data.targetType) + output("6")
+}
+
+func getPGroupByExample(data exampleData) string {
+	return fmt.Sprintf("groups := l.PGroupBy%s(%s)\nfmt.Println(len(groups))", data.suffix, getZero(data)) + output("1")
+}
+
+func getMapAsyncExample(data exampleData) string {
+	call := fmt.Sprintf("future := l.MapAsync%s(%s)\n", data.suffix, getIdentity(data))
+	return call + getMappedOutput(data, "future.Wait()", "fmt.Println(%s)", "")
+}
+
+// getFilteredOutput - get the example of a method filtering the list with getPredicate, given the output for the types
+// with literals, and the number of members kept for the other types
+func getFilteredOutput(data exampleData, method, literalOutput, kept string) string {
+	if data.literal {
+		return fmt.Sprintf("fmt.Println(l.%s(%s))", method, getPredicate(data)) + output(literalOutput)
+	}
+	return fmt.Sprintf("fmt.Println(len(l.%s(%s)))", method, getPredicate(data)) + output(kept)
+}
+
+func getFilterExample(data exampleData) string {
+	return getFilteredOutput(data, "Filter", data.show(0, 2), "3")
+}
+
+func getPFilterExample(data exampleData) string {
+	return getFilteredOutput(data, "PFilter", data.show(0, 2), "3")
+}
+
+func getTakeWhileExample(data exampleData) string {
+	return getFilteredOutput(data, "TakeWhile", data.show(0), "3")
+}
+
+func getDropWhileExample(data exampleData) string {
+	return getFilteredOutput(data, "DropWhile", data.show(1, 2), "0")
+}
+
+func getTakeExample(data exampleData) string {
+	if data.literal {
+		return "fmt.Println(l.Take(2))" + output(data.show(0, 1))
+	}
+	return "fmt.Println(len(l.Take(2)))" + output("2")
+}
+
+func getDropExample(data exampleData) string {
+	if data.literal {
+		return "fmt.Println(l.Drop(1))" + output(data.show(1, 2))
+	}
+	return "fmt.Println(len(l.Drop(1)))" + output("2")
+}
+
+// getFoldExample - get the example of Reduce or ReduceRight with a function returning the member, so that the result
+// is the last member the function is applied to. It is only generated for the types with literals
+func getFoldExample(data exampleData, method, arguments string, last int) string {
+	if !data.literal {
+		return ""
+	}
+	return fmt.Sprintf("var initial %[2]s\nfmt.Println(l.%[1]s(initial, func(%[3]s %[2]s) %[2]s { return t }))", method, data.typeName, arguments) + output(data.member(last))
+}
+
+func getReduceExample(data exampleData) string {
+	return getFoldExample(data, "Reduce", "_, t", 2)
+}
+
+func getReduceRightExample(data exampleData) string {
+	return getFoldExample(data, "ReduceRight", "t, _", 0)
+}
+
+func getEachExample(data exampleData) string {
+	if data.literal {
+		return fmt.Sprintf("l.Each(func(t %s) {\nfmt.Println(t)\n})", data.typeName) + output(data.member(0), data.member(1), data.member(2))
+	}
+	return fmt.Sprintf("calls := 0\nl.Each(func(%s) {\ncalls++\n})\nfmt.Println(calls)", data.typeName) + output("3")
+}
+
+func getEachIExample(data exampleData) string {
+	if data.literal {
+		return fmt.Sprintf("l.EachI(func(i int, t %s) {\nfmt.Println(i, t)\n})", data.typeName) + output("0 "+data.member(0), "1 "+data.member(1), "2 "+data.member(2))
+	}
+	return fmt.Sprintf("l.EachI(func(i int, _ %s) {\nfmt.Println(i)\n})", data.typeName) + output("0", "1", "2")
+}
+
+// getCheckExample - get the example of a method checking the members with getPredicate, like All or PCount, given
+// its output for the types with literals and for the other types
+func getCheckExample(data exampleData, method, literalOutput, otherOutput string) string {
+	if data.literal {
+		return fmt.Sprintf("fmt.Println(l.%s(%s))", method, getPredicate(data)) + output(literalOutput)
+	}
+	return fmt.Sprintf("fmt.Println(l.%s(%s))", method, getPredicate(data)) + output(otherOutput)
+}
+
+func getAllExample(data exampleData) string {
+	return getCheckExample(data, "All", "false", "true")
+}
+
+func getAnyExample(data exampleData) string {
+	return getCheckExample(data, "Any", "true", "true")
+}
+
+func getPAllExample(data exampleData) string {
+	return getCheckExample(data, "PAll", "false", "true")
+}
+
+func getPAnyExample(data exampleData) string {
+	return getCheckExample(data, "PAny", "true", "true")
+}
+
+func getPCountExample(data exampleData) string {
+	return getCheckExample(data, "PCount", "2", "3")
+}
+
+func getPSortExample(data exampleData) string {
+	return getSortExample("PSort", data)
+}
+
+// getSortExample - get the example of a method sorting a copy of the list with a function, like PSort
+func getSortExample(method string, data exampleData) string {
+	switch {
+	case data.typeName == "bool":
+		return fmt.Sprintf("fmt.Println(l.%s(func(a, b bool) bool { return !a && b }))", method) + output("[false true true]")
+	case data.literal:
+		return fmt.Sprintf("fmt.Println(l.%s(func(a, b %s) bool { return a > b }))", method, data.typeName) + output(data.show(2, 1, 0))
+	}
+	return fmt.Sprintf("fmt.Println(len(l.%s(func(_, _ %s) bool { return false })))", method, data.typeName) + output("3")
+}
+
+// getReceiveExample - get the code receiving the members of a channel 'ch' and printing them, or their number if the
+// type has no literals
+func getReceiveExample(data exampleData, ch string, indexes ...int) string {
+	if !data.literal {
+		return fmt.Sprintf("members := 0\nfor range %s {\nmembers++\n}\nfmt.Println(members)", ch) + output(fmt.Sprint(len(indexes)))
+	}
+	lines := []string{}
+	for _, i := range indexes {
+		lines = append(lines, data.member(i))
+	}
+	return fmt.Sprintf("for t := range %s {\nfmt.Println(t)\n}", ch) + output(lines...)
+}
+
+// getExampleChannel - get the code sending the members of the example list to a closed channel 'ch'
+func getExampleChannel(data exampleData) string {
+	return fmt.Sprintf("ch := make(chan %s, len(l))\nfor _, member := range l {\nch <- member\n}\nclose(ch)\n", data.typeName)
+}
+
+func getToChanExample(data exampleData) string {
+	return getReceiveExample(data, "l.ToChan()", 0, 1, 2)
+}
+
+func getFromChanExample(data exampleData) string {
+	return getExampleChannel(data) + getMappedOutput(data, data.listName+"FromChan(ch)", "fmt.Println(%s)", "")
+}
+
+func getMapChanExample(data exampleData) string {
+	if data.suffix != "" {
+		data.literal = false
+	}
+	return getExampleChannel(data) + getReceiveExample(data, fmt.Sprintf("%sMapChan%s(ch, %s)", data.listName, data.suffix, getIdentity(data)), 0, 1, 2)
+}
+
+func getFilterChanExample(data exampleData) string {
+	if !data.literal {
+		return getExampleChannel(data) + getReceiveExample(data, fmt.Sprintf("%sFilterChan(ch, %s)", data.listName, getPredicate(data)), 0, 1, 2)
+	}
+	return getExampleChannel(data) + getReceiveExample(data, fmt.Sprintf("%sFilterChan(ch, %s)", data.listName, getPredicate(data)), 0, 2)
+}
+
+func getPipelineExample(data exampleData) string {
+	call := fmt.Sprintf("l.Pipeline().Filter(%s).Map%s(%s).Collect()", getPredicate(data), data.suffix, getIdentity(data))
+	if data.suffix == "" && data.literal {
+		return fmt.Sprintf("fmt.Println(%s)", call) + output(data.show(0, 2))
+	}
+	if data.literal {
+		return fmt.Sprintf("fmt.Println(len(%s))", call) + output("2")
+	}
+	return fmt.Sprintf("fmt.Println(len(%s))", call) + output("3")
+}
+
+// getMapFilteredExample - get the example of FilterMap or PFilterMap, which are only generated for the other types
+func getMapFilteredExample(method string, data exampleData) string {
+	if data.suffix == "" {
+		return ""
+	}
+	if data.literal {
+		return fmt.Sprintf("fmt.Println(len(l.%s%s(%s, %s)))", method, data.suffix, getIdentity(data), getPredicate(data)) + output("2")
+	}
+	return fmt.Sprintf("fmt.Println(len(l.%s%s(%s, %s)))", method, data.suffix, getIdentity(data), getPredicate(data)) + output("3")
+}
+
+func getFilterMapExample(data exampleData) string {
+	return getMapFilteredExample("FilterMap", data)
+}
+
+func getPFilterMapExample(data exampleData) string {
+	return getMapFilteredExample("PFilterMap", data)
+}
+
+func getToSetExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(l.ToSet().Len())" + output("1")
+	}
+	// the members of the example list are not all different for every type, eg: 'true, false, true'
+	distinct := map[string]bool{}
+	for _, value := range data.values {
+		distinct[value] = true
+	}
+	return fmt.Sprintf("s := l.ToSet()\ns.Remove(%s)\nfmt.Println(s.Len(), s.Contains(%s), s.Contains(%s))", data.values[0], data.values[0], data.values[1]) + output(fmt.Sprintf("%d false true", len(distinct)-1))
+}
+
+func getToStackExample(data exampleData) string {
+	if !data.literal {
+		return "s := l.ToStack()\ns.Pop()\nfmt.Println(s.Len())" + output("2")
+	}
+	return fmt.Sprintf("s := l.ToStack()\ns.Push(%s)\ntop, _ := s.Pop()\nnext, _ := s.Peek()\nfmt.Println(top, next, s.Len())", data.values[0]) + output(fmt.Sprintf("%s %s 3", data.member(0), data.member(2)))
+}
+
+func getToImmutableExample(data exampleData) string {
+	if !data.literal {
+		return "im := l.ToImmutable()\nfmt.Println(im.Remove(0).Len(), im.Len())" + output("2 3")
+	}
+	return fmt.Sprintf("im := l.ToImmutable()\nim2 := im.Append(%s).Remove(0)\nfmt.Println(im.ToList(), im2.ToList())", data.values[0]) + output(fmt.Sprintf("[%[1]s %[2]s %[3]s] [%[2]s %[3]s %[1]s]", data.member(0), data.member(1), data.member(2)))
+}
+
+func getToSortedExample(data exampleData) string {
+	if !data.literal || data.typeName == "bool" {
+		return fmt.Sprintf("s := l.ToSorted(func(_, _ %s) bool { return false })\ns.Insert(l[0])\nfmt.Println(s.Len(), s.Contains(l[0]))", data.typeName) + output("4 true")
+	}
+	return fmt.Sprintf("s := l.ToSorted(func(a, b %s) bool { return a > b })\ns.Insert(%s)\nfmt.Println(s.ToList(), s.Contains(%s), s.Range(%s, %s))", data.typeName, data.values[1], data.values[0], data.values[1], data.values[0]) + output(fmt.Sprintf("%s true %s", data.show(2, 1, 1, 0), data.show(1, 1, 0)))
+}
+
+func getToQueueExample(data exampleData) string {
+	if !data.literal {
+		return "q := l.ToQueue()\nq.Dequeue()\nfmt.Println(q.Len())" + output("2")
+	}
+	return fmt.Sprintf("q := l.ToQueue()\nq.Enqueue(%s)\nfront, _ := q.Dequeue()\nnext, _ := q.Peek()\nfmt.Println(front, next, q.Len())", data.values[2]) + output(fmt.Sprintf("%s %s 3", data.member(0), data.member(1)))
+}
+
+func getToDequeExample(data exampleData) string {
+	if !data.literal {
+		return "d := l.ToDeque()\nd.PopFront()\nd.PopBack()\nfmt.Println(d.Len())" + output("1")
+	}
+	return fmt.Sprintf("d := l.ToDeque()\nd.PushFront(%s)\nd.PopBack()\nfront, _ := d.Front()\nback, _ := d.Back()\nfmt.Println(front, back, d.Len())", data.values[2]) + output(fmt.Sprintf("%s %s 3", data.member(2), data.member(1)))
+}
+
+func getFindExample(data exampleData) string {
+	if !data.literal {
+		return fmt.Sprintf("fmt.Println(l.Find(func(%s) bool { return true }).IsSome())", data.typeName) + output("true")
+	}
+	return fmt.Sprintf("found, ok := l.Find(func(t %s) bool { return t != %s }).Get()\nfmt.Println(found, ok)", data.typeName, data.values[0]) + output(data.member(1)+" true")
+}
+
+func getFirstExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(l.First().IsSome())" + output("true")
+	}
+	return fmt.Sprintf("fmt.Println(l.First().GetOr(%s))", data.values[1]) + output(data.member(0))
+}
+
+func getLastExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(l.Last().IsSome())" + output("true")
+	}
+	return fmt.Sprintf("fmt.Println(l.Last().GetOr(%s))", data.values[0]) + output(data.member(2))
+}
+
+func getMapResultExample(data exampleData) string {
+	if !data.literal {
+		return fmt.Sprintf("results := l.MapResult%[1]s(func(%[2]s) (%[3]s, error) {\nvar u %[3]s\nreturn u, errors.New(\"failed\")\n})\nfmt.Println(len(results), results[0].Err())", data.suffix, data.typeName, data.targetType) + output("3 failed")
+	}
+	return fmt.Sprintf("results := l.MapResult%[1]s(func(t %[2]s) (%[3]s, error) {\nvar u %[3]s\nif t == %[4]s {\nreturn u, errors.New(\"failed\")\n}\nreturn u, nil\n})\nfor _, result := range results {\nfmt.Println(result.Err())\n}", data.suffix, data.typeName, data.targetType, data.values[1]) + output("<nil>", "failed", "<nil>")
+}
+
+func getIterExample(data exampleData) string {
+	mapped := fmt.Sprintf("it := l.Iter().Filter(%s).Map%s(%s).Take(1)\n", getPredicate(data), data.suffix, getIdentity(data))
+	if data.suffix == "" && data.literal {
+		return mapped + "fmt.Println(l.CollectFromIter(it))" + output(data.show(0, 1, 2, 0))
+	}
+	return mapped + "_, ok := it.Next()\n_, more := it.Next()\nfmt.Println(ok, more)" + output("true false")
+}
+
+func getValuesExample(data exampleData) string {
+	if !data.literal {
+		return "members := 0\nfor range l.Values() {\nmembers++\n}\nfmt.Println(members)" + output("3")
+	}
+	return "for t := range l.Values() {\nfmt.Println(t)\n}" + output(data.member(0), data.member(1), data.member(2))
+}
+
+func getEnumeratedExample(data exampleData) string {
+	if !data.literal {
+		return "for i := range l.Enumerated() {\nfmt.Println(i)\n}" + output("0", "1", "2")
+	}
+	return "for i, t := range l.Enumerated() {\nfmt.Println(i, t)\n}" + output("0 "+data.member(0), "1 "+data.member(1), "2 "+data.member(2))
+}
+
+func getFromSeqExample(data exampleData) string {
+	seq := fmt.Sprintf("seq := func(yield func(%s) bool) {\nfor _, t := range l {\nif !yield(t) {\nreturn\n}\n}\n}\n", data.typeName)
+	return seq + getMappedOutput(data, data.listName+"FromSeq(seq)", "fmt.Println(%s)", "")
+}
+
+func getFlattenExample(data exampleData) string {
+	return fmt.Sprintf("l = %[1]s{make(%[2]s, 2), make(%[2]s, 1)}\nfmt.Println(len(l.Flatten()))", data.listName, data.typeName) + output("3")
+}
+
+func getFlatMapExample(data exampleData) string {
+	return fmt.Sprintf("fmt.Println(len(l.FlatMap%[1]s(func(%[2]s) []%[3]s { return make([]%[3]s, 2) })))", data.suffix, data.typeName, data.targetType) + output("6")
+}
+
+func getContainsExample(data exampleData) string {
+	return "fmt.Println(l.Contains(l[1]))" + output("true")
+}
+
+func getUniqueExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(len(l.Unique()))" + output("1")
+	}
+	// the members of the example list are shown once, in the order of their first occurrence
+	first := []int{}
+	for i, value := range data.values {
+		if !contains(data.values[:i], value) {
+			first = append(first, i)
+		}
+	}
+	return "l = append(l, l...)\nfmt.Println(l.Unique())" + output(data.show(first...))
+}
+
+func getUniqueByExample(data exampleData) string {
+	return fmt.Sprintf("fmt.Println(len(l.UniqueBy%s(%s)))", data.suffix, getZero(data)) + output("1")
+}
+
+func getSortByExample(data exampleData) string {
+	return getSortExample("SortBy", data)
+}
+
+func getFilterInPlaceExample(data exampleData) string {
+	if !data.literal {
+		return fmt.Sprintf("l = l.FilterInPlace(func(_ %s) bool { return true })\nfmt.Println(len(l))", data.typeName) + output("3")
+	}
+	return fmt.Sprintf("l = l.FilterInPlace(func(t %s) bool { return t != %s })\nfmt.Println(l)", data.typeName, data.values[1]) + output(data.show(0, 2))
+}
+
+func getMapInPlaceExample(data exampleData) string {
+	if !data.literal {
+		return fmt.Sprintf("l.MapInPlace(func(t %[1]s) %[1]s { return t })\nfmt.Println(len(l))", data.typeName) + output("3")
+	}
+	return fmt.Sprintf("l.MapInPlace(func(_ %[1]s) %[1]s { return %[2]s })\nfmt.Println(l)", data.typeName, data.values[0]) + output(data.show(0, 0, 0))
+}
+
+func getReverseInPlaceExample(data exampleData) string {
+	if !data.literal {
+		return "l.ReverseInPlace()\nfmt.Println(len(l))" + output("3")
+	}
+	return "l.ReverseInPlace()\nfmt.Println(l)" + output(data.show(2, 1, 0))
+}
+
+func getSortInPlaceExample(data exampleData) string {
+	return getSortExample("SortInPlace", data)
+}
+
+func getSampleWeightedExample(data exampleData) string {
+	if !data.literal {
+		return fmt.Sprintf("r := rand.New(rand.NewSource(1))\n_, ok := l.SampleWeighted(r, func(_ %s) float64 { return 1 })\nfmt.Println(ok)", data.typeName) + output("true")
+	}
+	return fmt.Sprintf("r := rand.New(rand.NewSource(1))\nfmt.Println(l.SampleWeighted(r, func(t %[1]s) float64 {\nif t == %[2]s {\nreturn 1\n}\nreturn 0\n}))", data.typeName, data.values[1]) + output(data.member(1)+" true")
+}
+
+func getCompactNilExample(data exampleData) string {
+	return fmt.Sprintf("l = append(l, new(%s))\nfmt.Println(len(l.CompactNil()))", strings.TrimPrefix(data.typeName, "*")) + output("1")
+}
+
+func getDerefOrExample(data exampleData) string {
+	return fmt.Sprintf("var def %s\nfmt.Println(len(l.DerefOr(def)))", strings.TrimPrefix(data.typeName, "*")) + output("3")
+}
+
+func getSumExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(l.Sum() == 0)" + output("true")
+	}
+	if data.values[0] == "1.5" {
+		return "fmt.Println(l.Sum())" + output("7.5")
+	}
+	return "fmt.Println(l.Sum())" + output("6")
+}
+
+func getAverageExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(l.Average())" + output("0")
+	}
+	if data.values[0] == "1.5" {
+		return "fmt.Println(l.Average())" + output("2.5")
+	}
+	return "fmt.Println(l.Average())" + output("2")
+}
+
+func getMinExample(data exampleData) string {
+	if !data.literal {
+		return "_, ok := l.Min()\nfmt.Println(ok)" + output("true")
+	}
+	return "fmt.Println(l.Min())" + output(data.member(0)+" true")
+}
+
+func getMaxExample(data exampleData) string {
+	if !data.literal {
+		return "_, ok := l.Max()\nfmt.Println(ok)" + output("true")
+	}
+	return "fmt.Println(l.Max())" + output(data.member(2)+" true")
+}
+
+func getTrimSpaceAllExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(len(l.TrimSpaceAll()))" + output("3")
+	}
+	return "l = append(l, \" d \")\nfmt.Printf(\"%q\\n\", l.TrimSpaceAll())" + output(fmt.Sprintf("[%s %s %s \"d\"]", data.values[0], data.values[1], data.values[2]))
+}
+
+func getToLowerAllExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(len(l.ToLowerAll()))" + output("3")
+	}
+	return "fmt.Println(l.ToLowerAll())" + output(data.show(0, 1, 2))
+}
+
+func getToUpperAllExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(len(l.ToUpperAll()))" + output("3")
+	}
+	return "fmt.Println(l.ToUpperAll())" + output(strings.ToUpper(data.show(0, 1, 2)))
+}
+
+func getNonEmptyExample(data exampleData) string {
+	// the zero values of the named string types are empty
+	nonEmpty := "0"
+	if data.literal {
+		nonEmpty = "3"
+	}
+	return "l = append(l, \"\")\nfmt.Println(len(l), len(l.NonEmpty()))" + output("4 "+nonEmpty)
+}
+
+func getJoinNonEmptyExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(l.JoinNonEmpty(\", \") == \"\")" + output("true")
+	}
+	return "l = append(l, \"\")\nfmt.Println(l.JoinNonEmpty(\", \"))" + output(fmt.Sprintf("%s, %s, %s", data.member(0), data.member(1), data.member(2)))
+}
+
+func getSortedExample(data exampleData) string {
+	if !data.literal {
+		return "fmt.Println(len(l.Sort()))" + output("3")
+	}
+	return "l[0], l[2] = l[2], l[0]\nfmt.Println(l.Sort())" + output(data.show(0, 1, 2))
+}
+
+func getZipExample(data exampleData) string {
+	if data.literal && data.suffix == "" {
+		return "fmt.Println(l.Zip(l[1:]))" + output(fmt.Sprintf("[{%s %s} {%s %s}]", data.member(0), data.member(1), data.member(1), data.member(2)))
+	}
+	return fmt.Sprintf("fmt.Println(len(l.Zip%s(nil)))", data.suffix) + output("0")
+}
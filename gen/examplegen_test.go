@@ -0,0 +1,23 @@
+package gen
+
+import (
+	"testing"
+)
+
+func TestExampleName(t *testing.T) {
+	if exampleName(getFilterFunction("intList", "int", "", ""), "F", "") != "intList_FFilter" {
+		t.Fail()
+	}
+	if exampleName(getFromChanFunction("intList", "int", "", ""), "F", "") != "intListFromChan" {
+		t.Fail()
+	}
+	if exampleName(getPipelineMapFunction("intList", "int", "string", "Str"), "F", "") != "intListPipeline_MapStr" {
+		t.Fail()
+	}
+}
+
+func TestGenerateExamples(t *testing.T) {
+	m := map[string]string{"int": "int", "*point": "*point"}
+	checkGolden(t, "TakeExamples", generateExamples("main", m, m, planOf("Take"), "", ""))
+	checkGolden(t, "MapExamples", generateExamples("main", m, map[string]string{"int": "int"}, planOf("Map"), "", ""))
+}
@@ -0,0 +1,58 @@
+package gen
+
+import (
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"log"
+	"strings"
+)
+
+// Format - format a generated source with go/format, or fail naming what was generated, eg: "type 'int'", and showing
+// the lines around the first syntax error if it is not valid Go code, so that an invalid file is never written
+func Format(src []byte, what string) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+			return nil, fmt.Errorf("the code generated for %s is not valid: %s\n%s", what, err, sourceContext(string(src), list[0].Pos.Line, list[0].Pos.Column))
+		}
+		return nil, fmt.Errorf("the code generated for %s is not valid: %s", what, err)
+	}
+	return formatted, nil
+}
+
+// sourceContext - get the lines of the source around a position, with a marker under the column
+func sourceContext(src string, line, column int) string {
+	lines := strings.Split(src, "\n")
+	context := ""
+	for i := line - 2; i <= line+2; i++ {
+		if i < 1 || i > len(lines) {
+			continue
+		}
+		context += fmt.Sprintf("%5d | %s\n", i, lines[i-1])
+		if i == line && column > 0 {
+			// the marker keeps the tabs of the line so that it is aligned with the column
+			prefix := lines[i-1]
+			if column-1 < len(prefix) {
+				prefix = prefix[:column-1]
+			}
+			indent := []rune{}
+			for _, r := range prefix {
+				if r != '\t' {
+					r = ' '
+				}
+				indent = append(indent, r)
+			}
+			context += fmt.Sprintf("%5s | %s^\n", "", string(indent))
+		}
+	}
+	return context
+}
+
+func f(s string) string {
+	formatted, err := format.Source([]byte(s))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return string(formatted)
+}
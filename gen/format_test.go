@@ -0,0 +1,34 @@
+package gen
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSourceContext(t *testing.T) {
+	src := "package main\n\nfunc (l intList) Bad( {\n\treturn\n}\n"
+	expected := "    1 | package main\n    2 | \n    3 | func (l intList) Bad( {\n      |                       ^\n    4 | \treturn\n    5 | }\n"
+	if sourceContext(src, 3, 23) != expected {
+		t.Fail()
+	}
+
+	expected = "    2 | \n    3 | func (l intList) Bad( {\n    4 | \treturn\n      | \t^\n    5 | }\n    6 | \n"
+	if sourceContext(src, 4, 2) != expected {
+		t.Fail()
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("types=%d", n), func(b *testing.B) {
+			_, src := benchmarkSource(b, n)
+			b.SetBytes(int64(len(src)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := Format([]byte(src), "the lists"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
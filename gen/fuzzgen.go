@@ -0,0 +1,272 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fuzzInvariant - an invariant between methods of the lists which the fuzz tests of -with-fuzz check on the lists
+// decoded from the fuzzed bytes (see fuzzKind). The check is generated with the list name, the type name and a
+// function of type T -> bool, in a function where 'l' is the decoded list, 'original' a copy of it and 'k' a fuzzed
+// uint8. The methods are always called on 'l' or on a copy of the list named 'members', so that they are renamed with
+// the prefix and the suffix and can have pointer receivers
+type fuzzInvariant struct {
+	name        string
+	description string
+	methods     []string
+	check       func(listName, typeName, predicate string) string
+}
+
+// fuzzInvariants - the invariants checked by the fuzz tests, for the lists which have all their methods, eg: the lists
+// of the types which cannot be ordered have no Sort, so their fuzz tests do not check it
+var fuzzInvariants = []fuzzInvariant{
+	{
+		name:        "Filter",
+		description: "Filter keeps the members for which the function returns true, and that the members it leaves out are kept with the opposite function",
+		methods:     []string{"Filter"},
+		check: func(listName, typeName, predicate string) string {
+			return fmt.Sprintf(`p := %[3]s
+            members := append(%[1]s{}, l...)
+            kept := l.Filter(p)
+            rejected := members.Filter(func(x %[2]s) bool { return !p(x) })
+            if len(kept)+len(rejected) != len(original) {
+                t.Errorf("%%v: kept %%d members and rejected %%d", original, len(kept), len(rejected))
+            }
+            for _, x := range kept {
+                if !p(x) {
+                    t.Errorf("%%v: kept %%v", original, x)
+                }
+            }`, listName, typeName, predicate)
+		},
+	},
+	{
+		name:        "PFilter",
+		description: "PFilter keeps the same members as Filter, in the same order",
+		methods:     []string{"Filter", "PFilter"},
+		check: func(listName, typeName, predicate string) string {
+			return fmt.Sprintf(`p := %[2]s
+            members := append(%[1]s{}, l...)
+            if serial, parallel := append(%[1]s{}, l.Filter(p)...), append(%[1]s{}, members.PFilter(p)...); !reflect.DeepEqual(serial, parallel) {
+                t.Errorf("%%v: got %%v, Filter returned %%v", original, parallel, serial)
+            }`, listName, predicate)
+		},
+	},
+	{
+		name:        "FilterInPlace",
+		description: "FilterInPlace keeps the same members as Filter",
+		methods:     []string{"Filter", "FilterInPlace"},
+		check: func(listName, typeName, predicate string) string {
+			return fmt.Sprintf(`p := %[2]s
+            members := append(%[1]s{}, l...)
+            if filtered, inPlace := append(%[1]s{}, l.Filter(p)...), append(%[1]s{}, members.FilterInPlace(p)...); !reflect.DeepEqual(filtered, inPlace) {
+                t.Errorf("%%v: got %%v, Filter returned %%v", original, inPlace, filtered)
+            }`, listName, predicate)
+		},
+	},
+	{
+		name:        "TakeDrop",
+		description: "the members Take returns followed by the members Drop returns are the list",
+		methods:     []string{"Take", "Drop"},
+		check: func(listName, _, _ string) string {
+			return fmt.Sprintf(`n := int(k) %% (len(l) + 1)
+            members := append(%[1]s{}, l...)
+            taken := l.Take(n)
+            dropped := members.Drop(n)
+            if joined := append(append(%[1]s{}, taken...), dropped...); !reflect.DeepEqual(joined, original) {
+                t.Errorf("%%v: got %%v and %%v with n = %%d", original, taken, dropped, n)
+            }`, listName)
+		},
+	},
+	{
+		name:        "TakeWhileDropWhile",
+		description: "the members TakeWhile returns followed by the members DropWhile returns are the list",
+		methods:     []string{"TakeWhile", "DropWhile"},
+		check: func(listName, _, predicate string) string {
+			return fmt.Sprintf(`p := %[2]s
+            members := append(%[1]s{}, l...)
+            taken := l.TakeWhile(p)
+            dropped := members.DropWhile(p)
+            if joined := append(append(%[1]s{}, taken...), dropped...); !reflect.DeepEqual(joined, original) {
+                t.Errorf("%%v: got %%v and %%v", original, taken, dropped)
+            }`, listName, predicate)
+		},
+	},
+	{
+		name:        "Map",
+		description: "Map with the identity function returns the list",
+		methods:     []string{"Map"},
+		check: func(listName, typeName, _ string) string {
+			return fmt.Sprintf(`if mapped := append(%[1]s{}, l.Map(func(x %[2]s) %[2]s { return x })...); !reflect.DeepEqual(mapped, original) {
+                t.Errorf("%%v: got %%v", original, mapped)
+            }`, listName, typeName)
+		},
+	},
+	{
+		name:        "AllAny",
+		description: "All returns true for a function if and only if Any returns false for the opposite function",
+		methods:     []string{"All", "Any"},
+		check: func(_, typeName, predicate string) string {
+			return fmt.Sprintf(`p := %[2]s
+            if all, opposite := l.All(p), l.Any(func(x %[1]s) bool { return !p(x) }); all == opposite {
+                t.Errorf("%%v: All returned %%v and Any of the opposite function %%v", original, all, opposite)
+            }`, typeName, predicate)
+		},
+	},
+	{
+		name:        "ReverseInPlace",
+		description: "ReverseInPlace twice leaves the list unchanged",
+		methods:     []string{"ReverseInPlace"},
+		check: func(_, _, _ string) string {
+			return `l.ReverseInPlace()
+            l.ReverseInPlace()
+            if !reflect.DeepEqual(l, original) {
+                t.Errorf("%v: got %v", original, l)
+            }`
+		},
+	},
+	{
+		name:        "Unique",
+		description: "Unique returns at most the members of the list, and that it returns the same members for its result",
+		methods:     []string{"Unique"},
+		check: func(listName, _, _ string) string {
+			return fmt.Sprintf(`unique := append(%[1]s{}, l.Unique()...)
+            members := append(%[1]s{}, unique...)
+            if twice := append(%[1]s{}, members.Unique()...); !reflect.DeepEqual(twice, unique) || len(unique) > len(original) {
+                t.Errorf("%%v: got %%v, and %%v for the result", original, unique, twice)
+            }`, listName)
+		},
+	},
+	{
+		name:        "UniqueContains",
+		description: "the result of Unique contains every member of the list",
+		methods:     []string{"Unique", "Contains"},
+		check: func(listName, _, _ string) string {
+			return fmt.Sprintf(`members := append(%[1]s{}, l.Unique()...)
+            for _, x := range original {
+                if !members.Contains(x) {
+                    t.Errorf("%%v: %%v is not in %%v", original, x, members)
+                }
+            }`, listName)
+		},
+	},
+	{
+		name:        "Sort",
+		description: "Sort returns the members of the list in increasing order, and the same list for its result",
+		methods:     []string{"Sort"},
+		check: func(listName, _, _ string) string {
+			return fmt.Sprintf(`sorted := append(%[1]s{}, l.Sort()...)
+            members := append(%[1]s{}, sorted...)
+            if len(sorted) != len(original) || !sort.SliceIsSorted(sorted, func(i, j int) bool { return sorted[i] < sorted[j] }) {
+                t.Errorf("%%v: got %%v", original, sorted)
+            }
+            if twice := append(%[1]s{}, members.Sort()...); !reflect.DeepEqual(twice, sorted) {
+                t.Errorf("%%v: got %%v, and %%v for the result", original, sorted, twice)
+            }`, listName)
+		},
+	},
+	{
+		name:        "MinMax",
+		description: "no member of the list is lower than the result of Min or greater than the result of Max",
+		methods:     []string{"Min", "Max"},
+		check: func(_, _, _ string) string {
+			return `lowest, lowestOK := l.Min()
+            highest, highestOK := l.Max()
+            if lowestOK != (len(original) > 0) || highestOK != lowestOK {
+                t.Errorf("%v: got %v and %v", original, lowestOK, highestOK)
+            }
+            for _, x := range original {
+                if x < lowest || x > highest {
+                    t.Errorf("%v: %v is not between %v and %v", original, x, lowest, highest)
+                }
+            }`
+		},
+	},
+}
+
+// fuzzKind - get how the fuzz tests decode a list of a type from the fuzzed bytes, and the function of type T -> bool
+// the invariants are checked with: a number for every byte, the strings separated by the zero bytes, or a bool for
+// every byte. The other types have no fuzz tests
+func (p plan) fuzzKind(typeName string) (decode, predicate string, ok bool) {
+	switch {
+	case typeName == "bool":
+		return "b%2 == 1", "func(x bool) bool { return x }", true
+	case p.ordering(typeName) == "number":
+		return fmt.Sprintf("%s(int8(b))", typeName), fmt.Sprintf("func(x %s) bool { return x > 0 }", typeName), true
+	case p.ordering(typeName) == "string":
+		return fmt.Sprintf("%s(b)", typeName), fmt.Sprintf("func(x %s) bool { return len(x)%%2 == 0 }", typeName), true
+	}
+	return "", "", false
+}
+
+// hasMethods - whether all the methods are in the methods selected for a list
+func hasMethods(selected map[string]bool, methods []string) bool {
+	for _, method := range methods {
+		if !selected[method] {
+			return false
+		}
+	}
+	return true
+}
+
+// generateFuzzTests - generate the fuzz tests of the invariants between the selected methods of the lists of the types
+// which can be decoded from the fuzzed bytes (see fuzzKind), for -with-fuzz. Every invariant gets a fuzz test, eg:
+// 'FuzzIntListTakeDrop', with a few seeds, so that 'go test' checks them with the seeds and 'go test -fuzz' with the
+// fuzzed inputs
+func generateFuzzTests(packageName string, selected map[string]string, p plan) string {
+	code := fmt.Sprintf(`package %[1]s
+            `, packageName)
+
+	for _, typeName := range sortedTypes(selected) {
+		decode, predicate, ok := p.fuzzKind(typeName)
+		if !ok {
+			continue
+		}
+		listName := strings.TrimPrefix(selected[typeName], "*") + "List"
+		methods := p.methodsOf(listName)
+
+		checked := []fuzzInvariant{}
+		for _, invariant := range fuzzInvariants {
+			if hasMethods(methods, invariant.methods) {
+				checked = append(checked, invariant)
+			}
+		}
+		if len(checked) == 0 {
+			continue
+		}
+
+		split := "for _, b := range data {"
+		if p.ordering(typeName) == "string" {
+			split = "for _, b := range bytes.Split(data, []byte{0}) {"
+		}
+		code += fmt.Sprintf(`
+            // %[1]sFromFuzz decodes the fuzzed bytes into a %[1]s for its fuzz tests
+            func %[1]sFromFuzz(data []byte) %[1]s {
+                l := %[1]s{}
+                if len(data) == 0 {
+                    return l
+                }
+                %[2]s
+                    l = append(l, %[3]s)
+                }
+                return l
+            }
+            `, listName, split, decode)
+
+		for _, invariant := range checked {
+			code += fmt.Sprintf(`
+            // Fuzz%[1]s%[2]s checks that %[3]s
+            func Fuzz%[1]s%[2]s(f *testing.F) {
+                f.Add([]byte{}, uint8(0))
+                f.Add([]byte{1, 2, 0, 128, 255, 2}, uint8(2))
+                f.Add([]byte("fungen"), uint8(7))
+                f.Fuzz(func(t *testing.T, data []byte, k uint8) {
+                    l := %[4]sFromFuzz(data)
+                    original := append(%[4]s{}, l...)
+                    %[5]s
+                })
+            }
+            `, strings.Title(listName), invariant.name, invariant.description, listName, invariant.check(listName, typeName, predicate))
+		}
+	}
+	return code
+}
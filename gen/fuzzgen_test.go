@@ -0,0 +1,65 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateFuzzTests(t *testing.T) {
+	m := map[string]string{"int": "int", "string": "Str", "bool": "bool", "point": "point"}
+	src := f(generateFuzzTests("main", m, planOf("Take,Drop,Filter,Sort")))
+
+	for _, expected := range []string{
+		"func intListFromFuzz(data []byte) intList {", "l = append(l, int(int8(b)))",
+		"func StrListFromFuzz(data []byte) StrList {", "for _, b := range bytes.Split(data, []byte{0}) {",
+		"func boolListFromFuzz(data []byte) boolList {", "l = append(l, b%2 == 1)",
+		"func FuzzIntListTakeDrop(f *testing.F) {", "func FuzzIntListFilter(f *testing.F) {", "func FuzzIntListSort(f *testing.F) {",
+		"func FuzzStrListSort(f *testing.F) {", "func FuzzBoolListFilter(f *testing.F) {",
+	} {
+		if !strings.Contains(src, expected) {
+			t.Errorf("missing %s", expected)
+		}
+	}
+	// the points cannot be decoded from the fuzzed bytes, and the methods which are not selected have no invariants
+	for _, unexpected := range []string{"pointList", "TakeWhileDropWhile", "FuzzIntListUnique"} {
+		if strings.Contains(src, unexpected) {
+			t.Errorf("unexpected %s", unexpected)
+		}
+	}
+
+	// Sort is only checked for the lists which have it
+	p := planOf("Take,Drop,Filter,Sort")
+	p.typed = map[string]map[string]bool{"boolList": {"Take": true, "Drop": true, "Filter": true}}
+	if src := generateFuzzTests("main", map[string]string{"bool": "bool"}, p); strings.Contains(src, "FuzzBoolListSort") || !strings.Contains(src, "FuzzBoolListTakeDrop") {
+		t.Error(src)
+	}
+}
+
+func TestGenerateFuzzTestsRenamed(t *testing.T) {
+	src, err := GenerateFuzzTests(Spec{Package: "main", Types: map[string]string{"int": "int"}, Methods: []string{"Take", "Drop"}, Prefix: "F", Pointer: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"taken := l.FTake(n)", "dropped := members.FDrop(n)", `"reflect"`, `"testing"`} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("missing %s in:\n%s", expected, src)
+		}
+	}
+}
+
+func TestGenerateFuzzTestsImports(t *testing.T) {
+	// the structs of another package have no fuzz tests, and their package is not imported
+	spec := Spec{Package: "main", Types: map[string]string{"int": "int", "example.com/t3/models.User": "MU"}, Methods: []string{"Take", "Drop"}}
+	src, err := GenerateFuzzTests(spec)
+	if err != nil || strings.Contains(string(src), "example.com/t3/models") {
+		t.Fatal(err, string(src))
+	}
+
+	// the named numbers of another package have fuzz tests, which import it
+	spec.Types["example.com/t3/models.Score"] = "Score"
+	spec.Ordered = map[string]string{"example.com/t3/models.Score": "number"}
+	src, err = GenerateFuzzTests(spec)
+	if err != nil || !strings.Contains(string(src), `"example.com/t3/models"`) || !strings.Contains(string(src), "FuzzScoreListTakeDrop") {
+		t.Fatal(err, string(src))
+	}
+}
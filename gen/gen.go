@@ -0,0 +1,609 @@
+// Package gen - generate the list types of fungen and their methods, so that the code generation tools and the tests
+// can drive the generator without running the fungen command
+package gen
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+// Spec - what to generate in a file: the lists of the types and the methods of each list
+type Spec struct {
+	// Package - the name of the package of the generated file, eg: 'main'
+	Package string
+	// Header - the text written before the package clause, eg: the '// Code generated by fungen; DO NOT EDIT.' line and
+	// a //go:build line. It is empty by default
+	Header string
+	// Types - the types to generate the lists of, with the names of the lists without the 'List' suffix, eg:
+	// {"int": "int", "string": "Str"} generates 'intList' and 'StrList'. A type can be qualified with the import path of
+	// its package, eg: 'github.com/user/app/model.User', and the package is imported
+	Types map[string]string
+	// Maps - the map types to generate, with their names, eg: {"map[string]User": "userIndex"} generates 'userIndex'
+	// with the methods of the maps (Keys, Values, MapValues, FilterMap and Merge). The key and the value types can be
+	// qualified with the import path of their package, like the Types
+	Maps map[string]string
+	// Arrays - the array types to generate, with their names, eg: {"[4]float32": "vec4"} generates 'vec4' with the
+	// methods of the arrays (Each, EachI, Map, Reduce, ReduceRight and ToList), which keep its fixed length. The element
+	// type can be qualified with the import path of its package, like the Types
+	Arrays map[string]string
+	// Concurrent - whether the maps get a concurrent variant, eg: 'concurrentUserIndex', backed by a sync.Map, with the
+	// typed Load, Store, Delete, ComputeIfAbsent, Range, Keys, Values, Len and ToMap methods, created with the
+	// ToConcurrent method of the map
+	Concurrent bool
+	// Targets - the types the methods like Map map the lists to, with the names of their lists. They are the Types by
+	// default. The lists of the Targets which are not Types must be generated in another file of the package
+	Targets map[string]string
+	// Methods - the methods generated for the lists (see Methods). By default all the methods except the opt-in ones
+	Methods []string
+	// TypeMethods - the methods generated for some lists instead of the Methods, by list name, eg: {"StrList": {"Map"}}
+	TypeMethods map[string][]string
+	// Declared - the lists which are declared in the package already, eg: 'userList', which only get their methods
+	Declared []string
+	// Skip - the generated methods to leave out for some lists, by list name, with the Prefix and the Suffix, eg: the
+	// methods declared in the package already. The functions of the lists calling them, like their tests, are left out
+	// too
+	Skip map[string][]string
+	// Prefix and Suffix - added to the names of the generated methods, eg: 'F' and '' generate 'FMap', 'FFilter', ...
+	Prefix, Suffix string
+	// Pointer - whether the methods have pointer receivers. The in-place methods (see Method) then replace the list by
+	// their result
+	Pointer bool
+	// Safe - whether the lists get a thread-safe wrapper, eg: 'safeIntList', embedding a sync.RWMutex, with the methods
+	// of the list locking around every call, and Snapshot and ReplaceAll
+	Safe bool
+	// Chunked - whether the parallel methods split the list into runtime.NumCPU() chunks, one goroutine for each
+	Chunked bool
+	// Pooled - whether the parallel methods accept an optional pool of goroutines to reuse
+	Pooled bool
+	// Grow - whether Filter and FilterMap grow their result from an empty list, using the least memory when few members
+	// are kept, instead of allocating it with the capacity of the list, so that it is not reallocated as it grows
+	Grow bool
+	// TwoPass - whether Filter counts the members it keeps before copying them to a result of their exact size, which
+	// uses less memory than Grow and the capacity of the list for the large lists keeping few members. It overrides Grow
+	TwoPass bool
+	// Copy - whether Take, TakeWhile, Drop and DropWhile return a copy of the members they keep, which can be appended to
+	// without changing the list, instead of a slice of the list sharing its backing array
+	Copy bool
+	// Fields - the fields of the struct element types of some lists which the Pluck methods of the lists get, by list
+	// name, eg: {"UserList": {{"Name", "string"}}} generates 'PluckName() stringList'
+	Fields map[string][]Field
+	// Deref - whether Contains and Unique compare the values the members of the lists of pointers point to, instead of
+	// the pointers
+	Deref bool
+	// Incomparable - the element types whose members cannot be compared although their type expression does not show
+	// it, with the reason (see ClassifyTypes). The methods needing members which can be compared, like Contains,
+	// are not generated for their lists, and the methods needing keys, like PGroupBy, are not generated with them
+	Incomparable map[string]string
+	// Ordered - the element types whose members can be ordered with < although their type expression does not show it,
+	// eg: 'time.Duration', with 'number' for the numbers and 'string' for the strings (see ClassifyTypes). The methods
+	// needing members which can be ordered, like Min, or which are numbers, like Sum, are only generated for the lists of
+	// these types and of the numbers and the strings
+	Ordered map[string]string
+	// Equality - the functions comparing the members of some lists instead of ==, by list name, eg: {"TaskList":
+	// {Eq: "sameTask", Hash: "hashTask"}}. The methods needing members which can be compared, like Contains, use them,
+	// and are generated with them for the lists whose members cannot be compared
+	Equality map[string]Equality
+	// MapTargets - the lists of the Targets the methods mapping to other lists, like Map, map some lists to instead of
+	// all the Targets, by list name, eg: {"userList": {"stringList", "intList"}} generates MapString and MapInt for
+	// userList but not the Map methods of the other Targets
+	MapTargets map[string][]string
+	// Generics - whether the methods which have a generic function, like Map and Filter, call it instead of being
+	// generated for every list. The generic functions are generated by GenerateGenerics
+	Generics bool
+	// Templates - the templates overriding the built-in methods or adding extra methods (see LoadTemplates). The
+	// built-in methods are generated without them
+	Templates Templates
+}
+
+// Field - a field of a struct element type: its name and its type, qualified with the name of its package like the
+// Types once they are qualified, eg: 'time.Time'
+type Field struct {
+	Name, Type string
+}
+
+// Equality - the functions comparing the members of an element type: Eq, of type func(T, T) bool, reports whether two
+// members are equal, and the optional Hash, of type func(T) uint64, gets a hash of a member, which must be the same for
+// the members which are equal. Contains and Unique only need Eq, ToSet needs both, and Unique uses Hash when it is given
+type Equality struct {
+	Eq, Hash string
+}
+
+// Method - a method which can be generated
+type Method struct {
+	// Name - the name of the method, eg: 'PMap'
+	Name string
+	// OptIn - the name of the option of fungen which selects the method, eg: 'chan' for MapChan. The methods with an
+	// OptIn are not generated by default
+	OptIn string
+	// Parallel - whether the method runs the function in several goroutines
+	Parallel bool
+	// Chunked and Pooled - whether the method has a variant for Spec.Chunked and Spec.Pooled
+	Chunked, Pooled bool
+	// InPlace - whether the method replaces the list by its result with Spec.Pointer
+	InPlace bool
+	// Comparable - whether the method needs members which can be compared, eg: Contains. It is not generated for the
+	// lists of the other types
+	Comparable bool
+	// Hashed - whether the method needs the hash function of Spec.Equality too to compare the members with its functions
+	Hashed bool
+	// Keyed - whether the method needs targets which can be the keys of a map, eg: PGroupBy. It is not generated with
+	// the other targets
+	Keyed bool
+}
+
+// Methods - get the built-in methods which can be generated, in the order in which they are generated
+func Methods() []Method {
+	return Templates{}.Methods()
+}
+
+// Methods - get the methods which can be generated with the templates: the built-in methods and the extra methods of
+// the templates, in the order in which they are generated
+func (t Templates) Methods() []Method {
+	result := []Method{}
+	t.generators().Each(func(gen Generator) {
+		result = append(result, Method{
+			Name:       gen.name,
+			OptIn:      gen.optIn,
+			Parallel:   gen.parallel,
+			Chunked:    gen.chunkedMethod != nil,
+			Pooled:     gen.pooledMethod != nil,
+			InPlace:    gen.inPlace,
+			Comparable: gen.comparable,
+			Hashed:     gen.hashed,
+			Keyed:      gen.keyed,
+		})
+	})
+	return result
+}
+
+// Omission - a selected method which is not generated for a list because it does not fit the members of the list, eg:
+// Sum for a list of structs
+type Omission struct {
+	// List and Type - the list and its element type, eg: 'UserList' and 'User'
+	List, Type string
+	// Method - the name of the method, eg: 'Sum'
+	Method string
+	// Reason - why the method does not fit the members, eg: "the members of 'User' are not numbers"
+	Reason string
+	// TypeChecked - whether the members cannot be compared according to the type-checking of the package (see
+	// Spec.Incomparable), which their type expression does not show
+	TypeChecked bool
+}
+
+// Omissions - get the selected methods which are not generated for the lists of a Spec because they do not fit their
+// members, sorted by element type and in the order in which the methods are generated
+func Omissions(spec Spec) ([]Omission, error) {
+	p, err := newPlan(spec)
+	if err != nil {
+		return nil, err
+	}
+	return p.omitted, nil
+}
+
+// ListMethods - describe every method which can be generated with the templates (for a list of T, mapping to a list
+// of U), with the signature and the doc comment of each generated function, using the chunked or pooled variants if
+// chunked or pooled is set
+func ListMethods(chunked, pooled bool, templates Templates) string {
+	return listMethods(templates.generators(), chunked, pooled)
+}
+
+// Generate - generate the formatted source of the file of a Spec: the lists of its types and their methods, and its
+// maps and arrays, with their imports
+func Generate(spec Spec) (_ []byte, err error) {
+	defer render.Recover(&err)
+	p, err := newPlan(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// the types are generated concurrently, and their code is joined in the order of the types
+	typeNames := sortedTypes(p.types)
+	codes := make([]string, len(typeNames))
+	errs := make([]error, len(typeNames))
+	lists := p.lists()
+	declarations := p.declarations(spec)
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, runtime.NumCPU())
+	for i, typeName := range typeNames {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, typeName string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			codes[i], errs[i] = p.generateType(spec, typeName, lists, declarations)
+		}(i, typeName)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, typeName := range sortedTypes(p.maps) {
+		code := p.renameMethods(generateMap(typeName, p.maps[typeName], p), spec, declarations)
+		if _, err := Format([]byte("package "+spec.Package+"\n"+code), fmt.Sprintf("type '%s'", typeName)); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	for _, typeName := range sortedTypes(p.arrays) {
+		code := p.renameMethods(generateArray(typeName, p.arrays[typeName], p), spec, declarations)
+		if _, err := Format([]byte("package "+spec.Package+"\n"+code), fmt.Sprintf("type '%s'", typeName)); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	src := spec.Header + fmt.Sprintf(`package %[1]s
+
+            `, spec.Package) + p.aliasDeclarations() + strings.Join(codes, "")
+	return p.finish(src, "the file")
+}
+
+// generateType - generate the list of a type and its methods, and check that the code is valid so that the errors
+// name the type. The calls of the methods are renamed with the declarations of all the types (see renameMethods)
+func (p plan) generateType(spec Spec, typeName string, lists map[string]string, declarations string) (_ string, err error) {
+	// the types are generated in their own goroutines
+	defer render.Recover(&err)
+	listName := strings.TrimPrefix(p.types[typeName], "*") + "List"
+	code := p.renameMethods(generate(typeName, listName, p.targets, p, spec.Chunked, spec.Pooled), spec, declarations)
+	if spec.Pointer {
+		var err error
+		code, err = pointerReceivers(code, lists, inPlaceMethods(spec.Prefix, spec.Suffix))
+		if err != nil {
+			return "", fmt.Errorf("the code generated for type '%s' is not valid: %s", typeName, err)
+		}
+	}
+	if spec.Safe {
+		inPlace := map[string]bool{}
+		if spec.Pointer {
+			inPlace = inPlaceMethods(spec.Prefix, spec.Suffix)
+		}
+		wrapper, err := safeWrapper(code, listName, inPlace, mutatingMethods(spec.Prefix, spec.Suffix), retainingMethods(spec.Prefix, spec.Suffix, p.targets))
+		if err != nil {
+			return "", fmt.Errorf("the code generated for type '%s' is not valid: %s", typeName, err)
+		}
+		code += wrapper
+	}
+	if _, err := Format([]byte("package "+spec.Package+"\n"+code), fmt.Sprintf("type '%s'", typeName)); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// GenerateTests - generate the source of a _test.go file with table-driven tests of the methods generated for a Spec
+func GenerateTests(spec Spec) (_ []byte, err error) {
+	defer render.Recover(&err)
+	p, err := newPlan(spec)
+	if err != nil {
+		return nil, err
+	}
+	code := generateTests(spec.Package, p.targets, p.types, p)
+	return p.finish(spec.Header+p.renameMethods(code, spec, p.declarations(spec)), "the tests")
+}
+
+// GenerateExamples - generate the source of a _example_test.go file with an example of every method generated for a
+// Spec
+func GenerateExamples(spec Spec) (_ []byte, err error) {
+	defer render.Recover(&err)
+	p, err := newPlan(spec)
+	if err != nil {
+		return nil, err
+	}
+	code := generateExamples(spec.Package, p.targets, p.types, p, spec.Prefix, spec.Suffix)
+	return p.finish(spec.Header+p.renameMethods(code, spec, p.declarations(spec)), "the examples")
+}
+
+// GenerateFuzzTests - generate the source of a _fuzz_test.go file with the fuzz tests of the invariants between the
+// methods generated for a Spec, eg: that Take and Drop split the list, for the lists of the numbers, the strings and
+// the bools
+func GenerateFuzzTests(spec Spec) (_ []byte, err error) {
+	defer render.Recover(&err)
+	p, err := newPlan(spec)
+	if err != nil {
+		return nil, err
+	}
+	src := spec.Header + p.renameMethods(generateFuzzTests(spec.Package, p.types, p), spec, p.declarations(spec))
+	if p.imports, err = p.usedImports(src); err != nil {
+		return nil, fmt.Errorf("resolving the imports of the fuzz tests: %s", err)
+	}
+	return p.finish(src, "the fuzz tests")
+}
+
+// GeneratePropertyTests - generate the source of a _property_test.go file with the property tests of the laws of the
+// methods generated for a Spec, eg: that Map with the composition of two functions returns the same list as Map with
+// each of them, checked with testing/quick on random lists
+func GeneratePropertyTests(spec Spec) (_ []byte, err error) {
+	defer render.Recover(&err)
+	p, err := newPlan(spec)
+	if err != nil {
+		return nil, err
+	}
+	src := spec.Header + p.renameMethods(generatePropertyTests(spec.Package, p.types, p), spec, p.declarations(spec))
+	if p.imports, err = p.usedImports(src); err != nil {
+		return nil, fmt.Errorf("resolving the imports of the property tests: %s", err)
+	}
+	return p.finish(src, "the property tests")
+}
+
+// GenerateBenchmarks - generate the source of a _bench_test.go file benchmarking the parallel methods generated for a
+// Spec against their serial counterparts
+func GenerateBenchmarks(spec Spec) (_ []byte, err error) {
+	defer render.Recover(&err)
+	p, err := newPlan(spec)
+	if err != nil {
+		return nil, err
+	}
+	code := generateBenchmarks(spec.Package, p.types, p)
+	return p.finish(spec.Header+p.renameMethods(code, spec, p.declarations(spec)), "the benchmarks")
+}
+
+// plan - a Spec resolved for the generation: its types qualified with the names of their packages, the imports, and
+// the methods selected for every list
+type plan struct {
+	types        map[string]string
+	maps         map[string]string
+	arrays       map[string]string
+	concurrent   bool
+	targets      map[string]string
+	imports      []string
+	methods      map[string]bool
+	typed        map[string]map[string]bool
+	declared     map[string]bool
+	skipped      map[string]map[string]bool
+	omitted      []Omission // the selected methods which do not fit the members of their lists (see Omissions)
+	generics     bool
+	grow         bool
+	twoPass      bool
+	copy         bool
+	deref        bool
+	fields       map[string][]Field
+	incomparable map[string]string
+	ordered      map[string]string
+	equality     map[string]Equality
+	mapTargets   map[string]map[string]bool
+	aliased      map[string]string // the aliases of the element types shadowed by the local names (see aliasElements)
+	ownAliases   map[string]bool   // the aliases declared in the file of the plan (see aliasDeclarations)
+	generators   GeneratorList     // the generators with the templates of the Spec (see Templates.generators)
+}
+
+// newPlan - resolve a Spec, checking the names of its methods
+func newPlan(spec Spec) (plan, error) {
+	p := plan{
+		methods:    map[string]bool{},
+		typed:      map[string]map[string]bool{},
+		declared:   map[string]bool{},
+		skipped:    map[string]map[string]bool{},
+		generics:   spec.Generics,
+		grow:       spec.Grow,
+		twoPass:    spec.TwoPass,
+		copy:       spec.Copy,
+		deref:      spec.Deref,
+		fields:     spec.Fields,
+		mapTargets: map[string]map[string]bool{},
+		concurrent: spec.Concurrent,
+		generators: spec.Templates.generators(),
+	}
+
+	targets := spec.Targets
+	if targets == nil {
+		targets = spec.Types
+	}
+	var err error
+	var imports []string
+	if p.types, imports, err = QualifyTypes(spec.Types); err != nil {
+		return p, err
+	}
+	if p.targets, p.imports, err = QualifyTypes(targets); err != nil {
+		return p, err
+	}
+	for _, path := range imports {
+		if !contains(p.imports, path) {
+			p.imports = append(p.imports, path)
+		}
+	}
+	if p.maps, imports, err = QualifyTypes(spec.Maps); err != nil {
+		return p, err
+	}
+	for _, path := range imports {
+		if !contains(p.imports, path) {
+			p.imports = append(p.imports, path)
+		}
+	}
+	if p.arrays, imports, err = QualifyTypes(spec.Arrays); err != nil {
+		return p, err
+	}
+	for _, path := range imports {
+		if !contains(p.imports, path) {
+			p.imports = append(p.imports, path)
+		}
+	}
+	if p.incomparable, _, err = QualifyTypes(spec.Incomparable); err != nil {
+		return p, err
+	}
+	if p.ordered, _, err = QualifyTypes(spec.Ordered); err != nil {
+		return p, err
+	}
+	p.equality = spec.Equality
+	p.aliasElements()
+	for listName, lists := range spec.MapTargets {
+		p.mapTargets[listName] = map[string]bool{}
+		for _, targetList := range lists {
+			p.mapTargets[listName][targetList] = true
+		}
+	}
+	for typeName := range p.maps {
+		if _, _, ok := splitMapType(typeName); !ok {
+			return p, fmt.Errorf("'%s' is not a map type", typeName)
+		}
+	}
+	for typeName := range p.arrays {
+		if _, _, ok := splitArrayType(typeName); !ok {
+			return p, fmt.Errorf("'%s' is not an array type", typeName)
+		}
+	}
+
+	validMethods := map[string]bool{}
+	p.generators.Each(func(gen Generator) {
+		validMethods[gen.name] = true
+	})
+	methodSet := func(methods []string) (map[string]bool, error) {
+		result := map[string]bool{}
+		for _, method := range methods {
+			if !validMethods[method] {
+				return nil, fmt.Errorf("'%s' is not a method", method)
+			}
+			result[method] = true
+		}
+		return result, nil
+	}
+
+	if spec.Methods == nil {
+		p.generators.Filter(func(gen Generator) bool {
+			return gen.optIn == ""
+		}).Each(func(gen Generator) {
+			p.methods[gen.name] = true
+		})
+	} else if p.methods, err = methodSet(spec.Methods); err != nil {
+		return p, err
+	}
+	for listName, methods := range spec.TypeMethods {
+		if p.typed[listName], err = methodSet(methods); err != nil {
+			return p, err
+		}
+	}
+	for listName, methods := range spec.Skip {
+		p.skipped[listName] = map[string]bool{}
+		for _, method := range methods {
+			p.skipped[listName][method] = true
+		}
+	}
+	for _, listName := range spec.Declared {
+		p.declared[listName] = true
+	}
+
+	// the imports are resolved from the generated code, the imports of the generators are only needed for the
+	// packages which are not standard
+	p.generators.Filter(func(gen Generator) bool {
+		if p.methods[gen.name] {
+			return true
+		}
+		for _, methods := range p.typed {
+			if methods[gen.name] {
+				return true
+			}
+		}
+		return false
+	}).Each(func(gen Generator) {
+		p.imports = append(p.imports, gen.imports...)
+	})
+
+	// the methods needing members which can be compared are left out for the other types and for the interfaces (the
+	// values of the pointers are compared with Spec.Deref), unless they are compared with the functions of Spec.Equality,
+	// and the methods needing members which can be ordered, which are numbers or which are strings, like Min, Sum and
+	// TrimSpaceAll, or which are slices or pointers, like Flatten and CompactNil, for the other types
+	for typeName, name := range p.types {
+		listName := strings.TrimPrefix(name, "*") + "List"
+		leftOut := p.generators.Filter(func(gen Generator) bool {
+			return p.misfit(gen, typeName, listName) != ""
+		})
+		if len(leftOut) == 0 {
+			continue
+		}
+		selected := map[string]bool{}
+		for method := range p.methodsOf(listName) {
+			selected[method] = true
+		}
+		leftOut.Each(func(gen Generator) {
+			if selected[gen.name] {
+				p.omitted = append(p.omitted, Omission{
+					List:        listName,
+					Type:        typeName,
+					Method:      gen.name,
+					Reason:      p.misfit(gen, typeName, listName),
+					TypeChecked: gen.comparable && p.incomparable[p.compared(typeName)] != "",
+				})
+			}
+			delete(selected, gen.name)
+		})
+		p.typed[listName] = selected
+	}
+	sort.SliceStable(p.omitted, func(i, j int) bool {
+		return p.omitted[i].Type < p.omitted[j].Type
+	})
+	return p, nil
+}
+
+// methodsOf - get the methods selected for a list: its TypeMethods, or the Methods
+func (p plan) methodsOf(listName string) map[string]bool {
+	if selected, ok := p.typed[listName]; ok {
+		return selected
+	}
+	return p.methods
+}
+
+// mapsTo - whether the methods mapping to other lists, like Map, map a list to the list of a Target: a list maps to all
+// the Targets unless it has MapTargets, and always to itself
+func (p plan) mapsTo(listName, typeName string) bool {
+	targetList := strings.TrimPrefix(p.targets[typeName], "*") + "List"
+	targets, ok := p.mapTargets[listName]
+	return !ok || targets[targetList] || targetList == listName
+}
+
+// listOf - get the type of the lists of a type: its list if it is a Target, or a slice, eg: 'stringList' or '[]string'
+func (p plan) listOf(typeName string) string {
+	if listName, ok := p.targets[typeName]; ok {
+		return strings.TrimPrefix(listName, "*") + "List"
+	}
+	return "[]" + typeName
+}
+
+// lists - get the types of the lists generated, by list name
+func (p plan) lists() map[string]string {
+	result := map[string]string{}
+	for typeName, name := range p.types {
+		result[strings.TrimPrefix(name, "*")+"List"] = typeName
+	}
+	return result
+}
+
+// finish - remove the skipped methods from the source generated for what (eg: 'the tests'), add its imports and format
+// it
+func (p plan) finish(src, what string) ([]byte, error) {
+	var err error
+	if len(p.skipped) > 0 {
+		if src, err = removeMethods(src, p.lists(), p.skipped); err != nil {
+			return nil, fmt.Errorf("the code generated for %s is not valid: %s", what, err)
+		}
+	}
+	if src, err = addImports(src, p.imports); err != nil {
+		return nil, fmt.Errorf("resolving the imports of %s: %s", what, err)
+	}
+	return Format([]byte(src), what)
+}
+
+// contains - whether a list of strings contains a string
+func contains(list []string, s string) bool {
+	for _, member := range list {
+		if member == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedTypes - get the types of the type map in a stable order, so that the generated code is the same on every run
+func sortedTypes(m map[string]string) []string {
+	result := []string{}
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,511 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// planOf - get the plan generating the comma-separated methods for every list
+func planOf(methods string) plan {
+	p := plan{methods: map[string]bool{}, generators: generators}
+	for _, method := range strings.Split(methods, ",") {
+		p.methods[method] = true
+	}
+	return p
+}
+
+func TestGenerate(t *testing.T) {
+	spec := Spec{Package: "models", Types: map[string]string{"int": "int", "time.Time": "Time"}, Methods: []string{"Map", "PFilter"}}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"package models\n", "\"sync\"", "\"time\"", "type intList []int", "type TimeList []time.Time", "func (l intList) MapTime(", "func (l TimeList) PFilter("} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	if strings.Contains(code, ") Filter(") {
+		t.Fail()
+	}
+
+	spec.Header = "// Code generated by a test; DO NOT EDIT.\n\n"
+	spec.Declared = []string{"intList"}
+	spec.Skip = map[string][]string{"TimeList": {"PFilter"}}
+	src, err = Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code = string(src)
+	if !strings.HasPrefix(code, spec.Header+"package models\n") || strings.Contains(code, "type intList") || strings.Contains(code, "func (l TimeList) PFilter(") || !strings.Contains(code, "func (l intList) PFilter(") {
+		t.Error(code)
+	}
+
+	if _, err := Generate(Spec{Package: "models", Types: map[string]string{"int": "int"}, Methods: []string{"Filtre"}}); err == nil {
+		t.Fail()
+	}
+	if _, err := Generate(Spec{Package: "models", Types: map[string]string{"a/model.User": "U", "b/model.T": "T"}}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestGenerateTypeMethods(t *testing.T) {
+	m := map[string]string{"int": "I", "string": "string"}
+	p := planOf("Map,MapAsync")
+	p.typed = map[string]map[string]bool{"IList": {"Map": true, "Filter": true}}
+	if len(p.methodsOf("IList")) != 2 || !p.methodsOf("stringList")["MapAsync"] {
+		t.Fail()
+	}
+
+	// IList does not have MapAsync, but its future is returned by the MapAsyncI method of stringList
+	code := generate("int", "IList", m, p, false, false)
+	if !strings.Contains(code, "type IListFuture struct") || strings.Contains(code, ") MapAsync") || !strings.Contains(code, ") Filter(") {
+		t.Error(code)
+	}
+}
+
+func TestGenerateExtraFiles(t *testing.T) {
+	spec := Spec{Package: "main", Types: map[string]string{"int": "int"}, Methods: []string{"Map", "Filter", "PFilter"}, Prefix: "F"}
+	tests, err := GenerateTests(spec)
+	if err != nil || !strings.Contains(string(tests), "func TestIntListFilter(") {
+		t.Error(err, string(tests))
+	}
+	examples, err := GenerateExamples(spec)
+	if err != nil || !strings.Contains(string(examples), "func ExampleintList_FFilter(") {
+		t.Error(err, string(examples))
+	}
+	benchmarks, err := GenerateBenchmarks(spec)
+	if err != nil || !strings.Contains(string(benchmarks), "func BenchmarkIntListPFilter(") || !strings.Contains(string(benchmarks), "l.FPFilter(") {
+		t.Error(err, string(benchmarks))
+	}
+
+	spec.Skip = map[string][]string{"intList": {"FFilter"}}
+	tests, err = GenerateTests(spec)
+	if err != nil || strings.Contains(string(tests), "func TestIntListFilter(") || !strings.Contains(string(tests), "func TestIntListMap(") {
+		t.Error(err, string(tests))
+	}
+}
+
+func TestMethods(t *testing.T) {
+	methods := Methods()
+	if methods[0].Name != "Map" || len(methods) != len(generators) {
+		t.Fail()
+	}
+	for _, method := range methods {
+		switch method.Name {
+		case "MapChan", "FilterChan":
+			if method.OptIn != "chan" {
+				t.Error(method)
+			}
+		case "PMap":
+			if !method.Parallel || !method.Chunked || !method.Pooled || method.OptIn != "" {
+				t.Error(method)
+			}
+		case "Filter":
+			if !method.InPlace || method.Parallel {
+				t.Error(method)
+			}
+		}
+	}
+}
+
+func TestOmissions(t *testing.T) {
+	omissions, err := Omissions(Spec{
+		Types:   map[string]string{"User": "User", "func()": "Callback", "[]int": "Ints", "int": "int"},
+		Methods: []string{"Map", "Unique", "Sum", "Flatten", "TrimSpaceAll"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Omission{
+		{List: "UserList", Type: "User", Method: "Flatten", Reason: "the members of 'User' are not slices"},
+		{List: "UserList", Type: "User", Method: "Sum", Reason: "the members of 'User' are not numbers"},
+		{List: "UserList", Type: "User", Method: "TrimSpaceAll", Reason: "the members of 'User' are not strings"},
+		{List: "IntsList", Type: "[]int", Method: "Unique", Reason: "the members of '[]int' cannot be compared"},
+		{List: "IntsList", Type: "[]int", Method: "Sum", Reason: "the members of '[]int' are not numbers"},
+		{List: "IntsList", Type: "[]int", Method: "TrimSpaceAll", Reason: "the members of '[]int' are not strings"},
+		{List: "CallbackList", Type: "func()", Method: "Flatten", Reason: "the members of 'func()' are not slices"},
+		{List: "CallbackList", Type: "func()", Method: "Unique", Reason: "the members of 'func()' cannot be compared"},
+		{List: "CallbackList", Type: "func()", Method: "Sum", Reason: "the members of 'func()' are not numbers"},
+		{List: "CallbackList", Type: "func()", Method: "TrimSpaceAll", Reason: "the members of 'func()' are not strings"},
+		{List: "intList", Type: "int", Method: "Flatten", Reason: "the members of 'int' are not slices"},
+		{List: "intList", Type: "int", Method: "TrimSpaceAll", Reason: "the members of 'int' are not strings"},
+	}
+	if !reflect.DeepEqual(omissions, expected) {
+		t.Error(omissions)
+	}
+
+	omissions, _ = Omissions(Spec{
+		Types:        map[string]string{"Task": "Task"},
+		Methods:      []string{"Contains"},
+		Incomparable: map[string]string{"Task": "its field 'Run' of type 'func()' cannot be compared"},
+	})
+	if len(omissions) != 1 || !omissions[0].TypeChecked {
+		t.Error(omissions)
+	}
+}
+
+func TestDeterministicOutput(t *testing.T) {
+	m := map[string]string{"int": "int", "string": "str", "float64": "f64", "*point": "*point", "bool": "b"}
+	generateAll := func() string {
+		src := ""
+		for _, generate := range []func(Spec) ([]byte, error){Generate, GenerateTests, GenerateExamples, GenerateBenchmarks} {
+			code, err := generate(Spec{Package: "main", Types: m})
+			if err != nil {
+				t.Fatal(err)
+			}
+			src += string(code)
+		}
+		return src
+	}
+
+	expected := generateAll()
+	for i := 0; i < 5; i++ {
+		if generateAll() != expected {
+			t.Fatal("the generated code changes between runs")
+		}
+	}
+}
+
+func TestGenerateSets(t *testing.T) {
+	spec := Spec{Package: "main", Types: map[string]string{"int": "int", "[]string": "Strs"}, Methods: []string{"Map", "ToSet"}}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"type intSet map[int]struct{}", "func intSetFromList(l intList) intSet {", "func (l intList) ToSet() intSet {", "func (s intSet) Difference(other intSet) intSet {", "func (s intSet) ToList() intList {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	// the slices cannot be the keys of a map
+	if strings.Contains(code, "StrsSet") || !strings.Contains(code, "func (l StrsList) MapInt(") {
+		t.Error(code)
+	}
+
+	tests, err := GenerateTests(spec)
+	if err != nil || !strings.Contains(string(tests), "func TestIntListToSet(") || strings.Contains(string(tests), "TestStrsListToSet") {
+		t.Error(err, string(tests))
+	}
+}
+
+func TestGenerateStacksAndQueues(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"string": "Str"}, Methods: []string{"ToStack", "ToQueue"}, Prefix: "F"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"type StrStack struct {", "func (l StrList) FToStack() *StrStack {", "func (s *StrStack) Pop() (string, bool) {", "func (s *StrStack) ToList() StrList {", "type StrQueue struct {", "func (l StrList) FToQueue() *StrQueue {", "func (q *StrQueue) Dequeue() (string, bool) {", "func (q *StrQueue) Peek() (string, bool) {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+}
+
+func TestGenerateDeques(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"string": "Str"}, Methods: []string{"ToDeque"}, Prefix: "F"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"type StrDeque struct {", "func (l StrList) FToDeque() *StrDeque {", "func (d *StrDeque) PushFront(t string) {", "func (d *StrDeque) PopBack() (string, bool) {", "func (d *StrDeque) At(i int) string {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+}
+
+func TestGenerateImmutables(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"string": "Str"}, Methods: []string{"ToImmutable"}, Prefix: "F"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"type StrImmutableList struct {", "func (l StrList) FToImmutable() StrImmutableList {", "func (im StrImmutableList) Append(members ...string) StrImmutableList {", "func (im StrImmutableList) Remove(i int) StrImmutableList {", "func (im StrImmutableList) Slice(i, j int) StrImmutableList {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+}
+
+func TestGenerateSortedLists(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"string": "Str"}, Methods: []string{"ToSorted"}, Prefix: "F"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"type SortedStrList struct {", "func (l StrList) FToSorted(less func(string, string) bool) *SortedStrList {", "func (s *SortedStrList) Insert(members ...string) {", "func (s *SortedStrList) Contains(t string) bool {", "func (s *SortedStrList) Range(from, to string) StrList {", `import (
+	"sort"`} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+}
+
+func TestGenerateOptions(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"string": "Str", "int": "Int"}, Methods: []string{"Find", "Last"}, TypeMethods: map[string][]string{"IntList": {"Map"}}, Prefix: "F"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"type StrOption struct {", "func StrSome(t string) StrOption {", "func StrNone() StrOption {", "func (o StrOption) GetOr(t string) string {", "func (l StrList) FFind(f func(string) bool) StrOption {", "func (l StrList) FLast() StrOption {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	if strings.Contains(code, "IntOption") {
+		t.Error("the option type is generated for a list without Find, First or Last")
+	}
+}
+
+func TestGenerateResults(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"string": "Str", "int": "Int"}, Methods: []string{"Map"}, TypeMethods: map[string][]string{"StrList": {"MapResult"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"type StrResult struct {", "type IntResult struct {", "func IntOk(t int) IntResult {", "func (r IntResult) AndThen(f func(int) IntResult) IntResult {", "func (l StrList) MapResultInt(f func(string) (int, error)) []IntResult {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	if strings.Contains(code, "func (l IntList) MapResult") {
+		t.Error("the MapResult methods are generated for a list without them")
+	}
+}
+
+func TestGenerateIterators(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"string": "Str", "int": "Int"}, Methods: []string{"Map"}, TypeMethods: map[string][]string{"StrList": {"Iter"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"type StrIter struct {", "type IntIter struct {", "func (l StrList) Iter() StrIter {", "func (l IntList) CollectFromIter(it IntIter) IntList {", "func (it StrIter) Take(n int) StrIter {", "func (it StrIter) MapInt(f func(string) int) IntIter {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	if strings.Contains(code, "func (it IntIter) Map") {
+		t.Error("the Map methods of the iterator are generated for a list without Iter")
+	}
+}
+
+func TestGenerateNested(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"[]string": "rows", "string": "string"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"func (l rowsList) Flatten() []string {", "func (l rowsList) FlatMapString(f func([]string) []string) stringList {", "func (l rowsList) PGroupByString(f func([]string) string) map[string]rowsList {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	for _, unexpected := range []string{"func (l stringList) Flatten()", "func (l stringList) FlatMap", "PGroupByRows", "func (l rowsList) ToSet("} {
+		if strings.Contains(code, unexpected) {
+			t.Error(unexpected)
+		}
+	}
+}
+
+func TestGeneratePointers(t *testing.T) {
+	for _, deref := range []bool{false, true} {
+		src, err := Generate(Spec{Package: "main", Types: map[string]string{"*point": "*point", "point": "Pt", "int": "int"}, Deref: deref})
+		if err != nil {
+			t.Fatal(err)
+		}
+		code := string(src)
+		for _, expected := range []string{"func (l pointList) CompactNil() pointList {", "func (l pointList) DerefOr(def point) PtList {", "func (l pointList) Contains(t *point) bool {", "func (l pointList) Unique() pointList {", "func (l intList) Unique() intList {"} {
+			if !strings.Contains(code, expected) {
+				t.Error(deref, expected)
+			}
+		}
+		for _, unexpected := range []string{"func (l intList) CompactNil()", "func (l PtList) DerefOr("} {
+			if strings.Contains(code, unexpected) {
+				t.Error(deref, unexpected)
+			}
+		}
+		if strings.Contains(code, "*member == *t") != deref || strings.Contains(code, "seen[*t]") != deref {
+			t.Error(deref, "the members are not compared as expected")
+		}
+	}
+
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"*point": "*point"}, Methods: []string{"DerefOr"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "func (l pointList) DerefOr(def point) []point {") {
+		t.Error(string(src))
+	}
+}
+
+func TestGenerateInterfaces(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"error": "err", "string": "Str"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"func (l errList) UniqueByStr(f func(error) string) errList {", "func (l errList) SortBy(less func(error, error) bool) errList {", "func (l StrList) Unique() StrList {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	for _, unexpected := range []string{"func (l errList) Unique()", "func (l errList) Contains(", "func (l errList) ToSet(", "func (l errList) PGroupBy(", "func (l errList) UniqueBy(", "UniqueByErr("} {
+		if strings.Contains(code, unexpected) {
+			t.Error(unexpected)
+		}
+	}
+}
+
+func TestGeneratePluck(t *testing.T) {
+	spec := Spec{
+		Package: "main",
+		Types:   map[string]string{"User": "User", "*User": "UserPtr", "string": "string"},
+		Methods: []string{"Map"},
+		Fields:  map[string][]Field{"UserList": {{"Name", "string"}, {"Born", "time.Time"}}, "UserPtrList": {{"Name", "string"}}},
+	}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"func (l UserList) PluckName() stringList {", "func (l UserList) PluckBorn() []time.Time {", "func (l UserPtrList) PluckName() stringList {", "if t != nil {", `"time"`} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+
+	tests, err := GenerateTests(spec)
+	if err != nil || !strings.Contains(string(tests), "func TestUserPtrListPluckName(t *testing.T) {") {
+		t.Error(err, string(tests))
+	}
+	examples, err := GenerateExamples(spec)
+	if err != nil || !strings.Contains(string(examples), "func ExampleUserList_PluckBorn() {") {
+		t.Error(err, string(examples))
+	}
+}
+
+func TestGeneratePairs(t *testing.T) {
+	spec := Spec{Package: "main", Types: map[string]string{"string": "string", "int": "int"}, Methods: []string{"Zip"}}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"type stringIntPair struct {", "type stringIntPairList []stringIntPair", "func (l stringIntPairList) Seconds() intList {", "func (l stringList) ZipInt(other intList) stringIntPairList {", "func (l intList) Zip(other intList) intIntPairList {", "type intStringPair struct {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	if strings.Count(code, "type stringIntPair struct {") != 1 {
+		t.Error(code)
+	}
+
+	src, err = Generate(Spec{Package: "main", Types: map[string]string{"string": "string", "int": "int"}, Methods: []string{"Map"}})
+	if err != nil || strings.Contains(string(src), "Pair") {
+		t.Error(err, string(src))
+	}
+}
+
+func TestGenerateMapTargets(t *testing.T) {
+	spec := Spec{
+		Package:    "main",
+		Types:      map[string]string{"User": "user", "string": "string", "int": "int", "bool": "bool"},
+		Methods:    []string{"Map", "MapAsync"},
+		MapTargets: map[string][]string{"userList": {"stringList", "intList"}},
+	}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"func (l userList) Map(f func(User) User) userList {", "func (l userList) MapString(f func(User) string) stringList {", "func (l userList) MapAsyncInt(", "func (l boolList) MapUser(", "type stringListFuture struct {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	for _, unexpected := range []string{"func (l userList) MapBool(", "func (l userList) MapAsyncBool("} {
+		if strings.Contains(code, unexpected) {
+			t.Error(unexpected)
+		}
+	}
+
+	tests, err := GenerateTests(spec)
+	if err != nil || strings.Contains(string(tests), "func TestUserListMapBool(") || !strings.Contains(string(tests), "func TestUserListMapInt(") {
+		t.Error(err, string(tests))
+	}
+}
+
+func TestGenerateSeqs(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"string": "Str"}, Methods: []string{"All", "Values", "Enumerated", "FromSeq"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"func (l StrList) All(f func(string) bool) bool {", "func (l StrList) Values() iter.Seq[string] {", "func (l StrList) Enumerated() iter.Seq2[int, string] {", "func StrListFromSeq(seq iter.Seq[string]) StrList {", `"iter"`} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+}
+
+// benchmarkSizes - the numbers of element types the generator is benchmarked with
+var benchmarkSizes = []int{1, 10, 100, 500}
+
+// benchmarkSpec - get a Spec generating the default methods for n element types. The lists only map to the lists of
+// int and string, so that the generated code grows with the number of types, like in most packages, instead of with
+// its square
+func benchmarkSpec(n int) Spec {
+	types := map[string]string{}
+	for i := 0; i < n; i++ {
+		types[fmt.Sprintf("Type%d", i)] = fmt.Sprintf("Type%d", i)
+	}
+	return Spec{Package: "models", Types: types, Targets: map[string]string{"int": "int", "string": "string"}}
+}
+
+// benchmarkSource - get the code of the lists of a benchmarkSpec before it is formatted, from the templates of the
+// methods
+func benchmarkSource(b *testing.B, n int) (plan, string) {
+	p, err := newPlan(benchmarkSpec(n))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var src strings.Builder
+	src.WriteString("package models\n")
+	for _, typeName := range sortedTypes(p.types) {
+		src.WriteString(generate(typeName, p.types[typeName]+"List", p.targets, p, false, false))
+	}
+	return p, src.String()
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		spec := benchmarkSpec(n)
+		b.Run(fmt.Sprintf("types=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := Generate(spec); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("types=%d", n), func(b *testing.B) {
+			p, src := benchmarkSource(b, n)
+			b.SetBytes(int64(len(src)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for typeName, name := range p.types {
+					generate(typeName, name+"List", p.targets, p, false, false)
+				}
+			}
+		})
+	}
+}
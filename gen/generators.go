@@ -0,0 +1,847 @@
+package gen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+//go:generate $GOPATH/bin/fungen -types "Generator" -methods Filter,Each
+
+// Generator - one generator (function and information about generate)
+type Generator struct {
+	name           string
+	method         func(_, _, _, _ string) string
+	chunkedMethod  func(_, _, _, _ string) string
+	pooledMethod   func(_, _, _, _ string) string
+	derefMethod    func(_, _, _, _ string) string // the variant comparing the values the members point to with Spec.Deref
+	eqMethod       func(_, _, _, _ string) string // the variant comparing the members with the functions of Spec.Equality, given the list, the type, eq and hash
+	grownMethod    func(_, _, _, _ string) string // the variant growing its result from an empty list with Spec.Grow
+	copiedMethod   func(_, _, _, _ string) string // the variant returning a copy instead of a slice of the list with Spec.Copy
+	twoPassMethod  func(_, _, _, _ string) string // the variant allocating its result with its exact size with Spec.TwoPass
+	hashed         bool                           // whether the eqMethod needs the hash function of Spec.Equality too
+	declare        func(listName, typeName string) string
+	imports        []string // the packages which are not standard, the standard ones are resolved from the code
+	needMapToMap   bool
+	parallel       bool
+	inPlace        bool   // whether the method replaces the list by its result with -pointer
+	mutating       bool   // whether the method changes the members of the list, the thread-safe wrapper holds the write lock for it
+	retaining      bool   // whether the result of the method reads the list after it returns, like an iterator, the thread-safe wrapper calls it on a copy of the list
+	comparable     bool   // whether the method needs members which can be compared, it is not generated for the other types
+	keyed          bool   // whether the method needs targets which can be the keys of a map (see safeKey), it is not generated for the other targets
+	nested         bool   // whether the method needs members which are slices, it is only generated for them
+	pointers       bool   // whether the method needs members which are pointers, it is only generated for them
+	ordered        bool   // whether the method needs members which can be ordered with <, the numbers and the strings (see plan.ordering), it is only generated for them
+	numeric        bool   // whether the method needs members which are numbers (see plan.ordering), it is only generated for them
+	textual        bool   // whether the method needs members which are strings (see plan.ordering), it is only generated for them
+	pairs          bool   // whether the method returns the pairs of the members with the members of the targets (see getPairType), declared once for every target
+	optIn          string // the option selecting the method, which is not generated by default
+	generic        string // the generic function which the method calls with Spec.Generics
+	genericBody    string // the body of the method calling the generic function, eg: 'return Map(l, f)'
+	grownGeneric   string // the generic function of the grownMethod
+	copiedGeneric  string // the generic function of the copiedMethod
+	twoPassGeneric string // the generic function of the twoPassMethod
+	serial         string // the serial counterpart which the parallel method is benchmarked against (see generateBenchmarks). PMapRate, PMapTimeout and PMapRetry have none since their throttling, deadline and retries are what they add to PMap, and PFlatMap, PGroupBy and PCount have no serial method
+	benchmark      string // the body of the benchmark of the method, formatted with the element type, and the first other target and its suffix for the methods only generated for the other targets
+	test           func(_, _, _, _ string) string
+	example        func(exampleData) string
+}
+
+// generators - the methods which can be generated
+var generators = GeneratorList{
+	{
+		name:         "Map",
+		example:      getMapExample,
+		test:         getMapTest,
+		method:       getMapFunction,
+		needMapToMap: true,
+		benchmark:    "l.Map(func(t %[1]s) %[1]s { return t })",
+		generic:      genericMap,
+		genericBody:  "return Map(l, f)",
+	},
+	{
+		name:          "PMap",
+		example:       getPMapExample,
+		test:          getPMapTest,
+		method:        getPMapFunction,
+		chunkedMethod: getChunkedPMapFunction,
+		needMapToMap:  true,
+		parallel:      true,
+		serial:        "Map",
+		benchmark:     "l.PMap(func(t %[1]s) %[1]s { return t })",
+		pooledMethod:  getPooledPMapFunction,
+	},
+	{
+		name:         "PMapRate",
+		example:      getPMapRateExample,
+		test:         getPMapRateTest,
+		method:       getPMapRateFunction,
+		needMapToMap: true,
+		parallel:     true,
+	},
+	{
+		name:         "PMapTimeout",
+		example:      getPMapTimeoutExample,
+		test:         getPMapTimeoutTest,
+		method:       getPMapTimeoutFunction,
+		needMapToMap: true,
+		parallel:     true,
+	},
+	{
+		name:         "PMapRetry",
+		example:      getPMapRetryExample,
+		test:         getPMapRetryTest,
+		method:       getPMapRetryFunction,
+		needMapToMap: true,
+		parallel:     true,
+	},
+	{
+		name:          "PFlatMap",
+		example:       getPFlatMapExample,
+		test:          getPFlatMapTest,
+		method:        getPFlatMapFunction,
+		chunkedMethod: getChunkedPFlatMapFunction,
+		needMapToMap:  true,
+		parallel:      true,
+		pooledMethod:  getPooledPFlatMapFunction,
+	},
+	{
+		name:          "PGroupBy",
+		example:       getPGroupByExample,
+		test:          getPGroupByTest,
+		method:        getPGroupByFunction,
+		chunkedMethod: getChunkedPGroupByFunction,
+		needMapToMap:  true,
+		keyed:         true,
+		parallel:      true,
+		pooledMethod:  getPooledPGroupByFunction,
+	},
+	{
+		name:         "MapAsync",
+		example:      getMapAsyncExample,
+		test:         getMapAsyncTest,
+		method:       getMapAsyncFunction,
+		declare:      getFutureType,
+		retaining:    true,
+		needMapToMap: true,
+	},
+	{
+		name:           "Filter",
+		example:        getFilterExample,
+		test:           getFilterTest,
+		inPlace:        true,
+		method:         getFilterFunction,
+		grownMethod:    getGrownFilterFunction,
+		twoPassMethod:  getTwoPassFilterFunction,
+		benchmark:      "l.Filter(func(%[1]s) bool { return true })",
+		generic:        genericFilter,
+		grownGeneric:   genericGrownFilter,
+		twoPassGeneric: genericTwoPassFilter,
+		genericBody:    "return Filter(l, f)",
+	},
+	{
+		name:          "PFilter",
+		example:       getPFilterExample,
+		test:          getPFilterTest,
+		inPlace:       true,
+		method:        getPFilterFunction,
+		chunkedMethod: getChunkedPFilterFunction,
+		parallel:      true,
+		serial:        "Filter",
+		benchmark:     "l.PFilter(func(%[1]s) bool { return true })",
+		pooledMethod:  getPooledPFilterFunction,
+	},
+	{
+		name:        "Reduce",
+		example:     getReduceExample,
+		test:        getReduceTest,
+		method:      getReduceFunction,
+		generic:     genericReduce,
+		genericBody: "return Reduce(l, t1, f)",
+	},
+	{
+		name:        "ReduceRight",
+		example:     getReduceRightExample,
+		test:        getReduceRightTest,
+		method:      getReduceRightFunction,
+		generic:     genericReduceRight,
+		genericBody: "return ReduceRight(l, t1, f)",
+	},
+	{
+		name:          "Take",
+		example:       getTakeExample,
+		test:          getTakeTest,
+		inPlace:       true,
+		method:        getTakeFunction,
+		copiedMethod:  getCopiedTakeFunction,
+		generic:       genericTake,
+		copiedGeneric: genericCopiedTake,
+		genericBody:   "return Take(l, n)",
+	},
+	{
+		name:          "TakeWhile",
+		example:       getTakeWhileExample,
+		test:          getTakeWhileTest,
+		inPlace:       true,
+		method:        getTakeWhileFunction,
+		copiedMethod:  getCopiedTakeWhileFunction,
+		generic:       genericTakeWhile,
+		copiedGeneric: genericCopiedTakeWhile,
+		genericBody:   "return TakeWhile(l, f)",
+	},
+	{
+		name:          "Drop",
+		example:       getDropExample,
+		test:          getDropTest,
+		inPlace:       true,
+		method:        getDropFunction,
+		copiedMethod:  getCopiedDropFunction,
+		generic:       genericDrop,
+		copiedGeneric: genericCopiedDrop,
+		genericBody:   "return Drop(l, n)",
+	},
+	{
+		name:          "DropWhile",
+		example:       getDropWhileExample,
+		test:          getDropWhileTest,
+		inPlace:       true,
+		method:        getDropWhileFunction,
+		copiedMethod:  getCopiedDropWhileFunction,
+		generic:       genericDropWhile,
+		copiedGeneric: genericCopiedDropWhile,
+		genericBody:   "return DropWhile(l, f)",
+	},
+	{
+		name:        "Each",
+		example:     getEachExample,
+		test:        getEachTest,
+		method:      getEachFunction,
+		generic:     genericEach,
+		genericBody: "Each(l, f)\n            return l",
+	},
+	{
+		name:        "EachI",
+		example:     getEachIExample,
+		test:        getEachITest,
+		method:      getEachIFunction,
+		generic:     genericEachI,
+		genericBody: "EachI(l, f)\n            return l",
+	},
+	{
+		name:        "All",
+		example:     getAllExample,
+		test:        getAllTest,
+		method:      getAllFunction,
+		benchmark:   "l.All(func(%[1]s) bool { return true })",
+		generic:     genericAll,
+		genericBody: "return All(l, f)",
+	},
+	{
+		name:        "Any",
+		example:     getAnyExample,
+		test:        getAnyTest,
+		method:      getAnyFunction,
+		benchmark:   "l.Any(func(%[1]s) bool { return false })",
+		generic:     genericAny,
+		genericBody: "return Any(l, f)",
+	},
+	{
+		name:      "PSort",
+		example:   getPSortExample,
+		test:      getPSortTest,
+		inPlace:   true,
+		method:    getPSortFunction,
+		parallel:  true,
+		serial:    "SortBy",
+		benchmark: "l.PSort(func(%[1]s, %[1]s) bool { return false })",
+	},
+	{
+		name:          "PAll",
+		example:       getPAllExample,
+		test:          getPAllTest,
+		method:        getPAllFunction,
+		chunkedMethod: getChunkedPAllFunction,
+		parallel:      true,
+		serial:        "All",
+		benchmark:     "l.PAll(func(%[1]s) bool { return true })",
+		pooledMethod:  getPooledPAllFunction,
+	},
+	{
+		name:          "PAny",
+		example:       getPAnyExample,
+		test:          getPAnyTest,
+		method:        getPAnyFunction,
+		chunkedMethod: getChunkedPAnyFunction,
+		parallel:      true,
+		serial:        "Any",
+		benchmark:     "l.PAny(func(%[1]s) bool { return false })",
+		pooledMethod:  getPooledPAnyFunction,
+	},
+	{
+		name:          "PCount",
+		example:       getPCountExample,
+		test:          getPCountTest,
+		method:        getPCountFunction,
+		chunkedMethod: getChunkedPCountFunction,
+		parallel:      true,
+		pooledMethod:  getPooledPCountFunction,
+	},
+	{
+		name:    "ToChan",
+		example: getToChanExample,
+		test:    getToChanTest,
+		method:  getToChanFunction,
+	},
+	{
+		name:    "FromChan",
+		example: getFromChanExample,
+		test:    getFromChanTest,
+		method:  getFromChanFunction,
+	},
+	{
+		name:         "MapChan",
+		example:      getMapChanExample,
+		test:         getMapChanTest,
+		method:       getMapChanFunction,
+		needMapToMap: true,
+		optIn:        "chan",
+	},
+	{
+		name:    "FilterChan",
+		example: getFilterChanExample,
+		test:    getFilterChanTest,
+		method:  getFilterChanFunction,
+		optIn:   "chan",
+	},
+	{
+		name:         "Pipeline",
+		example:      getPipelineExample,
+		test:         getPipelineTest,
+		method:       getPipelineMapFunction,
+		declare:      getPipelineType,
+		retaining:    true,
+		needMapToMap: true,
+		parallel:     true,
+		optIn:        "pipeline",
+	},
+	{
+		name:         "FilterMap",
+		example:      getFilterMapExample,
+		test:         getFilterMapTest,
+		method:       getFilterMapFunction,
+		grownMethod:  getGrownFilterMapFunction,
+		needMapToMap: true,
+		generic:      genericFilterMap,
+		grownGeneric: genericGrownFilterMap,
+		genericBody:  "return FilterMap(l, fMap, fFilters...)",
+		benchmark:    "l.FilterMap%[3]s(func(%[1]s) (u %[2]s) { return u }, func(%[1]s) bool { return true })",
+	},
+	{
+		name:          "PFilterMap",
+		example:       getPFilterMapExample,
+		test:          getPFilterMapTest,
+		method:        getPFilterMapFunction,
+		chunkedMethod: getChunkedPFilterMapFunction,
+		needMapToMap:  true,
+		parallel:      true,
+		serial:        "FilterMap",
+		benchmark:     "l.PFilterMap%[3]s(func(%[1]s) (u %[2]s) { return u }, func(%[1]s) bool { return true })",
+	},
+	{
+		name:       "ToSet",
+		example:    getToSetExample,
+		test:       getToSetTest,
+		method:     getToSetFunction,
+		eqMethod:   getEqToSetFunction,
+		hashed:     true,
+		comparable: true,
+		optIn:      "set",
+	},
+	{
+		name:    "ToStack",
+		example: getToStackExample,
+		test:    getToStackTest,
+		method:  getToStackFunction,
+		optIn:   "stack",
+	},
+	{
+		name:    "ToQueue",
+		example: getToQueueExample,
+		test:    getToQueueTest,
+		method:  getToQueueFunction,
+		optIn:   "queue",
+	},
+	{
+		name:    "ToDeque",
+		example: getToDequeExample,
+		test:    getToDequeTest,
+		method:  getToDequeFunction,
+		optIn:   "deque",
+	},
+	{
+		name:    "ToImmutable",
+		example: getToImmutableExample,
+		test:    getToImmutableTest,
+		method:  getToImmutableFunction,
+		optIn:   "immutable",
+	},
+	{
+		name:    "ToSorted",
+		example: getToSortedExample,
+		test:    getToSortedTest,
+		method:  getToSortedFunction,
+		optIn:   "sorted",
+	},
+	{
+		name:    "Find",
+		example: getFindExample,
+		test:    getFindTest,
+		method:  getFindFunction,
+		optIn:   "option",
+	},
+	{
+		name:    "First",
+		example: getFirstExample,
+		test:    getFirstTest,
+		method:  getFirstFunction,
+		optIn:   "option",
+	},
+	{
+		name:    "Last",
+		example: getLastExample,
+		test:    getLastTest,
+		method:  getLastFunction,
+		optIn:   "option",
+	},
+	{
+		name:         "MapResult",
+		example:      getMapResultExample,
+		test:         getMapResultTest,
+		method:       getMapResultFunction,
+		declare:      getResultType,
+		needMapToMap: true,
+		optIn:        "result",
+	},
+	{
+		name:         "Iter",
+		example:      getIterExample,
+		test:         getIterTest,
+		method:       getIterMapFunction,
+		declare:      getIterType,
+		retaining:    true,
+		needMapToMap: true,
+		optIn:        "iter",
+	},
+	{
+		name:      "Values",
+		example:   getValuesExample,
+		test:      getValuesTest,
+		method:    getValuesSeqFunction,
+		retaining: true,
+		optIn:     "seq",
+	},
+	{
+		name:      "Enumerated",
+		example:   getEnumeratedExample,
+		test:      getEnumeratedTest,
+		method:    getEnumeratedFunction,
+		retaining: true,
+		optIn:     "seq",
+	},
+	{
+		name:    "FromSeq",
+		example: getFromSeqExample,
+		test:    getFromSeqTest,
+		method:  getFromSeqFunction,
+		optIn:   "seq",
+	},
+	{
+		name:    "Flatten",
+		example: getFlattenExample,
+		test:    getFlattenTest,
+		method:  getFlattenFunction,
+		nested:  true,
+	},
+	{
+		name:         "FlatMap",
+		example:      getFlatMapExample,
+		test:         getFlatMapTest,
+		method:       getFlatMapFunction,
+		needMapToMap: true,
+		nested:       true,
+	},
+	{
+		name:        "Contains",
+		example:     getContainsExample,
+		test:        getContainsTest,
+		method:      getContainsFunction,
+		derefMethod: getDerefContainsFunction,
+		eqMethod:    getEqContainsFunction,
+		comparable:  true,
+	},
+	{
+		name:        "Unique",
+		example:     getUniqueExample,
+		test:        getUniqueTest,
+		inPlace:     true,
+		method:      getUniqueFunction,
+		derefMethod: getDerefUniqueFunction,
+		eqMethod:    getEqUniqueFunction,
+		comparable:  true,
+	},
+	{
+		name:         "UniqueBy",
+		example:      getUniqueByExample,
+		test:         getUniqueByTest,
+		method:       getUniqueByFunction,
+		needMapToMap: true,
+		keyed:        true,
+	},
+	{
+		name:      "SortBy",
+		example:   getSortByExample,
+		test:      getSortByTest,
+		inPlace:   true,
+		method:    getSortByFunction,
+		benchmark: "l.SortBy(func(%[1]s, %[1]s) bool { return false })",
+	},
+	{
+		name:     "FilterInPlace",
+		example:  getFilterInPlaceExample,
+		test:     getFilterInPlaceTest,
+		inPlace:  true,
+		method:   getFilterInPlaceFunction,
+		mutating: true,
+		optIn:    "inplace",
+	},
+	{
+		name:     "MapInPlace",
+		example:  getMapInPlaceExample,
+		test:     getMapInPlaceTest,
+		method:   getMapInPlaceFunction,
+		mutating: true,
+		optIn:    "inplace",
+	},
+	{
+		name:     "ReverseInPlace",
+		example:  getReverseInPlaceExample,
+		test:     getReverseInPlaceTest,
+		method:   getReverseInPlaceFunction,
+		mutating: true,
+		optIn:    "inplace",
+	},
+	{
+		name:     "SortInPlace",
+		example:  getSortInPlaceExample,
+		test:     getSortInPlaceTest,
+		method:   getSortInPlaceFunction,
+		mutating: true,
+		optIn:    "inplace",
+	},
+	{
+		name:    "SampleWeighted",
+		example: getSampleWeightedExample,
+		test:    getSampleWeightedTest,
+		method:  getSampleWeightedFunction,
+	},
+	{
+		name:     "CompactNil",
+		example:  getCompactNilExample,
+		test:     getCompactNilTest,
+		inPlace:  true,
+		method:   getCompactNilFunction,
+		pointers: true,
+	},
+	{
+		name:     "DerefOr",
+		example:  getDerefOrExample,
+		test:     getDerefOrTest,
+		method:   getDerefOrFunction,
+		pointers: true,
+	},
+	{
+		name:    "Sum",
+		example: getSumExample,
+		test:    getSumTest,
+		method:  getSumFunction,
+		numeric: true,
+	},
+	{
+		name:    "Average",
+		example: getAverageExample,
+		test:    getAverageTest,
+		method:  getAverageFunction,
+		numeric: true,
+	},
+	{
+		name:    "Min",
+		example: getMinExample,
+		test:    getMinTest,
+		method:  getMinFunction,
+		ordered: true,
+	},
+	{
+		name:    "Max",
+		example: getMaxExample,
+		test:    getMaxTest,
+		method:  getMaxFunction,
+		ordered: true,
+	},
+	{
+		name:    "Sort",
+		example: getSortedExample,
+		test:    getSortedTest,
+		inPlace: true,
+		method:  getSortFunction,
+		ordered: true,
+	},
+	{
+		name:    "TrimSpaceAll",
+		example: getTrimSpaceAllExample,
+		test:    getTrimSpaceAllTest,
+		method:  getTrimSpaceAllFunction,
+		textual: true,
+	},
+	{
+		name:    "ToLowerAll",
+		example: getToLowerAllExample,
+		test:    getToLowerAllTest,
+		method:  getToLowerAllFunction,
+		textual: true,
+	},
+	{
+		name:    "ToUpperAll",
+		example: getToUpperAllExample,
+		test:    getToUpperAllTest,
+		method:  getToUpperAllFunction,
+		textual: true,
+	},
+	{
+		name:    "NonEmpty",
+		example: getNonEmptyExample,
+		test:    getNonEmptyTest,
+		inPlace: true,
+		method:  getNonEmptyFunction,
+		textual: true,
+	},
+	{
+		name:    "JoinNonEmpty",
+		example: getJoinNonEmptyExample,
+		test:    getJoinNonEmptyTest,
+		method:  getJoinNonEmptyFunction,
+		textual: true,
+	},
+	{
+		name:         "Zip",
+		example:      getZipExample,
+		test:         getZipTest,
+		method:       getZipFunction,
+		needMapToMap: true,
+		pairs:        true,
+		optIn:        "pair",
+	},
+}
+
+// validName - a valid identifier, like the name of a package or of a method
+var validName = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+
+// methodSignature - the doc comment and the signature of a generated function
+var methodSignature = regexp.MustCompile(`(?m)^\s*// (.*)\n\s*(func .*) \{$`)
+
+// listMethods - describe every method of the generators (for a list of T, mapping to a list of U), with the signature and the doc comment of each generated function
+func listMethods(generators GeneratorList, chunked, pooled bool) string {
+	result := ""
+	optionListed := false
+	generators.Each(func(gen Generator) {
+		method := gen.method
+		if chunked && gen.chunkedMethod != nil {
+			method = gen.chunkedMethod
+		}
+		if pooled && gen.pooledMethod != nil {
+			method = gen.pooledMethod
+		}
+
+		// the members of the lists of the nested methods are slices, and the members of the lists of the methods
+		// needing pointers point to the members of a TList
+		typeName := "T"
+		if gen.nested {
+			typeName = "[]T"
+		}
+		code := ""
+		if gen.declare != nil {
+			code += gen.declare("TList", typeName)
+		}
+		switch {
+		case gen.needMapToMap:
+			code += method("TList", typeName, typeName, "") + method("TList", typeName, "U", "U")
+		case gen.pointers:
+			code += method("TPtrList", "*T", "T", "T")
+		default:
+			code += method("TList", typeName, "", "")
+		}
+		if strings.Contains(code, optionName("TList")) && !optionListed {
+			code += getOptionType("TList", "T")
+			optionListed = true
+		}
+
+		result += gen.name
+		if gen.optIn != "" {
+			result += " (not generated by default)"
+		}
+		if gen.nested {
+			result += " (only generated for the lists of slices)"
+		}
+		if gen.pointers {
+			result += " (only generated for the lists of pointers)"
+		}
+		if gen.pairs {
+			code = getPairType("TList", typeName, "UList", "U") + code
+		}
+		if gen.ordered {
+			result += " (only generated for the lists of numbers and strings)"
+		}
+		if gen.numeric {
+			result += " (only generated for the lists of numbers)"
+		}
+		if gen.textual {
+			result += " (only generated for the lists of strings)"
+		}
+		result += "\n"
+		for _, match := range methodSignature.FindAllStringSubmatch(code, -1) {
+			result += fmt.Sprintf("    %s\n        %s\n", match[2], match[1])
+		}
+	})
+	return result
+}
+
+var listTypeTemplate = render.Template("ListType")
+
+// generate - generate the list type and the methods selected for it (see plan.methodsOf). If chunked or pooled is set, the chunked or pooled variants of the parallel methods are used
+func generate(typeName, listname string, m map[string]string, p plan, chunked, pooled bool) string {
+	code := ""
+	if !p.declared[listname] {
+		code += "\n" + render.Declarations(listTypeTemplate, newTemplateData(listname, typeName, "", ""))
+	}
+
+	selected := p.methodsOf(listname)
+	selectedGenerators := p.generators.Filter(func(gen Generator) bool {
+		_, ok := selected[gen.name]
+		return ok
+	})
+
+	// the declarations returned by the methods of the other lists mapping to this list, like the future of
+	// MapAsync, are needed even if this list does not have the methods
+	methods := strings.Builder{}
+	p.generators.Filter(func(gen Generator) bool {
+		if gen.declare == nil || !gen.needMapToMap || selected[gen.name] {
+			return false
+		}
+		for _, k := range sortedTypes(m) {
+			otherList := strings.TrimPrefix(m[k], "*") + "List"
+			if p.methodsOf(otherList)[gen.name] && p.mapsTo(otherList, typeName) {
+				return true
+			}
+		}
+		return false
+	}).Each(func(gen Generator) {
+		methods.WriteString(gen.declare(listname, typeName))
+	})
+	// the lists with MapTargets only map to them
+	targets := []string{}
+	for _, k := range sortedTypes(m) {
+		if p.mapsTo(listname, k) {
+			targets = append(targets, k)
+		}
+	}
+	// the pair types are shared by the methods returning pairs, and declared with the list of their first members
+	if len(selectedGenerators.Filter(func(gen Generator) bool { return gen.pairs })) > 0 {
+		for _, k := range targets {
+			targetListName := listname
+			if k != typeName {
+				targetListName = strings.TrimPrefix(m[k], "*") + "List"
+			}
+			methods.WriteString(getPairType(listname, typeName, targetListName, k))
+		}
+	}
+	selectedGenerators.Each(func(gen Generator) {
+		method := gen.method
+		if p.grow && gen.grownMethod != nil {
+			method = gen.grownMethod
+		}
+		if p.twoPass && gen.twoPassMethod != nil {
+			method = gen.twoPassMethod
+		}
+		if p.copy && gen.copiedMethod != nil {
+			method = gen.copiedMethod
+		}
+		if p.generics && gen.generic != "" {
+			method = genericMethod(method, gen.genericBody)
+		}
+		if chunked && gen.chunkedMethod != nil {
+			method = gen.chunkedMethod
+		}
+		if pooled && gen.pooledMethod != nil {
+			method = gen.pooledMethod
+		}
+		if p.deref && gen.derefMethod != nil && strings.HasPrefix(typeName, "*") {
+			method = gen.derefMethod
+		}
+		if equality := p.equality[listname]; p.equalized(gen, listname) {
+			method = func(listName, typeName, _, _ string) string {
+				return gen.eqMethod(listName, typeName, equality.Eq, equality.Hash)
+			}
+		}
+
+		if gen.declare != nil {
+			methods.WriteString(gen.declare(listname, typeName))
+		}
+
+		// the methods of the lists of pointers return the list of the values when it is generated
+		if gen.pointers {
+			valueType := strings.TrimPrefix(typeName, "*")
+			methods.WriteString(method(listname, typeName, valueType, m[valueType]))
+		} else if gen.needMapToMap {
+			for _, k := range targets {
+				targetTypeName := m[k]
+				if k == typeName {
+					targetTypeName = ""
+				}
+				if gen.keyed && !p.keyable(k) {
+					continue
+				}
+
+				methods.WriteString(method(listname, typeName, k, targetTypeName))
+			}
+		} else {
+			methods.WriteString(method(listname, typeName, "", ""))
+		}
+	})
+	for _, field := range p.fields[listname] {
+		methods.WriteString(getPluckFunction(listname, typeName, field, p.listOf(field.Type)))
+	}
+	methodsCode := methods.String()
+
+	// the shared declarations are generated when a selected method needs them, including the methods of the
+	// -templates which call them
+	poolCode := ""
+	if pooled && (len(selectedGenerators.Filter(func(gen Generator) bool {
+		return gen.pooledMethod != nil
+	})) > 0 || strings.Contains(methodsCode, listname+"Pool") || strings.Contains(methodsCode, listname+"Run(")) {
+		poolCode = getPoolType(listname, typeName)
+	}
+
+	optionCode := ""
+	if strings.Contains(methodsCode, optionName(listname)) {
+		optionCode = getOptionType(listname, typeName)
+	}
+
+	if len(selectedGenerators.Filter(func(gen Generator) bool {
+		return gen.parallel
+	})) > 0 || strings.Contains(methodsCode+poolCode, listname+"Workers(") {
+		code += getMaxWorkersVariable(listname, typeName)
+	}
+
+	return code + poolCode + optionCode + methodsCode
+}
@@ -0,0 +1,77 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestListMethods(t *testing.T) {
+	result := "\n" + listMethods(generators, false, false)
+
+	if !strings.HasPrefix(result, "\nMap\n    func (l TList) Map(f func(T) T) TList\n") {
+		t.Fail()
+	}
+	if !strings.Contains(result, "\nFilterChan (not generated by default)\n    func TListFilterChan(in <-chan T, f func(T) bool) <-chan T\n") {
+		t.Fail()
+	}
+	if !strings.Contains(result, "\nDerefOr (only generated for the lists of pointers)\n    func (l TPtrList) DerefOr(def T) TList\n") {
+		t.Fail()
+	}
+	if !strings.Contains(result, "\nFlatten (only generated for the lists of slices)\n    func (l TList) Flatten() []T\n") {
+		t.Fail()
+	}
+	if !strings.Contains(result, "\nMin (only generated for the lists of numbers and strings)\n    func (l TList) Min() (T, bool)\n") {
+		t.Fail()
+	}
+	if !strings.Contains(result, "\nAverage (only generated for the lists of numbers)\n    func (l TList) Average() float64\n") {
+		t.Fail()
+	}
+	if !strings.Contains(result, "\nZip (not generated by default)\n    func (l TUPairList) Firsts() TList\n") {
+		t.Fail()
+	}
+	generators.Each(func(gen Generator) {
+		if !strings.Contains(result, "\n"+gen.name+"\n") && !strings.Contains(result, "\n"+gen.name+" (") {
+			t.Fail()
+		}
+	})
+}
+
+func TestGenerateDependencies(t *testing.T) {
+	m := map[string]string{"int": "int", "string": "string"}
+	src := "package main\n" + generate("int", "intList", m, planOf("Map,Filter,Reduce"), false, false)
+	imports, err := resolveImports(src, nil)
+	if err != nil || len(imports) != 0 || strings.Contains(src, "intListWorkers") {
+		t.Fail()
+	}
+
+	src = "package main\n" + generate("int", "intList", m, planOf("Filter,PMap"), false, false)
+	imports, err = resolveImports(src, nil)
+	if err != nil || strings.Join(imports, ",") != "sync" || !strings.Contains(src, "func intListWorkers") {
+		t.Fail()
+	}
+
+	src = "package main\n" + generate("int", "intList", m, planOf("PAny"), false, true)
+	imports, err = resolveImports(src, nil)
+	if err != nil || strings.Join(imports, ",") != "sync" || !strings.Contains(src, "type intListPool") {
+		t.Fail()
+	}
+}
+
+// TestMethodTemplates - every variant of the built-in methods renders code which parses, so that a template is not
+// only checked by the variants of the golden files
+func TestMethodTemplates(t *testing.T) {
+	generators.Each(func(gen Generator) {
+		variants := map[string]func(_, _, _, _ string) string{
+			"method": gen.method, "chunked": gen.chunkedMethod, "pooled": gen.pooledMethod, "deref": gen.derefMethod,
+			"grown": gen.grownMethod, "copied": gen.copiedMethod, "two-pass": gen.twoPassMethod,
+		}
+		for variant, method := range variants {
+			if method != nil && method("intList", "int", "string", "string") == "" {
+				t.Errorf("%s: the %s variant generates no code", gen.name, variant)
+			}
+		}
+		if gen.eqMethod != nil && gen.eqMethod("intList", "int", "sameInt", "hashInt") == "" {
+			t.Errorf("%s: the eq variant generates no code", gen.name)
+		}
+	})
+}
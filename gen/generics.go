@@ -0,0 +1,216 @@
+package gen
+
+import (
+	"fmt"
+
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+// GenerateGenerics - generate the formatted source of the file of the generic functions which the methods of the
+// lists call with Spec.Generics, eg: 'Map[T, U any]' and 'Filter[T any]'. It only needs the Package, the Header, the
+// Templates and the options of the generic functions (Grow, TwoPass and Copy) of the Spec, the functions are the same
+// for all the lists
+func GenerateGenerics(spec Spec) (_ []byte, err error) {
+	defer render.Recover(&err)
+	code := ""
+	spec.Templates.generators().Each(func(gen Generator) {
+		if spec.TwoPass && gen.twoPassGeneric != "" {
+			code += gen.twoPassGeneric
+		} else if spec.Grow && gen.grownGeneric != "" {
+			code += gen.grownGeneric
+		} else if spec.Copy && gen.copiedGeneric != "" {
+			code += gen.copiedGeneric
+		} else if gen.generic != "" {
+			code += gen.generic
+		}
+	})
+	p := plan{}
+	return p.finish(spec.Header+fmt.Sprintf(`package %[1]s
+
+            `, spec.Package)+code, "the generic functions")
+}
+
+// GenericFunction - get the name of the generic function which a method of a list calls with Spec.Generics, given the
+// names of the target lists without the 'List' suffix, eg: 'Map' for 'MapStr', or nothing if the method has none
+func GenericFunction(name string, targets []string) string {
+	method := methodOf(generators, name, "", "", targets)
+	result := ""
+	generators.Each(func(gen Generator) {
+		if gen.name == method && gen.generic != "" {
+			result = method
+		}
+	})
+	return result
+}
+
+var genericMethodTemplate = render.Template("GenericMethod")
+
+// genericMethod - get the method of a generator with Spec.Generics: the doc comment and the signature of the method
+// generated by the generator, with the body calling its generic function, eg: 'return Map(l, f)'
+func genericMethod(method func(_, _, _, _ string) string, body string) func(_, _, _, _ string) string {
+	return func(listName, typeName, targetType, targetTypeName string) string {
+		code := method(listName, typeName, targetType, targetTypeName)
+		match := methodSignature.FindStringSubmatch(code)
+		if match == nil {
+			return code
+		}
+		return render.Declarations(genericMethodTemplate, struct{ Comment, Signature, Body string }{match[1], match[2], body})
+	}
+}
+
+const genericMap = `
+        // Map is a function that takes a function of type T -> U and applies it to every member of a list of type []T
+        func Map[T, U any](l []T, f func(T) U) []U {
+            l2 := make([]U, len(l))
+            for i, t := range l {
+                l2[i] = f(t)
+            }
+            return l2
+        }
+        `
+
+var (
+	genericFilter      = genericFilterFunction(false)
+	genericGrownFilter = genericFilterFunction(true)
+)
+
+var genericFilterTemplate = render.Template("GenericFilter")
+
+// genericFilterFunction - get the generic Filter, allocating its result like the Filter methods (see resultAllocation)
+func genericFilterFunction(grow bool) string {
+	return render.Declarations(genericFilterTemplate, struct{ Allocation string }{resultAllocation("[]T", grow)})
+}
+
+// genericTwoPassFilter - the generic Filter with Spec.TwoPass (see getTwoPassFilterFunction)
+const genericTwoPassFilter = `
+        // Filter is a function that takes a function of type T -> bool and returns a list which contains all the members of a list of type []T for which the function returned true. The members kept are counted first, so that the list returned is allocated with their exact number
+        func Filter[T any](l []T, f func(T) bool) []T {
+            keep := make([]bool, len(l))
+            n := 0
+            for i, t := range l {
+                if f(t) {
+                    keep[i] = true
+                    n++
+                }
+            }
+            l2 := make([]T, 0, n)
+            for i, t := range l {
+                if keep[i] {
+                    l2 = append(l2, t)
+                }
+            }
+            return l2
+        }
+        `
+
+const genericReduce = `
+        // Reduce is a function that takes a function of type (T, T) -> T and returns a T which is the result of applying the function to all the members of a list of type []T starting from the first member
+        func Reduce[T any](l []T, t1 T, f func(T, T) T) T {
+            for _, t := range l {
+                t1 = f(t1, t)
+            }
+            return t1
+        }
+        `
+
+const genericReduceRight = `
+        // ReduceRight is a function that takes a function of type (T, T) -> T and returns a T which is the result of applying the function to all the members of a list of type []T starting from the last member
+        func ReduceRight[T any](l []T, t1 T, f func(T, T) T) T {
+            for i := len(l) - 1; i >= 0; i-- {
+                t1 = f(l[i], t1)
+            }
+            return t1
+        }
+        `
+
+var (
+	genericTake            = genericTakeFunction(false)
+	genericCopiedTake      = genericTakeFunction(true)
+	genericTakeWhile       = genericTakeWhileFunction(false)
+	genericCopiedTakeWhile = genericTakeWhileFunction(true)
+	genericDrop            = genericDropFunction(false)
+	genericCopiedDrop      = genericDropFunction(true)
+	genericDropWhile       = genericDropWhileFunction(false)
+	genericCopiedDropWhile = genericDropWhileFunction(true)
+)
+
+var genericTakeTemplate = render.Template("GenericTake")
+
+// genericTakeFunction, genericTakeWhileFunction, genericDropFunction and genericDropWhileFunction - get the generic
+// Take, TakeWhile, Drop and DropWhile, sharing the backing array of the list or copying their result like the methods
+// (see sliceResult)
+func genericTakeFunction(copied bool) string {
+	return render.Declarations(genericTakeTemplate, struct{ Result, Whole, Comment string }{sliceResult("[]T", "l[:n]", copied), sliceResult("[]T", "l", copied), sliceResultComment(copied)})
+}
+
+var genericTakeWhileTemplate = render.Template("GenericTakeWhile")
+
+func genericTakeWhileFunction(copied bool) string {
+	return render.Declarations(genericTakeWhileTemplate, struct{ Result, Whole, Comment string }{sliceResult("[]T", "l[:i]", copied), sliceResult("[]T", "l", copied), sliceResultComment(copied)})
+}
+
+var genericDropTemplate = render.Template("GenericDrop")
+
+func genericDropFunction(copied bool) string {
+	return render.Declarations(genericDropTemplate, struct{ Result, Comment string }{sliceResult("[]T", "l[n:]", copied), sliceResultComment(copied)})
+}
+
+var genericDropWhileTemplate = render.Template("GenericDropWhile")
+
+func genericDropWhileFunction(copied bool) string {
+	return render.Declarations(genericDropWhileTemplate, struct{ Result, Comment string }{sliceResult("[]T", "l[i:]", copied), sliceResultComment(copied)})
+}
+
+const genericEach = `
+        // Each is a function that takes a function of type T -> void and applies it to each member of a list of type []T
+        func Each[T any](l []T, f func(T)) {
+            for _, t := range l {
+                f(t)
+            }
+        }
+        `
+
+const genericEachI = `
+        // EachI is a function that takes a function of type (int, T) -> void and applies it to each member of a list of type []T, with the index of the member
+        func EachI[T any](l []T, f func(int, T)) {
+            for i, t := range l {
+                f(i, t)
+            }
+        }
+        `
+
+const genericAll = `
+        // All is a function that returns true if all the members of a list of type []T satisfy a function or if the list is empty
+        func All[T any](l []T, f func(T) bool) bool {
+            for _, t := range l {
+                if !f(t) {
+                    return false
+                }
+            }
+            return true
+        }
+        `
+
+const genericAny = `
+        // Any is a function that returns true if at least one member of a list of type []T satisfies a function. It returns false if the list is empty.
+        func Any[T any](l []T, f func(T) bool) bool {
+            for _, t := range l {
+                if f(t) {
+                    return true
+                }
+            }
+            return false
+        }
+        `
+
+var (
+	genericFilterMap      = genericFilterMapFunction(false)
+	genericGrownFilterMap = genericFilterMapFunction(true)
+)
+
+var genericFilterMapTemplate = render.Template("GenericFilterMap")
+
+// genericFilterMapFunction - get the generic FilterMap, allocating its result like the FilterMap methods
+func genericFilterMapFunction(grow bool) string {
+	return render.Declarations(genericFilterMapTemplate, struct{ Allocation string }{resultAllocation("[]U", grow)})
+}
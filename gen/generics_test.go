@@ -0,0 +1,51 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateGenerics(t *testing.T) {
+	src, err := GenerateGenerics(Spec{Package: "main", Header: "// Code generated by a test; DO NOT EDIT.\n\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"package main\n", "func Map[T, U any](l []T, f func(T) U) []U {", "func Filter[T any](l []T, f func(T) bool) []T {", "func FilterMap[T, U any](l []T, fMap func(T) U, fFilters ...func(T) bool) []U {"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+	if strings.Contains(code, "PMap") || strings.Contains(code, "import") {
+		t.Error(code)
+	}
+}
+
+func TestGenerateWithGenerics(t *testing.T) {
+	src, err := Generate(Spec{Package: "main", Types: map[string]string{"int": "int", "string": "Str"}, Methods: []string{"Map", "PMap", "Each", "Drop"}, Prefix: "F", Generics: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{
+		"func (l intList) FMap(f func(int) int) intList {\n\treturn Map(l, f)\n}",
+		"func (l intList) FMapStr(f func(int) string) StrList {\n\treturn Map(l, f)\n}",
+		"func (l StrList) FEach(f func(string)) StrList {\n\tEach(l, f)\n\treturn l\n}",
+		"func (l StrList) FDrop(n int) StrList {\n\treturn Drop(l, n)\n}",
+		"// FMap is a method on intList that takes a function of type int -> int and applies it to every member of intList\n",
+		"wg := sync.WaitGroup{}",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+}
+
+func TestGenericFunction(t *testing.T) {
+	targets := []string{"Str", "Int"}
+	for name, expected := range map[string]string{"Map": "Map", "MapStr": "Map", "FilterMapInt": "FilterMap", "Take": "Take", "PMap": "", "MapUser": "", "ToSet": ""} {
+		if function := GenericFunction(name, targets); function != expected {
+			t.Error(name, function)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package gen
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "write the testdata/*.golden files with the generated code instead of comparing it with them")
+
+// checkGolden - check that the code generated by a test parses, and compare it, formatted, with its
+// testdata/<name>.golden file, or write the file with -update. The code can be a source file or declarations, which
+// are parsed and formatted in a file of the package, without its package clause in the .golden file
+func checkGolden(t *testing.T, name, src string) {
+	t.Helper()
+	const clause = "package gen\n"
+	file := src
+	if !strings.HasPrefix(strings.TrimSpace(src), "package ") {
+		file = clause + src
+	}
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, name+".golden", file, parser.ParseComments)
+	if err != nil {
+		t.Errorf("%s: the generated code does not parse: %s\n%s", name, err, src)
+		return
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, parsed); err != nil {
+		t.Fatal(err)
+	}
+	formatted := buf.Bytes()
+	if file != src {
+		formatted = bytes.TrimLeft(bytes.TrimPrefix(formatted, []byte(clause)), "\n")
+	}
+
+	filename := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := ioutil.WriteFile(filename, formatted, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Errorf("%s: %s (run 'go test -update' to write it)", name, err)
+		return
+	}
+	if string(formatted) != string(expected) {
+		t.Errorf("%s: the generated code is not the code of %s (run 'go test -update' to update it):\n%s", name, filename, goldenDiff(string(expected), string(formatted)))
+	}
+}
+
+// goldenDiff - the lines of the expected code missing from the generated code, prefixed with '-', and the lines of the
+// generated code missing from the expected code, prefixed with '+', with their line numbers
+func goldenDiff(expected, generated string) string {
+	a, b := strings.Split(expected, "\n"), strings.Split(generated, "\n")
+	// common[i][j] - the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	diff := ""
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i, j = i+1, j+1
+		case j < len(b) && (i == len(a) || common[i][j+1] >= common[i+1][j]):
+			diff += fmt.Sprintf("%5d + %s\n", j+1, b[j])
+			j++
+		default:
+			diff += fmt.Sprintf("%5d - %s\n", i+1, a[i])
+			i++
+		}
+	}
+	return diff
+}
+
+func TestGoldenDiff(t *testing.T) {
+	diff := goldenDiff("a\nb\nc\n", "a\nc\nd\n")
+	if diff != "    2 - b\n    3 + d\n" {
+		t.Errorf("%q", diff)
+	}
+	if diff := goldenDiff("a\n", "a\n"); diff != "" {
+		t.Errorf("%q", diff)
+	}
+}
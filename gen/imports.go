@@ -0,0 +1,184 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// standardPackages - the standard packages which the generated code can refer to, by the name they are referred to
+// with. The element types given with -types can also refer to them, eg: 'time.Time'
+var standardPackages = map[string]string{
+	"atomic":  "sync/atomic",
+	"bytes":   "bytes",
+	"cmp":     "cmp",
+	"context": "context",
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"io":      "io",
+	"iter":    "iter",
+	"json":    "encoding/json",
+	"maps":    "maps",
+	"math":    "math",
+	"quick":   "testing/quick",
+	"rand":    "math/rand",
+	"reflect": "reflect",
+	"runtime": "runtime",
+	"slices":  "slices",
+	"sort":    "sort",
+	"strconv": "strconv",
+	"strings": "strings",
+	"sync":    "sync",
+	"testing": "testing",
+	"time":    "time",
+	"unicode": "unicode",
+	"utf8":    "unicode/utf8",
+}
+
+// usedPackages - get the names of the packages which a source file refers to without declaring or importing them, eg:
+// 'sync' for 'sync.WaitGroup'
+func usedPackages(src string) (map[string]bool, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	unresolved := map[*ast.Ident]bool{}
+	for _, ident := range file.Unresolved {
+		unresolved[ident] = true
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok && unresolved[ident] {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return used, nil
+}
+
+// resolveImports - get the import paths needed by a source file, in the order of gofmt. The standard packages are
+// imported only if the code refers to them, and the other packages requested (eg: by the '{{import}}' of a template)
+// are always imported since the name they are referred to with cannot be known
+func resolveImports(src string, requested []string) ([]string, error) {
+	used, err := usedPackages(src)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string]bool{}
+	for name := range used {
+		if path, ok := standardPackages[name]; ok {
+			paths[path] = true
+		}
+	}
+	standard := map[string]bool{}
+	for _, path := range standardPackages {
+		standard[path] = true
+	}
+	for _, path := range requested {
+		if !standard[path] {
+			paths[path] = true
+		}
+	}
+
+	result := []string{}
+	for path := range paths {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// usedImports - get the imports of the plan which a generated source refers to, by the names of their packages (see
+// QualifyTypes), so that the files with the code of some of the types only, like the fuzz and the property tests of the
+// lists of numbers, do not import the packages of the other types
+func (p plan) usedImports(src string) ([]string, error) {
+	used, err := usedPackages(src)
+	if err != nil {
+		return nil, err
+	}
+	result := []string{}
+	for _, importPath := range p.imports {
+		if used[importPath[strings.LastIndex(importPath, "/")+1:]] {
+			result = append(result, importPath)
+		}
+	}
+	return result, nil
+}
+
+// addImports - add an import declaration with the imports needed by the generated code right after its package clause
+func addImports(src string, requested []string) (string, error) {
+	imports, err := resolveImports(src, requested)
+	if err != nil || len(imports) == 0 {
+		return src, err
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.PackageClauseOnly)
+	if err != nil {
+		return "", err
+	}
+	quoted := []string{}
+	for _, path := range imports {
+		quoted = append(quoted, strconv.Quote(path))
+	}
+
+	insertAt := int(file.Name.End()) - 1
+	return src[:insertAt] + "\n\nimport (\n\t" + strings.Join(quoted, "\n\t") + "\n)" + src[insertAt:], nil
+}
+
+// QualifyTypes - replace the element types given with the import path of their package, eg:
+// 'github.com/user/app/model.User' (or '*github.com/user/app/model.User'), by the type qualified with the name of the
+// package, 'model.User', and get the import paths. The names of the packages are the last elements of their paths. The
+// key and the value types of a map type, eg: 'map[string]github.com/user/app/model.User', are qualified too
+func QualifyTypes(m map[string]string) (map[string]string, []string, error) {
+	result := map[string]string{}
+	imports := []string{}
+	packages := map[string]string{}
+	var qualify func(typeName string) (string, error)
+	qualify = func(typeName string) (string, error) {
+		prefix := typeName[:len(typeName)-len(strings.TrimLeft(typeName, "*[]"))]
+		qualified := typeName[len(prefix):]
+		if key, value, ok := splitMapType(qualified); ok {
+			var err error
+			if key, err = qualify(key); err == nil {
+				value, err = qualify(value)
+			}
+			return prefix + "map[" + key + "]" + value, err
+		}
+		slash := strings.LastIndex(qualified, "/")
+		dot := strings.LastIndex(qualified, ".")
+		if slash < 0 || dot < slash {
+			return typeName, nil
+		}
+
+		importPath, name := qualified[:dot], qualified[slash+1:dot]
+		if !validName.MatchString(name) {
+			return "", fmt.Errorf("'%s' is not valid: '%s' cannot be used as the name of the package '%s'", typeName, name, importPath)
+		}
+		if other, ok := packages[name]; ok && other != importPath {
+			return "", fmt.Errorf("'%s' is not valid: the packages '%s' and '%s' have the same name", typeName, other, importPath)
+		}
+		if _, ok := packages[name]; !ok {
+			imports = append(imports, importPath)
+		}
+		packages[name] = importPath
+		return prefix + name + qualified[dot:], nil
+	}
+
+	for _, typeName := range sortedTypes(m) {
+		qualified, err := qualify(typeName)
+		if err != nil {
+			return nil, nil, err
+		}
+		result[qualified] = m[typeName]
+	}
+	return result, imports, nil
+}
@@ -0,0 +1,74 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveImports(t *testing.T) {
+	src := `package main
+
+type timeList []time.Time
+
+func (l timeList) PEach(f func(time.Time)) {
+	var wg sync.WaitGroup
+	for _, t := range l {
+		wg.Add(1)
+		go func(t time.Time) {
+			defer wg.Done()
+			f(t)
+		}(t)
+	}
+	wg.Wait()
+}
+
+func (l timeList) Sort(sort func(time.Time, time.Time) bool) {
+	_ = sort.Value
+}
+`
+	imports, err := resolveImports(src, []string{"sort", "github.com/user/pkg"})
+	if err != nil || strings.Join(imports, ",") != "github.com/user/pkg,sync,time" {
+		t.Fail()
+	}
+
+	if _, err := resolveImports("package main\n\nfunc {", nil); err == nil {
+		t.Fail()
+	}
+}
+
+func TestAddImports(t *testing.T) {
+	src := "// Code generated by fungen; DO NOT EDIT.\n\npackage main\n\nvar d = time.Second\n"
+	expected := "// Code generated by fungen; DO NOT EDIT.\n\npackage main\n\nimport (\n\t\"time\"\n)\n\nvar d = time.Second\n"
+	result, err := addImports(src, nil)
+	if err != nil || result != expected {
+		t.Fail()
+	}
+
+	src = "package main\n\nvar n = 1\n"
+	result, err = addImports(src, nil)
+	if err != nil || result != src {
+		t.Fail()
+	}
+}
+
+func TestQualifyTypes(t *testing.T) {
+	m, imports, err := QualifyTypes(map[string]string{"example.com/app/model.User": "User", "*example.com/app/model.User": "UserPtr", "time.Time": "Time", "int": "int"})
+	if err != nil || strings.Join(imports, ",") != "example.com/app/model" || len(m) != 4 {
+		t.Fail()
+	}
+	if m["model.User"] != "User" || m["*model.User"] != "UserPtr" || m["time.Time"] != "Time" || m["int"] != "int" {
+		t.Fail()
+	}
+
+	if _, _, err := QualifyTypes(map[string]string{"a/model.User": "U", "b/model.T": "T"}); err == nil {
+		t.Fail()
+	}
+	if _, _, err := QualifyTypes(map[string]string{"example.com/go-model.User": "U"}); err == nil {
+		t.Fail()
+	}
+
+	m, imports, err = QualifyTypes(map[string]string{"map[example.com/app/id.ID]*example.com/app/model.User": "users"})
+	if err != nil || strings.Join(imports, ",") != "example.com/app/id,example.com/app/model" || m["map[id.ID]*model.User"] != "users" {
+		t.Error(err, imports, m)
+	}
+}
@@ -0,0 +1,100 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// SplitMethods - split a source generated for a Spec (see Generate) into a source for each method of each list, with
+// the functions the method generates (eg: 'MapStr' and 'MapInt' for 'Map'), by the name of the list and the name of the
+// method, eg: 'intList.Map', and a source with the other declarations, like the list types and the declarations shared
+// by the methods, by the empty key. Every source has the header and the package clause of the generated source and its
+// own imports
+func SplitMethods(spec Spec, src []byte) (map[string][]byte, error) {
+	p, err := newPlan(spec)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("the generated code is not valid: %s", err)
+	}
+
+	lists := p.lists()
+	targets := []string{}
+	for _, name := range p.targets {
+		targets = append(targets, strings.Title(strings.TrimPrefix(name, "*")))
+	}
+
+	// every declaration comes with the comments before it, down to the previous declaration
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+	header := string(src[:offset(file.Name.End())]) + "\n"
+	bodies := map[string]string{"": ""}
+	keys := []string{""}
+	previous := offset(file.Name.End())
+	for _, decl := range file.Decls {
+		code := string(src[previous:offset(decl.End())])
+		previous = offset(decl.End())
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			continue
+		}
+
+		key := ""
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+			listName := DeclarationName(fn)
+			if _, ok := lists[listName]; ok {
+				if method := methodOf(p.generators, fn.Name.Name, spec.Prefix, spec.Suffix, targets); method != "" {
+					key = listName + "." + method
+				}
+			}
+		}
+		if _, ok := bodies[key]; !ok {
+			keys = append(keys, key)
+		}
+		bodies[key] += code
+	}
+
+	result := map[string][]byte{}
+	for _, key := range keys {
+		what := "the declarations shared by the methods"
+		if key != "" {
+			what = "the method " + key
+		}
+		code, err := addImports(header+bodies[key]+"\n", p.imports)
+		if err != nil {
+			return nil, fmt.Errorf("resolving the imports of %s: %s", what, err)
+		}
+		if result[key], err = Format([]byte(code), what); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// methodOf - get the method of the generators which generates a method of a list, given the Prefix and the Suffix and
+// the names of the target lists without the 'List' suffix, eg: 'Map' for 'FMapStrF', or 'Pluck' for the Pluck methods
+// of the fields, or nothing if no method generates it (eg: the methods added by a plugin)
+func methodOf(generators GeneratorList, name, prefix, suffix string, targets []string) string {
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) || len(name) < len(prefix)+len(suffix) {
+		return ""
+	}
+	name = name[len(prefix) : len(name)-len(suffix)]
+
+	result := ""
+	generators.Each(func(gen Generator) {
+		if len(gen.name) <= len(result) || !strings.HasPrefix(name, gen.name) {
+			return
+		}
+		if name == gen.name || gen.needMapToMap && contains(targets, name[len(gen.name):]) {
+			result = gen.name
+		}
+	})
+	if result == "" && strings.HasPrefix(name, pluckPrefix) && validName.MatchString(name[len(pluckPrefix):]) {
+		result = pluckPrefix
+	}
+	return result
+}
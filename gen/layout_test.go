@@ -0,0 +1,54 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitMethods(t *testing.T) {
+	spec := Spec{Package: "main", Header: "// Code generated by a test; DO NOT EDIT.\n\n", Types: map[string]string{"int": "int", "time.Time": "Time"}, Methods: []string{"Map", "PFilter", "Take"}, Prefix: "F"}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := SplitMethods(spec, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 7 {
+		t.Error(len(sources))
+	}
+
+	shared := string(sources[""])
+	if !strings.HasPrefix(shared, spec.Header+"package main\n") || !strings.Contains(shared, "type TimeList []time.Time") || !strings.Contains(shared, "func intListWorkers(") || strings.Contains(shared, ") FMap") {
+		t.Error(shared)
+	}
+	mapped := string(sources["intList.Map"])
+	if !strings.Contains(mapped, ") FMap(") || !strings.Contains(mapped, ") FMapTime(") || !strings.Contains(mapped, "\"time\"") || strings.Contains(mapped, "type intList") {
+		t.Error(mapped)
+	}
+	if taken := string(sources["TimeList.Take"]); !strings.Contains(taken, ") FTake(") || strings.Contains(taken, "import") {
+		t.Error(taken)
+	}
+	if filtered := string(sources["TimeList.PFilter"]); !strings.Contains(filtered, "\"sync\"") {
+		t.Error(filtered)
+	}
+}
+
+func TestMethodOf(t *testing.T) {
+	targets := []string{"Str", "Int", "Rate"}
+	for name, expected := range map[string]string{
+		"Map":       "Map",
+		"MapStr":    "Map",
+		"PMapRate":  "PMapRate",
+		"FilterStr": "",
+		"Median":    "",
+	} {
+		if method := methodOf(generators, name, "", "", targets); method != expected {
+			t.Error(name, method)
+		}
+	}
+	if methodOf(generators, "FMapIntX", "F", "X", targets) != "Map" || methodOf(generators, "MapInt", "F", "", targets) != "" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,226 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+// splitMapType - split a map type, eg: 'map[string]model.User', into its key type and its value type
+func splitMapType(typeName string) (string, string, bool) {
+	if !strings.HasPrefix(typeName, "map[") {
+		return "", "", false
+	}
+	depth := 0
+	for i := len("map"); i < len(typeName); i++ {
+		switch typeName[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				key, value := typeName[len("map["):i], typeName[i+1:]
+				return key, value, key != "" && value != ""
+			}
+		}
+	}
+	return "", "", false
+}
+
+// comparableType - whether the values of a type can be compared with ==, and be the keys of a map, judging by the type
+// expression: the slices, the maps and the functions, and the arrays of them, cannot. The named types are assumed to be
+// comparable
+func comparableType(typeName string) bool {
+	for strings.HasPrefix(typeName, "[") && !strings.HasPrefix(typeName, "[]") {
+		typeName = typeName[strings.Index(typeName, "]")+1:]
+	}
+	return !strings.HasPrefix(typeName, "[]") && !strings.HasPrefix(typeName, "map[") && !strings.HasPrefix(typeName, "func(")
+}
+
+// MapType - whether a type expression is a map type with a key and a value type, eg: 'map[string]User', and not a
+// malformed one, eg: 'map[string]'
+func MapType(typeName string) bool {
+	_, _, ok := splitMapType(typeName)
+	return ok
+}
+
+// InterfaceType - whether a type is an interface, judging by the type expression: error, any and the interface
+// literals, eg: 'interface{ Len() int }'. The interfaces can be compared with ==, but the comparison panics when the
+// dynamic types of the values cannot, so the methods needing members which can be compared are not generated for them
+func InterfaceType(typeName string) bool {
+	return typeName == "error" || typeName == "any" || strings.HasPrefix(typeName, "interface{")
+}
+
+// safeKey - whether the values of a type can safely be the keys of the maps of the generated methods, like PGroupBy:
+// the types which can be compared, except the interfaces
+func safeKey(typeName string) bool {
+	return comparableType(typeName) && !InterfaceType(typeName)
+}
+
+// orderedType - whether the values of a type can be ordered with <, judging by the type expression: 'number' for the
+// integers and the floats, 'string' for the strings, or nothing. The named types are assumed not to be ordered
+func orderedType(typeName string) string {
+	switch typeName {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune", "float32", "float64":
+		return "number"
+	case "string":
+		return "string"
+	}
+	return ""
+}
+
+// ordering - whether the values of a type can be ordered with < (see orderedType), judging by the type expression or from
+// the type-checking of the package (see Spec.Ordered)
+func (p plan) ordering(typeName string) string {
+	if ordered := orderedType(typeName); ordered != "" {
+		return ordered
+	}
+	return p.ordered[typeName]
+}
+
+// equalized - whether a generator comparing the members of a list is generated with the functions of Spec.Equality:
+// the functions are given for the list, and the hash function if the generator needs it
+func (p plan) equalized(gen Generator, listName string) bool {
+	equality, ok := p.equality[listName]
+	return ok && gen.eqMethod != nil && (equality.Hash != "" || !gen.hashed)
+}
+
+// keyable - whether the values of a type can safely be compared and be the keys of the maps of the generated methods
+// (see safeKey), and are not known to be incomparable from the type-checking of the package (see Spec.Incomparable)
+func (p plan) keyable(typeName string) bool {
+	return safeKey(typeName) && p.incomparable[typeName] == ""
+}
+
+// compared - get the type of the values which the methods comparing the members of the lists of a type compare: the
+// type the pointers point to with Spec.Deref, or the type
+func (p plan) compared(typeName string) string {
+	if p.deref {
+		return strings.TrimPrefix(typeName, "*")
+	}
+	return typeName
+}
+
+// misfit - describe why a generator does not fit the members of the lists of a type, and is not generated for them, eg:
+// "the members of 'User' are not numbers", or nothing if it fits them
+func (p plan) misfit(gen Generator, typeName, listName string) string {
+	compared := p.compared(typeName)
+	switch {
+	case gen.comparable && !p.keyable(compared) && !p.equalized(gen, listName):
+		if reason := p.incomparable[compared]; reason != "" {
+			return fmt.Sprintf("the members of '%s' cannot safely be compared (%s)", compared, reason)
+		}
+		if InterfaceType(compared) {
+			return fmt.Sprintf("the members of '%s' are interfaces, whose comparison panics for the dynamic types which cannot be compared", compared)
+		}
+		return fmt.Sprintf("the members of '%s' cannot be compared", compared)
+	case gen.ordered && p.ordering(typeName) == "":
+		return fmt.Sprintf("the members of '%s' cannot be ordered", typeName)
+	case gen.numeric && p.ordering(typeName) != "number":
+		return fmt.Sprintf("the members of '%s' are not numbers", typeName)
+	case gen.textual && p.ordering(typeName) != "string":
+		return fmt.Sprintf("the members of '%s' are not strings", typeName)
+	case gen.nested && !strings.HasPrefix(typeName, "[]"):
+		return fmt.Sprintf("the members of '%s' are not slices", typeName)
+	case gen.pointers && !strings.HasPrefix(typeName, "*"):
+		return fmt.Sprintf("the members of '%s' are not pointers", typeName)
+	}
+	return ""
+}
+
+// mapData - the data which the templates of the maps are executed with
+type mapData struct {
+	// Name - the name of the map type, eg: 'userIndex'
+	Name string
+	// MapType - the map type, eg: 'map[string]User'
+	MapType string
+	// KeyType, ValueType - the types of the keys and of the values, eg: 'string' and 'User'
+	KeyType, ValueType string
+	// KeysType, ValuesType - the lists of the keys and of the values, eg: 'stringList', or the slices if they have no
+	// list
+	KeysType, ValuesType string
+	// TargetType, TargetMapType, Suffix - the type which MapValues maps the values to, the map it returns and the suffix
+	// of its name, eg: 'int', 'map[string]int' and 'Int' (in 'MapValuesInt')
+	TargetType, TargetMapType, Suffix string
+}
+
+var mapTypeTemplate = render.Template("MapType")
+
+// generateMap - generate a map type and its methods. The keys and the values are returned in the lists of their types
+// if they are Targets, MapValues maps the values to every Target, and Invert is only generated if the values can be the
+// keys of a map. With Spec.Concurrent, the concurrent variant of the map is generated too
+func generateMap(typeName, name string, p plan) string {
+	keyType, valueType, _ := splitMapType(typeName)
+	listOf := p.listOf
+
+	code := ""
+	if !p.declared[name] {
+		code += "\n" + render.Declarations(mapTypeTemplate, mapData{Name: name, KeyType: keyType, ValueType: valueType, MapType: typeName})
+	}
+
+	code += getKeysFunction(name, keyType, listOf(keyType))
+	code += getKeysSortedFunction(name, keyType, listOf(keyType))
+	code += getValuesFunction(name, valueType, listOf(valueType))
+	if comparableType(valueType) && p.incomparable[valueType] == "" {
+		code += getInvertFunction(name, keyType, valueType)
+	}
+	code += getMapValuesFunction(name, keyType, valueType, valueType, "")
+	for _, targetType := range sortedTypes(p.targets) {
+		if targetType != valueType {
+			code += getMapValuesFunction(name, keyType, valueType, targetType, p.targets[targetType])
+		}
+	}
+	code += getFilterMapEntriesFunction(name, keyType, valueType)
+	code += getMergeFunction(name)
+	if p.concurrent {
+		code += getConcurrentMapType(name, keyType, valueType, listOf(keyType), listOf(valueType))
+	}
+	return code
+}
+
+var keysTemplate = render.Template("Keys")
+
+func getKeysFunction(name, keyType, keysType string) string {
+	return render.Declarations(keysTemplate, mapData{Name: name, KeyType: keyType, KeysType: keysType})
+}
+
+var keysSortedTemplate = render.Template("KeysSorted")
+
+func getKeysSortedFunction(name, keyType, keysType string) string {
+	return render.Declarations(keysSortedTemplate, mapData{Name: name, KeyType: keyType, KeysType: keysType})
+}
+
+var invertTemplate = render.Template("Invert")
+
+func getInvertFunction(name, keyType, valueType string) string {
+	return render.Declarations(invertTemplate, mapData{Name: name, KeyType: keyType, ValueType: valueType})
+}
+
+var valuesTemplate = render.Template("Values")
+
+func getValuesFunction(name, valueType, valuesType string) string {
+	return render.Declarations(valuesTemplate, mapData{Name: name, ValueType: valueType, ValuesType: valuesType})
+}
+
+var mapValuesTemplate = render.Template("MapValues")
+
+func getMapValuesFunction(name, keyType, valueType, targetType, targetTypeName string) string {
+	targetMapType := "map[" + keyType + "]" + targetType
+	if targetTypeName == "" {
+		targetMapType = name
+	}
+
+	return render.Declarations(mapValuesTemplate, mapData{Name: name, KeyType: keyType, ValueType: valueType, TargetType: targetType, Suffix: strings.Title(strings.TrimPrefix(targetTypeName, "*")), TargetMapType: targetMapType})
+}
+
+var filterMapEntriesTemplate = render.Template("FilterMapEntries")
+
+func getFilterMapEntriesFunction(name, keyType, valueType string) string {
+	return render.Declarations(filterMapEntriesTemplate, mapData{Name: name, KeyType: keyType, ValueType: valueType})
+}
+
+var mergeTemplate = render.Template("Merge")
+
+func getMergeFunction(name string) string {
+	return render.Declarations(mergeTemplate, mapData{Name: name})
+}
@@ -0,0 +1,104 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitMapType(t *testing.T) {
+	for typeName, expected := range map[string][2]string{
+		"map[string]User":                    {"string", "User"},
+		"map[[2]int][]model.User":            {"[2]int", "[]model.User"},
+		"map[string]map[int]bool":            {"string", "map[int]bool"},
+		"map[string]github.com/user/app/m.T": {"string", "github.com/user/app/m.T"},
+	} {
+		key, value, ok := splitMapType(typeName)
+		if !ok || [2]string{key, value} != expected {
+			t.Error(typeName, key, value)
+		}
+	}
+	for _, typeName := range []string{"[]int", "map[string]", "map[]int", "map[string"} {
+		if _, _, ok := splitMapType(typeName); ok {
+			t.Error(typeName)
+		}
+	}
+}
+
+func TestGenerateMap(t *testing.T) {
+	spec := Spec{Package: "main", Types: map[string]string{"string": "Str", "int": "int"}, Maps: map[string]string{"map[string]time.Time": "timeIndex"}, Methods: []string{"Map"}}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{
+		"\"time\"",
+		"type timeIndex map[string]time.Time",
+		"func (m timeIndex) Keys() StrList {",
+		"func (m timeIndex) Values() []time.Time {",
+		"func (m timeIndex) MapValues(f func(time.Time) time.Time) timeIndex {",
+		"func (m timeIndex) MapValuesStr(f func(time.Time) string) map[string]string {",
+		"func (m timeIndex) MapValuesInt(f func(time.Time) int) map[string]int {",
+		"func (m timeIndex) FilterMap(f func(string, time.Time) bool) timeIndex {",
+		"func (m timeIndex) Merge(other timeIndex) timeIndex {",
+		"func (m timeIndex) KeysSorted(less func(string, string) bool) StrList {",
+		"func (m timeIndex) Invert() map[time.Time]string {",
+		"\"sort\"",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+
+	spec.Declared = []string{"timeIndex"}
+	spec.Prefix = "F"
+	src, err = Generate(spec)
+	if err != nil || strings.Contains(string(src), "type timeIndex") || !strings.Contains(string(src), "func (m timeIndex) FMerge(") {
+		t.Error(err, string(src))
+	}
+
+	if _, err := Generate(Spec{Package: "main", Maps: map[string]string{"[]int": "index"}}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestComparableType(t *testing.T) {
+	for typeName, expected := range map[string]bool{
+		"int":            true,
+		"*User":          true,
+		"[4]string":      true,
+		"time.Time":      true,
+		"[]int":          false,
+		"[2][]int":       false,
+		"map[string]int": false,
+		"func() int":     false,
+	} {
+		if comparableType(typeName) != expected {
+			t.Error(typeName)
+		}
+	}
+
+	src, err := Generate(Spec{Package: "main", Maps: map[string]string{"map[string][]int": "groups"}})
+	if err != nil || strings.Contains(string(src), ") Invert(") || !strings.Contains(string(src), ") KeysSorted(") {
+		t.Error(err, string(src))
+	}
+}
+
+func TestInterfaceType(t *testing.T) {
+	for typeName, expected := range map[string]bool{
+		"error":                  true,
+		"any":                    true,
+		"interface{ Len() int }": true,
+		"interface{}":            true,
+		"int":                    false,
+		"*error":                 false,
+		"[]error":                false,
+	} {
+		if InterfaceType(typeName) != expected {
+			t.Error(typeName)
+		}
+		if safeKey(typeName) != (comparableType(typeName) && !expected) {
+			t.Error(typeName)
+		}
+	}
+}
@@ -0,0 +1,1006 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+var maxWorkersVariableTemplate = render.Template("MaxWorkersVariable")
+
+func getMaxWorkersVariable(listName, typeName string) string {
+	return render.Declarations(maxWorkersVariableTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var mapTemplate = render.Template("Map")
+
+func getMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(mapTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var pMapTemplate = render.Template("PMap")
+
+func getPMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(pMapTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var pMapRateTemplate = render.Template("PMapRate")
+
+func getPMapRateFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(pMapRateTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var pMapTimeoutTemplate = render.Template("PMapTimeout")
+
+func getPMapTimeoutFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(pMapTimeoutTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var pMapRetryTemplate = render.Template("PMapRetry")
+
+func getPMapRetryFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(pMapRetryTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var pFlatMapTemplate = render.Template("PFlatMap")
+
+func getPFlatMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(pFlatMapTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var pGroupByTemplate = render.Template("PGroupBy")
+
+func getPGroupByFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(pGroupByTemplate, groupByData{newTemplateData(listName, typeName, targetType, targetTypeName), getGroupMerge(listName, targetType)})
+}
+
+var groupMergeTemplate = render.Template("GroupMerge")
+
+// groupByData - the data which the templates of PGroupBy are executed with: the data of the method and the merge of
+// its groups (see getGroupMerge)
+type groupByData struct {
+	TemplateData
+	Merge string
+}
+
+// getGroupMerge - get the grouping shared by the PGroupBy methods, which groups the members of l by their keys, in keys,
+// into groups: every chunk of the list is grouped in its own goroutine, and the groups of the chunks are merged in
+// their order, so that the members of every group keep their order
+func getGroupMerge(listName, targetType string) string {
+	return render.Execute(groupMergeTemplate, TemplateData{ListName: listName, TargetType: targetType})
+}
+
+var futureTypeTemplate = render.Template("FutureType")
+
+func getFutureType(listName, typeName string) string {
+	return render.Declarations(futureTypeTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var mapAsyncTemplate = render.Template("MapAsync")
+
+func getMapAsyncFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(mapAsyncTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+func getFilterFunction(listName, typeName, _, _ string) string {
+	return filterFunction(listName, typeName, false)
+}
+
+func getGrownFilterFunction(listName, typeName, _, _ string) string {
+	return filterFunction(listName, typeName, true)
+}
+
+// resultAllocation - get the allocation of the result of Filter and FilterMap: with the capacity of the list, so that
+// it is not reallocated as it grows, or empty with Spec.Grow, using the least memory when few members are kept
+func resultAllocation(listName string, grow bool) string {
+	if grow {
+		return listName + "{}"
+	}
+	return fmt.Sprintf("make(%s, 0, len(l))", listName)
+}
+
+var filterTemplate = render.Template("Filter")
+
+// filterFunction - get Filter, allocating its result with the capacity of the list, or growing it from an empty list
+// with Spec.Grow
+func filterFunction(listName, typeName string, grow bool) string {
+	return render.Declarations(filterTemplate, struct {
+		TemplateData
+		Allocation string
+	}{newTemplateData(listName, typeName, "", ""), resultAllocation(listName, grow)})
+}
+
+var twoPassFilterTemplate = render.Template("TwoPassFilter")
+
+// getTwoPassFilterFunction - get Filter with Spec.TwoPass: a first pass records which members are kept and counts
+// them, and a second pass copies them to a result of the exact size, so that a large list keeping few members does not
+// allocate the capacity of the list, nor a result which grew to twice the members kept
+func getTwoPassFilterFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(twoPassFilterTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var pFilterTemplate = render.Template("PFilter")
+
+func getPFilterFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(pFilterTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var eachTemplate = render.Template("Each")
+
+func getEachFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(eachTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var eachITemplate = render.Template("EachI")
+
+func getEachIFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(eachITemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+// sliceResult - get a result of Take, TakeWhile, Drop and DropWhile, a slice of the list sharing its backing array, or
+// a copy of it with Spec.Copy, which can be appended to without changing the list
+func sliceResult(listName, slice string, copied bool) string {
+	if copied {
+		return fmt.Sprintf("append(%s{}, %s...)", listName, slice)
+	}
+	return slice
+}
+
+// sliceResultComment - get the sentence of the doc comments of Take, TakeWhile, Drop and DropWhile telling whether
+// their result shares the backing array of the list (see sliceResult)
+func sliceResultComment(copied bool) string {
+	if copied {
+		return "The list returned is a copy, which can be changed and appended to without changing the original list"
+	}
+	return "The list returned shares the backing array of the original list, so that appending to it can change the original list"
+}
+
+func getDropWhileFunction(listName, typeName, _, _ string) string {
+	return dropWhileFunction(listName, typeName, false)
+}
+
+func getCopiedDropWhileFunction(listName, typeName, _, _ string) string {
+	return dropWhileFunction(listName, typeName, true)
+}
+
+var dropWhileTemplate = render.Template("DropWhile")
+
+func dropWhileFunction(listName, typeName string, copied bool) string {
+	return render.Declarations(dropWhileTemplate, struct {
+		TemplateData
+		Result  string
+		Comment string
+	}{newTemplateData(listName, typeName, "", ""), sliceResult(listName, "l[i:]", copied), sliceResultComment(copied)})
+}
+
+func getTakeWhileFunction(listName, typeName, _, _ string) string {
+	return takeWhileFunction(listName, typeName, false)
+}
+
+func getCopiedTakeWhileFunction(listName, typeName, _, _ string) string {
+	return takeWhileFunction(listName, typeName, true)
+}
+
+var takeWhileTemplate = render.Template("TakeWhile")
+
+func takeWhileFunction(listName, typeName string, copied bool) string {
+	return render.Declarations(takeWhileTemplate, struct {
+		TemplateData
+		Result  string
+		Whole   string
+		Comment string
+	}{newTemplateData(listName, typeName, "", ""), sliceResult(listName, "l[:i]", copied), sliceResult(listName, "l", copied), sliceResultComment(copied)})
+}
+
+func getTakeFunction(listName, typeName, _, _ string) string {
+	return takeFunction(listName, typeName, false)
+}
+
+func getCopiedTakeFunction(listName, typeName, _, _ string) string {
+	return takeFunction(listName, typeName, true)
+}
+
+var takeTemplate = render.Template("Take")
+
+func takeFunction(listName, typeName string, copied bool) string {
+	return render.Declarations(takeTemplate, struct {
+		TemplateData
+		Result  string
+		Whole   string
+		Comment string
+	}{newTemplateData(listName, typeName, "", ""), sliceResult(listName, "l[:n]", copied), sliceResult(listName, "l", copied), sliceResultComment(copied)})
+}
+
+func getDropFunction(listName, typeName, _, _ string) string {
+	return dropFunction(listName, typeName, false)
+}
+
+func getCopiedDropFunction(listName, typeName, _, _ string) string {
+	return dropFunction(listName, typeName, true)
+}
+
+var dropTemplate = render.Template("Drop")
+
+func dropFunction(listName, typeName string, copied bool) string {
+	return render.Declarations(dropTemplate, struct {
+		TemplateData
+		Result  string
+		Comment string
+	}{newTemplateData(listName, typeName, "", ""), sliceResult(listName, "l[n:]", copied), sliceResultComment(copied)})
+}
+
+var reduceTemplate = render.Template("Reduce")
+
+func getReduceFunction(listName, typename, _, _ string) string {
+	return render.Declarations(reduceTemplate, newTemplateData(listName, typename, "", ""))
+}
+
+var reduceRightTemplate = render.Template("ReduceRight")
+
+func getReduceRightFunction(listName, typename, _, _ string) string {
+	return render.Declarations(reduceRightTemplate, newTemplateData(listName, typename, "", ""))
+}
+
+var pSortTemplate = render.Template("PSort")
+
+func getPSortFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(pSortTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var allTemplate = render.Template("All")
+
+func getAllFunction(listName, typename, _, _ string) string {
+	return render.Declarations(allTemplate, newTemplateData(listName, typename, "", ""))
+}
+
+var anyTemplate = render.Template("Any")
+
+func getAnyFunction(listName, typename, _, _ string) string {
+	return render.Declarations(anyTemplate, newTemplateData(listName, typename, "", ""))
+}
+
+var pAllTemplate = render.Template("PAll")
+
+func getPAllFunction(listName, typename, _, _ string) string {
+	return render.Declarations(pAllTemplate, newTemplateData(listName, typename, "", ""))
+}
+
+var pAnyTemplate = render.Template("PAny")
+
+func getPAnyFunction(listName, typename, _, _ string) string {
+	return render.Declarations(pAnyTemplate, newTemplateData(listName, typename, "", ""))
+}
+
+var pCountTemplate = render.Template("PCount")
+
+func getPCountFunction(listName, typename, _, _ string) string {
+	return render.Declarations(pCountTemplate, newTemplateData(listName, typename, "", ""))
+}
+
+var toChanTemplate = render.Template("ToChan")
+
+func getToChanFunction(listName, typename, _, _ string) string {
+	return render.Declarations(toChanTemplate, newTemplateData(listName, typename, "", ""))
+}
+
+var fromChanTemplate = render.Template("FromChan")
+
+func getFromChanFunction(listName, typename, _, _ string) string {
+	return render.Declarations(fromChanTemplate, newTemplateData(listName, typename, "", ""))
+}
+
+var mapChanTemplate = render.Template("MapChan")
+
+func getMapChanFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(mapChanTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var filterChanTemplate = render.Template("FilterChan")
+
+func getFilterChanFunction(listName, typename, _, _ string) string {
+	return render.Declarations(filterChanTemplate, newTemplateData(listName, typename, "", ""))
+}
+
+var pipelineTypeTemplate = render.Template("PipelineType")
+
+func getPipelineType(listName, typeName string) string {
+	return render.Declarations(pipelineTypeTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var pipelineMapTemplate = render.Template("PipelineMap")
+
+func getPipelineMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(pipelineMapTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+func getFilterMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return filterMapFunction(listName, typeName, targetType, targetTypeName, false)
+}
+
+func getGrownFilterMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return filterMapFunction(listName, typeName, targetType, targetTypeName, true)
+}
+
+var filterMapTemplate = render.Template("FilterMap")
+
+// filterMapFunction - get FilterMap, allocating its result like Filter (see resultAllocation)
+func filterMapFunction(listName, typeName, targetType, targetTypeName string, grow bool) string {
+	if targetTypeName == "" {
+		//there's no need for a FilterMap function for the same time as the filter function suffices
+		return ""
+	}
+	data := newTemplateData(listName, typeName, targetType, targetTypeName)
+	return render.Declarations(filterMapTemplate, struct {
+		TemplateData
+		Allocation string
+	}{data, resultAllocation(data.TargetListName, grow)})
+}
+
+var pFilterMapTemplate = render.Template("PFilterMap")
+
+func getPFilterMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	if targetTypeName == "" {
+		//there's no need for a PFilterMap function for the same time as the pfilter function suffices
+		return ""
+	}
+	return render.Declarations(pFilterMapTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var chunkedLoopTemplate = render.Template("ChunkedLoop")
+
+// getChunkedLoop - get the loop shared by the chunked parallel methods. The list is split into runtime.NumCPU() chunks (or at most <listName>MaxWorkers chunks) and every chunk is processed in its own goroutine. setup is placed before the loop and may use n, the number of chunks; body is executed for every member and may use c (the index of the chunk), i and t. body may return to abandon the rest of its chunk.
+func getChunkedLoop(listName, setup, body string) string {
+	return render.Execute(chunkedLoopTemplate, struct {
+		TemplateData
+		Setup string
+		Body  string
+	}{TemplateData{ListName: listName}, strings.TrimSpace(setup), strings.TrimSpace(body)})
+}
+
+var chunkedPMapTemplate = render.Template("ChunkedPMap")
+
+func getChunkedPMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	data := newTemplateData(listName, typeName, targetType, targetTypeName)
+	loop := getChunkedLoop(listName, fmt.Sprintf(`l2 := make(%[1]s, len(l))`, data.TargetListName), `l2[i] = f(t)`)
+
+	return render.Declarations(chunkedPMapTemplate, struct {
+		TemplateData
+		Loop string
+	}{data, loop})
+}
+
+var chunkedPFilterTemplate = render.Template("ChunkedPFilter")
+
+func getChunkedPFilterFunction(listName, typeName, _, _ string) string {
+	loop := getChunkedLoop(listName, fmt.Sprintf(`parts := make([]%[1]s, n)`, listName), `
+                        if f(t) {
+                            parts[c] = append(parts[c], t)
+                        }`)
+
+	return render.Declarations(chunkedPFilterTemplate, struct {
+		TemplateData
+		Loop string
+	}{newTemplateData(listName, typeName, "", ""), loop})
+}
+
+var chunkedPAllTemplate = render.Template("ChunkedPAll")
+
+func getChunkedPAllFunction(listName, typeName, _, _ string) string {
+	loop := getChunkedLoop(listName, `
+            done := make(chan struct{})
+            once := sync.Once{}`, `
+                        select {
+                        case <-done:
+                            return
+                        default:
+                        }
+                        if !f(t) {
+                            once.Do(func() { close(done) })
+                            return
+                        }`)
+
+	return render.Declarations(chunkedPAllTemplate, struct {
+		TemplateData
+		Loop string
+	}{newTemplateData(listName, typeName, "", ""), loop})
+}
+
+var chunkedPAnyTemplate = render.Template("ChunkedPAny")
+
+func getChunkedPAnyFunction(listName, typeName, _, _ string) string {
+	loop := getChunkedLoop(listName, `
+            done := make(chan struct{})
+            once := sync.Once{}`, `
+                        select {
+                        case <-done:
+                            return
+                        default:
+                        }
+                        if f(t) {
+                            once.Do(func() { close(done) })
+                            return
+                        }`)
+
+	return render.Declarations(chunkedPAnyTemplate, struct {
+		TemplateData
+		Loop string
+	}{newTemplateData(listName, typeName, "", ""), loop})
+}
+
+var chunkedPFilterMapTemplate = render.Template("ChunkedPFilterMap")
+
+func getChunkedPFilterMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	if targetTypeName == "" {
+		//there's no need for a PFilterMap function for the same time as the pfilter function suffices
+		return ""
+	}
+	data := newTemplateData(listName, typeName, targetType, targetTypeName)
+	loop := getChunkedLoop(listName, fmt.Sprintf(`parts := make([]%[1]s, n)`, data.TargetListName), `
+                        pass := true
+                        for _, f := range fFilters {
+                            if !f(t) {
+                                pass = false
+                                break
+                            }
+                        }
+                        if pass {
+                            parts[c] = append(parts[c], fMap(t))
+                        }`)
+
+	return render.Declarations(chunkedPFilterMapTemplate, struct {
+		TemplateData
+		Loop string
+	}{data, loop})
+}
+
+var chunkedPFlatMapTemplate = render.Template("ChunkedPFlatMap")
+
+func getChunkedPFlatMapFunction(listName, typeName, targetType, targetTypeName string) string {
+
+	loop := getChunkedLoop(listName, fmt.Sprintf(`parts := make([][]%[1]s, len(l))`, targetType), `parts[i] = f(t)`)
+
+	return render.Declarations(chunkedPFlatMapTemplate, struct {
+		TemplateData
+		Loop string
+	}{newTemplateData(listName, typeName, targetType, targetTypeName), loop})
+}
+
+var chunkedPGroupByTemplate = render.Template("ChunkedPGroupBy")
+
+func getChunkedPGroupByFunction(listName, typeName, targetType, targetTypeName string) string {
+
+	loop := getChunkedLoop(listName, fmt.Sprintf(`keys := make([]%[1]s, len(l))`, targetType), `keys[i] = f(t)`)
+
+	return render.Declarations(chunkedPGroupByTemplate, struct {
+		groupByData
+		Loop string
+	}{groupByData{newTemplateData(listName, typeName, targetType, targetTypeName), getGroupMerge(listName, targetType)}, loop})
+}
+
+var chunkedPCountTemplate = render.Template("ChunkedPCount")
+
+func getChunkedPCountFunction(listName, typeName, _, _ string) string {
+	loop := getChunkedLoop(listName, `var count int64`, `
+                        if f(t) {
+                            atomic.AddInt64(&count, 1)
+                        }`)
+
+	return render.Declarations(chunkedPCountTemplate, struct {
+		TemplateData
+		Loop string
+	}{newTemplateData(listName, typeName, "", ""), loop})
+}
+
+var poolTypeTemplate = render.Template("PoolType")
+
+func getPoolType(listName, typeName string) string {
+	return render.Declarations(poolTypeTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var pooledPMapTemplate = render.Template("PooledPMap")
+
+func getPooledPMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(pooledPMapTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var pooledPFlatMapTemplate = render.Template("PooledPFlatMap")
+
+func getPooledPFlatMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(pooledPFlatMapTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var pooledPGroupByTemplate = render.Template("PooledPGroupBy")
+
+func getPooledPGroupByFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(pooledPGroupByTemplate, groupByData{newTemplateData(listName, typeName, targetType, targetTypeName), getGroupMerge(listName, targetType)})
+}
+
+var pooledPFilterTemplate = render.Template("PooledPFilter")
+
+func getPooledPFilterFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(pooledPFilterTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var pooledPAllTemplate = render.Template("PooledPAll")
+
+func getPooledPAllFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(pooledPAllTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var pooledPAnyTemplate = render.Template("PooledPAny")
+
+func getPooledPAnyFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(pooledPAnyTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var pooledPCountTemplate = render.Template("PooledPCount")
+
+func getPooledPCountFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(pooledPCountTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+// setName - get the name of the set type of a list, eg: 'userSet' for 'userList'
+func setName(listName string) string {
+	return strings.TrimSuffix(listName, "List") + "Set"
+}
+
+var toSetTemplate = render.Template("ToSet")
+
+func getToSetFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(toSetTemplate, struct {
+		TemplateData
+		SetName string
+	}{newTemplateData(listName, typeName, "", ""), setName(listName)})
+}
+
+var eqToSetTemplate = render.Template("EqToSet")
+
+func getEqToSetFunction(listName, typeName, eq, hash string) string {
+	return render.Declarations(eqToSetTemplate, struct {
+		TemplateData
+		SetName string
+		Eq      string
+		Hash    string
+	}{newTemplateData(listName, typeName, "", ""), setName(listName), eq, hash})
+}
+
+// stackName, queueName and dequeName - get the names of the stack, the queue and the deque types of a list, eg:
+// 'userStack' for 'userList'
+func stackName(listName string) string {
+	return strings.TrimSuffix(listName, "List") + "Stack"
+}
+
+func queueName(listName string) string {
+	return strings.TrimSuffix(listName, "List") + "Queue"
+}
+
+func dequeName(listName string) string {
+	return strings.TrimSuffix(listName, "List") + "Deque"
+}
+
+var toStackTemplate = render.Template("ToStack")
+
+func getToStackFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(toStackTemplate, struct {
+		TemplateData
+		StackName string
+	}{newTemplateData(listName, typeName, "", ""), stackName(listName)})
+}
+
+var toQueueTemplate = render.Template("ToQueue")
+
+func getToQueueFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(toQueueTemplate, struct {
+		TemplateData
+		QueueName string
+	}{newTemplateData(listName, typeName, "", ""), queueName(listName)})
+}
+
+var toDequeTemplate = render.Template("ToDeque")
+
+func getToDequeFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(toDequeTemplate, struct {
+		TemplateData
+		DequeName string
+	}{newTemplateData(listName, typeName, "", ""), dequeName(listName)})
+}
+
+// immutableName - get the name of the immutable list type of a list, eg: 'userImmutableList' for 'userList'
+func immutableName(listName string) string {
+	return strings.TrimSuffix(listName, "List") + "ImmutableList"
+}
+
+var toImmutableTemplate = render.Template("ToImmutable")
+
+func getToImmutableFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(toImmutableTemplate, struct {
+		TemplateData
+		ImmutableName string
+	}{newTemplateData(listName, typeName, "", ""), immutableName(listName)})
+}
+
+// sortedName - get the name of the sorted list type of a list, eg: 'sortedUserList' for 'userList' and
+// 'SortedUserList' for 'UserList'
+func sortedName(listName string) string {
+	if ast.IsExported(listName) {
+		return "Sorted" + listName
+	}
+	return "sorted" + strings.Title(listName)
+}
+
+var toSortedTemplate = render.Template("ToSorted")
+
+func getToSortedFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(toSortedTemplate, struct {
+		TemplateData
+		SortedName string
+	}{newTemplateData(listName, typeName, "", ""), sortedName(listName)})
+}
+
+// optionName - get the name of the option type of a list, eg: 'userOption' for 'userList', and the names of the
+// functions creating an option with a member or without, eg: 'userSome' and 'userNone'
+func optionName(listName string) string {
+	return strings.TrimSuffix(listName, "List") + "Option"
+}
+
+var optionTypeTemplate = render.Template("OptionType")
+
+func getOptionType(listName, typeName string) string {
+	return render.Declarations(optionTypeTemplate, struct {
+		TemplateData
+		OptionName string
+		Name       string
+	}{newTemplateData(listName, typeName, "", ""), optionName(listName), strings.TrimSuffix(listName, "List")})
+}
+
+var findTemplate = render.Template("Find")
+
+func getFindFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(findTemplate, struct {
+		TemplateData
+		OptionName string
+		Name       string
+	}{newTemplateData(listName, typeName, "", ""), optionName(listName), strings.TrimSuffix(listName, "List")})
+}
+
+var firstTemplate = render.Template("First")
+
+func getFirstFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(firstTemplate, struct {
+		TemplateData
+		OptionName string
+		Name       string
+	}{newTemplateData(listName, typeName, "", ""), optionName(listName), strings.TrimSuffix(listName, "List")})
+}
+
+var lastTemplate = render.Template("Last")
+
+func getLastFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(lastTemplate, struct {
+		TemplateData
+		OptionName string
+		Name       string
+	}{newTemplateData(listName, typeName, "", ""), optionName(listName), strings.TrimSuffix(listName, "List")})
+}
+
+// resultName - get the name of the result type of a list, eg: 'userResult' for 'userList', and the names of the
+// functions creating a result with a member or with an error, eg: 'userOk' and 'userErr'
+func resultName(listName string) string {
+	return strings.TrimSuffix(listName, "List") + "Result"
+}
+
+var resultTypeTemplate = render.Template("ResultType")
+
+func getResultType(listName, typeName string) string {
+	return render.Declarations(resultTypeTemplate, struct {
+		TemplateData
+		ResultName string
+		Name       string
+	}{newTemplateData(listName, typeName, "", ""), resultName(listName), strings.TrimSuffix(listName, "List")})
+}
+
+var mapResultTemplate = render.Template("MapResult")
+
+func getMapResultFunction(listName, typeName, targetType, targetTypeName string) string {
+	data := newTemplateData(listName, typeName, targetType, targetTypeName)
+	return render.Declarations(mapResultTemplate, struct {
+		TemplateData
+		ResultName string
+	}{data, resultName(data.TargetListName)})
+}
+
+// iterName - get the name of the iterator type of a list, eg: 'userIter' for 'userList'
+func iterName(listName string) string {
+	return strings.TrimSuffix(listName, "List") + "Iter"
+}
+
+var iterTypeTemplate = render.Template("IterType")
+
+func getIterType(listName, typeName string) string {
+	return render.Declarations(iterTypeTemplate, struct {
+		TemplateData
+		IterName string
+	}{newTemplateData(listName, typeName, "", ""), iterName(listName)})
+}
+
+var iterMapTemplate = render.Template("IterMap")
+
+func getIterMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	data := newTemplateData(listName, typeName, targetType, targetTypeName)
+	return render.Declarations(iterMapTemplate, struct {
+		TemplateData
+		IterName       string
+		TargetIterName string
+	}{data, iterName(listName), iterName(data.TargetListName)})
+}
+
+var valuesSeqTemplate = render.Template("ValuesSeq")
+
+func getValuesSeqFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(valuesSeqTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var enumeratedTemplate = render.Template("Enumerated")
+
+func getEnumeratedFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(enumeratedTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var fromSeqTemplate = render.Template("FromSeq")
+
+func getFromSeqFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(fromSeqTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var flattenTemplate = render.Template("Flatten")
+
+func getFlattenFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(flattenTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var flatMapTemplate = render.Template("FlatMap")
+
+func getFlatMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(flatMapTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var containsTemplate = render.Template("Contains")
+
+func getContainsFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(containsTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var derefContainsTemplate = render.Template("DerefContains")
+
+func getDerefContainsFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(derefContainsTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var uniqueTemplate = render.Template("Unique")
+
+func getUniqueFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(uniqueTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var derefUniqueTemplate = render.Template("DerefUnique")
+
+func getDerefUniqueFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(derefUniqueTemplate, struct {
+		TemplateData
+		ValueType string
+	}{newTemplateData(listName, typeName, "", ""), strings.TrimPrefix(typeName, "*")})
+}
+
+var eqContainsTemplate = render.Template("EqContains")
+
+func getEqContainsFunction(listName, typeName, eq, _ string) string {
+	return render.Declarations(eqContainsTemplate, struct {
+		TemplateData
+		Eq string
+	}{newTemplateData(listName, typeName, "", ""), eq})
+}
+
+var eqUniqueTemplate = render.Template("EqUnique")
+
+func getEqUniqueFunction(listName, typeName, eq, hash string) string {
+	return render.Declarations(eqUniqueTemplate, struct {
+		TemplateData
+		Eq   string
+		Hash string
+	}{newTemplateData(listName, typeName, "", ""), eq, hash})
+}
+
+var uniqueByTemplate = render.Template("UniqueBy")
+
+func getUniqueByFunction(listName, typeName, targetType, targetTypeName string) string {
+	return render.Declarations(uniqueByTemplate, newTemplateData(listName, typeName, targetType, targetTypeName))
+}
+
+var sortByTemplate = render.Template("SortBy")
+
+func getSortByFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(sortByTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var filterInPlaceTemplate = render.Template("FilterInPlace")
+
+func getFilterInPlaceFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(filterInPlaceTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var mapInPlaceTemplate = render.Template("MapInPlace")
+
+func getMapInPlaceFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(mapInPlaceTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var reverseInPlaceTemplate = render.Template("ReverseInPlace")
+
+func getReverseInPlaceFunction(listName, _, _, _ string) string {
+	return render.Declarations(reverseInPlaceTemplate, TemplateData{ListName: listName})
+}
+
+var sortInPlaceTemplate = render.Template("SortInPlace")
+
+func getSortInPlaceFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(sortInPlaceTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var sampleWeightedTemplate = render.Template("SampleWeighted")
+
+func getSampleWeightedFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(sampleWeightedTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var compactNilTemplate = render.Template("CompactNil")
+
+func getCompactNilFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(compactNilTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var derefOrTemplate = render.Template("DerefOr")
+
+func getDerefOrFunction(listName, typeName, valueType, valueTypeName string) string {
+	valueListName := "[]" + valueType
+	if valueTypeName != "" {
+		valueListName = strings.TrimPrefix(valueTypeName, "*") + "List"
+	}
+
+	return render.Declarations(derefOrTemplate, struct {
+		TemplateData
+		ValueType     string
+		ValueListName string
+	}{newTemplateData(listName, typeName, "", ""), valueType, valueListName})
+}
+
+// pluckPrefix - the prefix of the methods getting a field of the members of a list, eg: 'PluckName'
+const pluckPrefix = "Pluck"
+
+// getPluckFunction - get the method getting a field of the members of a list of structs in the list of the type of the
+// field. The nil members of a list of pointers get the zero value of the field
+var pluckTemplate = render.Template("Pluck")
+
+func getPluckFunction(listName, typeName string, field Field, fieldListName string) string {
+	return render.Declarations(pluckTemplate, struct {
+		TemplateData
+		FieldListName string
+		FieldName     string
+		FieldType     string
+		Pointer       bool
+	}{newTemplateData(listName, typeName, "", ""), fieldListName, field.Name, field.Type, strings.HasPrefix(typeName, "*")})
+}
+
+var sumTemplate = render.Template("Sum")
+
+func getSumFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(sumTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var averageTemplate = render.Template("Average")
+
+func getAverageFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(averageTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var extremeTemplate = render.Template("Extreme")
+
+// getExtremeFunction - get the method returning the least (Min, with '<') or the greatest (Max, with '>') member
+func getExtremeFunction(method, description, operator, listName, typeName string) string {
+	return render.Declarations(extremeTemplate, struct {
+		TemplateData
+		Method      string
+		Description string
+		Operator    string
+	}{newTemplateData(listName, typeName, "", ""), method, description, operator})
+}
+
+func getMinFunction(listName, typeName, _, _ string) string {
+	return getExtremeFunction("Min", "least", "<", listName, typeName)
+}
+
+func getMaxFunction(listName, typeName, _, _ string) string {
+	return getExtremeFunction("Max", "greatest", ">", listName, typeName)
+}
+
+var sortTemplate = render.Template("Sort")
+
+func getSortFunction(listName, typeName, _, _ string) string {
+	return render.Declarations(sortTemplate, newTemplateData(listName, typeName, "", ""))
+}
+
+var stringMapTemplate = render.Template("StringMap")
+
+// getStringMapFunction - get TrimSpaceAll, ToLowerAll or ToUpperAll, applying a function of the strings package to
+// every member, converted to a string and back for the named string types
+func getStringMapFunction(method, function, description, listName, typeName string) string {
+	value := "strings." + function + "(t)"
+	if typeName != "string" {
+		value = fmt.Sprintf("%s(strings.%s(string(t)))", typeName, function)
+	}
+	return render.Declarations(stringMapTemplate, struct {
+		TemplateData
+		Method      string
+		Description string
+		Value       string
+	}{newTemplateData(listName, typeName, "", ""), method, description, value})
+}
+
+func getTrimSpaceAllFunction(listName, typeName, _, _ string) string {
+	return getStringMapFunction("TrimSpaceAll", "TrimSpace", "without its leading and trailing white space", listName, typeName)
+}
+
+func getToLowerAllFunction(listName, typeName, _, _ string) string {
+	return getStringMapFunction("ToLowerAll", "ToLower", "in lower case", listName, typeName)
+}
+
+func getToUpperAllFunction(listName, typeName, _, _ string) string {
+	return getStringMapFunction("ToUpperAll", "ToUpper", "in upper case", listName, typeName)
+}
+
+var nonEmptyTemplate = render.Template("NonEmpty")
+
+func getNonEmptyFunction(listName, _, _, _ string) string {
+	return render.Declarations(nonEmptyTemplate, TemplateData{ListName: listName})
+}
+
+var joinNonEmptyTemplate = render.Template("JoinNonEmpty")
+
+func getJoinNonEmptyFunction(listName, typeName, _, _ string) string {
+	member := "t"
+	if typeName != "string" {
+		member = "string(t)"
+	}
+	return render.Declarations(joinNonEmptyTemplate, struct {
+		TemplateData
+		Member string
+	}{newTemplateData(listName, typeName, "", ""), member})
+}
+
+// pairName - get the name of the pair type of the members of a list with the members of a target list, eg:
+// 'stringIntPair' for 'stringList' and 'intList'. Its list is named with a 'List' suffix, eg: 'stringIntPairList'
+func pairName(listName, targetListName string) string {
+	return strings.TrimSuffix(listName, "List") + strings.Title(strings.TrimSuffix(targetListName, "List")) + "Pair"
+}
+
+// getPairType - get the pair type of the members of a list with the members of a target list, with the First and
+// Second fields, and its list, which has the Firsts and Seconds methods, for the methods building pairs, like Zip
+var pairTypeTemplate = render.Template("PairType")
+
+func getPairType(listName, typeName, targetListName, targetType string) string {
+	return render.Declarations(pairTypeTemplate, struct {
+		TemplateData
+		PairName string
+	}{TemplateData{ListName: listName, TypeName: typeName, TargetType: targetType, TargetListName: targetListName}, pairName(listName, targetListName)})
+}
+
+var zipTemplate = render.Template("Zip")
+
+func getZipFunction(listName, typeName, targetType, targetTypeName string) string {
+	data := newTemplateData(listName, typeName, targetType, targetTypeName)
+	return render.Declarations(zipTemplate, struct {
+		TemplateData
+		PairName string
+	}{data, pairName(listName, data.TargetListName)})
+}
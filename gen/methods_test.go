@@ -0,0 +1,219 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+// TestMethodGoldenFiles - compare the code generated for every method with its testdata/<method>.golden file, which
+// 'go test -update' writes, so that the changes of the templates of the methods are reviewed as diffs
+func TestMethodGoldenFiles(t *testing.T) {
+	methods := []struct {
+		name string
+		src  string
+	}{
+		{"Filter", getFilterFunction("stringList", "string", "", "")},
+		{"PFilter", getPFilterFunction("stringList", "string", "", "")},
+		{"Each", getEachFunction("stringList", "string", "", "")},
+		{"EachI", getEachIFunction("stringList", "string", "", "")},
+		{"DropWhile", getDropWhileFunction("stringList", "string", "", "")},
+		{"TakeWhile", getTakeWhileFunction("stringList", "string", "", "")},
+		{"Take", getTakeFunction("stringList", "string", "", "")},
+		{"Drop", getDropFunction("stringList", "string", "", "")},
+		{"Reduce", getReduceFunction("stringList", "string", "", "")},
+		{"ReduceRight", getReduceRightFunction("stringList", "string", "", "")},
+		{"Map", getMapFunction("stringList", "string", "string", "")},
+		{"MapInt", getMapFunction("stringList", "string", "int", "int")},
+		{"MapI", getMapFunction("stringList", "string", "int", "I")},
+		{"PMap", getPMapFunction("stringList", "string", "string", "")},
+		{"PMapInt", getPMapFunction("stringList", "string", "int", "int")},
+		{"PMapI", getPMapFunction("stringList", "string", "int", "I")},
+		{"All", getAllFunction("stringList", "string", "", "")},
+		{"Any", getAnyFunction("stringList", "string", "", "")},
+		{"PAll", getPAllFunction("stringList", "string", "", "")},
+		{"PAny", getPAnyFunction("stringList", "string", "", "")},
+		{"FilterMap", getFilterMapFunction("stringList", "string", "int", "int")},
+		{"PFilterMap", getPFilterMapFunction("stringList", "string", "int", "int")},
+		{"ChunkedPMap", getChunkedPMapFunction("stringList", "string", "string", "")},
+		{"ChunkedPFilter", getChunkedPFilterFunction("stringList", "string", "", "")},
+		{"ChunkedPAll", getChunkedPAllFunction("stringList", "string", "", "")},
+		{"ChunkedPAny", getChunkedPAnyFunction("stringList", "string", "", "")},
+		{"ChunkedPFilterMap", getChunkedPFilterMapFunction("stringList", "string", "int", "int")},
+		{"PMapRate", getPMapRateFunction("stringList", "string", "int", "int")},
+		{"PMapTimeout", getPMapTimeoutFunction("stringList", "string", "int", "int")},
+		{"PFlatMap", getPFlatMapFunction("stringList", "string", "int", "int")},
+		{"ChunkedPFlatMap", getChunkedPFlatMapFunction("stringList", "string", "int", "int")},
+		{"PGroupBy", getPGroupByFunction("stringList", "string", "int", "int")},
+		{"ChunkedPGroupBy", getChunkedPGroupByFunction("stringList", "string", "int", "int")},
+		{"PSort", getPSortFunction("stringList", "string", "", "")},
+		{"PCount", getPCountFunction("stringList", "string", "", "")},
+		{"ChunkedPCount", getChunkedPCountFunction("stringList", "string", "", "")},
+		{"ToChan", getToChanFunction("stringList", "string", "", "")},
+		{"FromChan", getFromChanFunction("stringList", "string", "", "")},
+		{"MapChan", getMapChanFunction("stringList", "string", "int", "int")},
+		{"FilterChan", getFilterChanFunction("stringList", "string", "", "")},
+		{"MapAsync", getMapAsyncFunction("stringList", "string", "int", "int")},
+		{"FutureType", getFutureType("stringList", "string")},
+		{"MaxWorkersVariable", getMaxWorkersVariable("stringList", "string")},
+		{"PMapRetry", getPMapRetryFunction("stringList", "string", "int", "int")},
+		{"PipelineType", getPipelineType("stringList", "string")},
+		{"PipelineMap", getPipelineMapFunction("stringList", "string", "int", "int")},
+		{"PooledPMap", getPooledPMapFunction("stringList", "string", "int", "int")},
+		{"PooledPFlatMap", getPooledPFlatMapFunction("stringList", "string", "int", "int")},
+		{"PooledPGroupBy", getPooledPGroupByFunction("stringList", "string", "int", "int")},
+		{"PooledPFilter", getPooledPFilterFunction("stringList", "string", "", "")},
+		{"PooledPAll", getPooledPAllFunction("stringList", "string", "", "")},
+		{"PooledPAny", getPooledPAnyFunction("stringList", "string", "", "")},
+		{"PooledPCount", getPooledPCountFunction("stringList", "string", "", "")},
+		{"PoolType", getPoolType("stringList", "string")},
+		{"ListType", render.Declarations(listTypeTemplate, newTemplateData("stringList", "string", "", ""))},
+		{"GroupMerge", "func f() {\n" + getGroupMerge("stringList", "int") + "\n}"},
+		{"ChunkedLoop", "func f() {\n" + getChunkedLoop("stringList", "l2 := make(stringList, len(l))", "l2[i] = t") + "\n}"},
+		{"TwoPassFilter", getTwoPassFilterFunction("stringList", "string", "", "")},
+		{"Average", getAverageFunction("intList", "int", "", "")},
+		{"Sum", getSumFunction("intList", "int", "", "")},
+		{"Extreme", getMinFunction("intList", "int", "", "")},
+		{"Sort", getSortFunction("stringList", "string", "", "")},
+		{"SortBy", getSortByFunction("stringList", "string", "", "")},
+		{"SortInPlace", getSortInPlaceFunction("stringList", "string", "", "")},
+		{"ToSorted", getToSortedFunction("stringList", "string", "", "")},
+		{"StringMap", getTrimSpaceAllFunction("stringList", "string", "", "")},
+		{"JoinNonEmpty", getJoinNonEmptyFunction("stringList", "string", "", "")},
+		{"NonEmpty", getNonEmptyFunction("stringList", "string", "", "")},
+		{"Contains", getContainsFunction("stringList", "string", "", "")},
+		{"Unique", getUniqueFunction("stringList", "string", "", "")},
+		{"UniqueBy", getUniqueByFunction("stringList", "string", "int", "int")},
+		{"ToSet", getToSetFunction("stringList", "string", "", "")},
+		{"EqContains", getEqContainsFunction("pointList", "point", "pointEq", "")},
+		{"EqUnique", getEqUniqueFunction("pointList", "point", "pointEq", "pointHash")},
+		{"EqToSet", getEqToSetFunction("pointList", "point", "pointEq", "pointHash")},
+		{"DerefContains", getDerefContainsFunction("pointList", "*point", "", "")},
+		{"DerefUnique", getDerefUniqueFunction("pointList", "*point", "", "")},
+		{"DerefOr", getDerefOrFunction("pointList", "*point", "point", "point")},
+		{"CompactNil", getCompactNilFunction("pointList", "*point", "", "")},
+		{"Find", getFindFunction("stringList", "string", "", "")},
+		{"First", getFirstFunction("stringList", "string", "", "")},
+		{"Last", getLastFunction("stringList", "string", "", "")},
+		{"OptionType", getOptionType("stringList", "string")},
+		{"ResultType", getResultType("stringList", "string")},
+		{"MapResult", getMapResultFunction("stringList", "string", "int", "int")},
+		{"FlatMap", getFlatMapFunction("stringList", "string", "int", "int")},
+		{"Flatten", getFlattenFunction("stringList", "string", "", "")},
+		{"FilterInPlace", getFilterInPlaceFunction("stringList", "string", "", "")},
+		{"MapInPlace", getMapInPlaceFunction("stringList", "string", "", "")},
+		{"ReverseInPlace", getReverseInPlaceFunction("stringList", "string", "", "")},
+		{"SampleWeighted", getSampleWeightedFunction("stringList", "string", "", "")},
+		{"Enumerated", getEnumeratedFunction("stringList", "string", "", "")},
+		{"FromSeq", getFromSeqFunction("stringList", "string", "", "")},
+		{"ValuesSeq", getValuesSeqFunction("stringList", "string", "", "")},
+		{"IterType", getIterType("stringList", "string")},
+		{"IterMap", getIterMapFunction("stringList", "string", "int", "int")},
+		{"PairType", getPairType("stringList", "string", "intList", "int")},
+		{"Zip", getZipFunction("stringList", "string", "int", "int")},
+		{"Pluck", getPluckFunction("pointList", "point", Field{"X", "int"}, "intList")},
+		{"ToStack", getToStackFunction("stringList", "string", "", "")},
+		{"ToQueue", getToQueueFunction("stringList", "string", "", "")},
+		{"ToDeque", getToDequeFunction("stringList", "string", "", "")},
+		{"ToImmutable", getToImmutableFunction("stringList", "string", "", "")},
+		{"GenericMethod", genericMethod(getMapFunction, "return Map(l, f)")("stringList", "string", "string", "")},
+		{"GenericFilter", genericFilterFunction(false)},
+		{"GenericFilterMap", genericFilterMapFunction(false)},
+		{"GenericTake", genericTakeFunction(false)},
+		{"GenericTakeWhile", genericTakeWhileFunction(false)},
+		{"GenericDrop", genericDropFunction(false)},
+		{"GenericDropWhile", genericDropWhileFunction(false)},
+		{"ArrayType", render.Declarations(arrayTypeTemplate, struct {
+			TemplateData
+			Length    string
+			ArrayType string
+		}{newTemplateData("intArray3", "int", "", ""), "3", "[3]int"})},
+		{"ArrayEach", getArrayEachFunction("intArray3", "int")},
+		{"ArrayEachI", getArrayEachIFunction("intArray3", "int")},
+		{"ArrayMap", getArrayMapFunction("intArray3", "int", "string", "string")},
+		{"ArrayReduce", getArrayReduceFunction("intArray3", "int")},
+		{"ArrayReduceRight", getArrayReduceRightFunction("intArray3", "int")},
+		{"ArrayToList", getArrayToListFunction("intArray3", "int", "intList")},
+		{"MapType", render.Declarations(mapTypeTemplate, mapData{Name: "stringIntMap", MapType: "map[string]int", KeyType: "string", ValueType: "int"})},
+		{"Keys", getKeysFunction("stringIntMap", "string", "stringList")},
+		{"KeysSorted", getKeysSortedFunction("stringIntMap", "string", "stringList")},
+		{"Values", getValuesFunction("stringIntMap", "int", "intList")},
+		{"Invert", getInvertFunction("stringIntMap", "string", "int")},
+		{"MapValues", getMapValuesFunction("stringIntMap", "string", "int", "string", "string")},
+		{"FilterMapEntries", getFilterMapEntriesFunction("stringIntMap", "string", "int")},
+		{"Merge", getMergeFunction("stringIntMap")},
+		{"ConcurrentMap", getConcurrentMapType("stringIntMap", "string", "int", "stringList", "intList")},
+	}
+	for _, method := range methods {
+		checkGolden(t, method.name, method.src)
+	}
+	safeType, err := safeWrapper("", "stringList", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "SafeType", safeType)
+	safeMethods, err := safeWrapper(getTakeFunction("stringList", "string", "", "")+getFilterInPlaceFunction("stringList", "string", "", ""), "stringList", nil, map[string]bool{"FilterInPlace": true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "SafeMethod", strings.TrimPrefix(safeMethods, safeType))
+}
+
+// TestTemplateGoldenFiles - check that every template of internal/render/templates has a testdata/<template>.golden
+// file, compared with the code it generates by TestMethodGoldenFiles
+func TestTemplateGoldenFiles(t *testing.T) {
+	templates, err := filepath.Glob(filepath.Join("..", "internal", "render", "templates", "*.tmpl"))
+	if err != nil || len(templates) == 0 {
+		t.Fatal(templates, err)
+	}
+	for _, template := range templates {
+		name := strings.TrimSuffix(filepath.Base(template), ".tmpl")
+		if _, err := os.Stat(filepath.Join("testdata", name+".golden")); err != nil {
+			t.Errorf("%s: the template has no golden file: %s", name, err)
+		}
+	}
+}
+
+func TestGrownFilterGeneration(t *testing.T) {
+	for _, result := range []string{getGrownFilterFunction("stringList", "string", "", ""), getGrownFilterMapFunction("stringList", "string", "int", "int"), genericGrownFilter} {
+		if strings.Contains(result, "make(") || !strings.Contains(result, "List{}") && !strings.Contains(result, "l2 := []T{}") {
+			t.Error(result)
+		}
+	}
+	if !strings.Contains(genericFilter, "l2 := make([]T, 0, len(l))") || !strings.Contains(genericFilterMap, "l2 := make([]U, 0, len(l))") {
+		t.Fail()
+	}
+}
+
+func TestTwoPassFilterGeneration(t *testing.T) {
+	for _, result := range []string{getTwoPassFilterFunction("stringList", "string", "", ""), genericTwoPassFilter} {
+		if !strings.Contains(result, "keep := make([]bool, len(l))") || !strings.Contains(result, ", 0, n)") || strings.Contains(result, "len(l))\n            for") {
+			t.Error(result)
+		}
+	}
+}
+
+func TestCopiedTakeDropGeneration(t *testing.T) {
+	results := []string{
+		getCopiedTakeFunction("stringList", "string", "", ""),
+		getCopiedTakeWhileFunction("stringList", "string", "", ""),
+		getCopiedDropFunction("stringList", "string", "", ""),
+		getCopiedDropWhileFunction("stringList", "string", "", ""),
+	}
+	for _, result := range results {
+		if !strings.Contains(result, "return append(stringList{}, l") || strings.Contains(result, "return l\n") || strings.Contains(result, "return l[") {
+			t.Error(result)
+		}
+	}
+	for _, result := range []string{genericCopiedTake, genericCopiedTakeWhile, genericCopiedDrop, genericCopiedDropWhile} {
+		if !strings.Contains(result, "return append([]T{}, l") {
+			t.Error(result)
+		}
+	}
+	if !strings.Contains(genericTake, "return l[:n]") || !strings.Contains(genericDropWhile, "return l[i:]") {
+		t.Fail()
+	}
+}
@@ -0,0 +1,106 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+)
+
+// pointerReceiver - the name of the receiver of the methods generated with -pointer. The body of every method starts
+// with 'l := *lp', so that the code of the templates is the same for both kinds of receivers
+const pointerReceiver = "lp"
+
+// inPlaceComment - the sentence added to the doc comment of the methods which replace the list by their result
+const inPlaceComment = "With a pointer receiver, the list is replaced by the result."
+
+// edit - a replacement of the text between two offsets of a source
+type edit struct {
+	start, end int
+	text       string
+}
+
+// pointerReceivers - change the value receivers of the methods on the lists to pointer receivers. The methods in
+// inPlace which return their list type replace the list by their result, eg: 'l.Filter(f)' removes the members of l
+// which do not satisfy f
+func pointerReceivers(code string, lists map[string]string, inPlace map[string]bool) (string, error) {
+	const prefix = "package p\n"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", prefix+code, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset - len(prefix)
+	}
+
+	edits := []edit{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || len(fn.Recv.List[0].Names) != 1 || fn.Body == nil {
+			continue
+		}
+		recv := fn.Recv.List[0]
+		listType, ok := recv.Type.(*ast.Ident)
+		if !ok || recv.Names[0].Name != "l" {
+			continue
+		}
+		if _, ok := lists[listType.Name]; !ok {
+			continue
+		}
+
+		edits = append(edits, edit{offset(recv.Pos()), offset(recv.End()), fmt.Sprintf("%s *%s", pointerReceiver, listType.Name)})
+		edits = append(edits, edit{offset(fn.Body.Lbrace) + 1, offset(fn.Body.Lbrace) + 1, "\nl := *" + pointerReceiver + "\n"})
+
+		if !inPlace[fn.Name.Name] || !returnsList(fn, listType.Name) {
+			continue
+		}
+		if fn.Doc != nil {
+			edits = append(edits, edit{offset(fn.Doc.End()), offset(fn.Doc.End()), "\n//\n// " + inPlaceComment})
+		}
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.FuncLit:
+				// the returns of the function literals are not the returns of the method
+				return false
+			case *ast.ReturnStmt:
+				if len(n.Results) == 1 {
+					result := code[offset(n.Results[0].Pos()):offset(n.Results[0].End())]
+					text := fmt.Sprintf("*%[1]s = %[2]s\nreturn *%[1]s", pointerReceiver, result)
+					edits = append(edits, edit{offset(n.Pos()), offset(n.End()), text})
+				}
+			}
+			return true
+		})
+	}
+
+	// the edits are applied from the end so that the offsets of the others stay valid
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, e := range edits {
+		code = code[:e.start] + e.text + code[e.end:]
+	}
+	return code, nil
+}
+
+// returnsList - whether a method returns only its list type
+func returnsList(fn *ast.FuncDecl, listName string) bool {
+	results := fn.Type.Results
+	if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 1 {
+		return false
+	}
+	ident, ok := results.List[0].Type.(*ast.Ident)
+	return ok && ident.Name == listName
+}
+
+// inPlaceMethods - get the names of the methods which replace the list by their result with -pointer, with the
+// -prefix and -suffix
+func inPlaceMethods(prefix, suffix string) map[string]bool {
+	result := map[string]bool{}
+	generators.Each(func(gen Generator) {
+		if gen.inPlace {
+			result[prefix+gen.name+suffix] = true
+		}
+	})
+	return result
+}
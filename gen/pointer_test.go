@@ -0,0 +1,40 @@
+package gen
+
+import (
+	"testing"
+)
+
+func TestPointerReceivers(t *testing.T) {
+	code := `
+// Filter is a method on intList that returns the members for which f returns true
+func (l intList) Filter(f func(int) bool) intList {
+	l2 := intList{}
+	for _, t := range l {
+		if func() bool { return f(t) }() {
+			l2 = append(l2, t)
+		}
+	}
+	return l2
+}
+
+// Len is a method on intList that returns its length
+func (l intList) Len() int {
+	return len(l)
+}
+
+func (pool *intListPool) Close() {
+}
+`
+	result, err := pointerReceivers(code, map[string]string{"intList": "int"}, map[string]bool{"Filter": true, "Len": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "PointerReceivers", result)
+}
+
+func TestInPlaceMethods(t *testing.T) {
+	methods := inPlaceMethods("F", "")
+	if !methods["FFilter"] || !methods["FPSort"] || methods["FMap"] || methods["Filter"] {
+		t.Fail()
+	}
+}
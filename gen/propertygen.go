@@ -0,0 +1,147 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// propertyFunctions - the functions the property tests of -with-properties check the laws of the methods of a list
+// with: f and g of type T -> T, the functions p and q of type T -> bool, and op of type (T, T) -> T, which is
+// commutative and associative, so that Reduce and ReduceRight get the same result with it
+type propertyFunctions struct {
+	f, g, p, q, op string
+}
+
+// propertyLaw - an algebraic law of the methods of the lists which the property tests check with testing/quick on
+// random lists. The check is generated with the list name, the type name and the propertyFunctions, in a function
+// taking the random list 'l' and returning whether the law holds. Like the invariants of the fuzz tests (see
+// fuzzInvariant), the methods are called on 'l' or on a copy of the list named 'members'
+type propertyLaw struct {
+	name        string
+	description string
+	methods     []string
+	check       func(listName, typeName string, functions propertyFunctions) string
+}
+
+// propertyLaws - the laws checked by the property tests, for the lists which have all their methods
+var propertyLaws = []propertyLaw{
+	{
+		name:        "MapIdentity",
+		description: "Map with the identity function returns the list",
+		methods:     []string{"Map"},
+		check: func(listName, typeName string, _ propertyFunctions) string {
+			return fmt.Sprintf(`original := append(%[1]s{}, l...)
+            mapped := append(%[1]s{}, l.Map(func(x %[2]s) %[2]s { return x })...)
+            return reflect.DeepEqual(mapped, original)`, listName, typeName)
+		},
+	},
+	{
+		name:        "MapComposition",
+		description: "Map with f and then with g returns the same list as Map with the composition of f and g",
+		methods:     []string{"Map"},
+		check: func(listName, typeName string, functions propertyFunctions) string {
+			return fmt.Sprintf(`f, g := %[3]s, %[4]s
+            members := append(%[1]s{}, l...)
+            members = members.Map(f)
+            members = members.Map(g)
+            composed := append(%[1]s{}, l.Map(func(x %[2]s) %[2]s { return g(f(x)) })...)
+            return reflect.DeepEqual(append(%[1]s{}, members...), composed)`, listName, typeName, functions.f, functions.g)
+		},
+	},
+	{
+		name:        "FilterComposition",
+		description: "Filter with p and then with q returns the same list as Filter with p and q",
+		methods:     []string{"Filter"},
+		check: func(listName, typeName string, functions propertyFunctions) string {
+			return fmt.Sprintf(`p, q := %[3]s, %[4]s
+            members := append(%[1]s{}, l...)
+            members = members.Filter(p)
+            members = members.Filter(q)
+            both := append(%[1]s{}, l.Filter(func(x %[2]s) bool { return p(x) && q(x) })...)
+            return reflect.DeepEqual(append(%[1]s{}, members...), both)`, listName, typeName, functions.p, functions.q)
+		},
+	},
+	{
+		name:        "ReduceReduceRight",
+		description: "Reduce and ReduceRight get the same result with a commutative and associative function",
+		methods:     []string{"Reduce", "ReduceRight"},
+		check: func(_, typeName string, functions propertyFunctions) string {
+			return fmt.Sprintf(`op := %[2]s
+            var initial %[1]s
+            return l.Reduce(initial, op) == l.ReduceRight(initial, op)`, typeName, functions.op)
+		},
+	},
+}
+
+// propertyKind - get the propertyFunctions of a type: the property tests are generated for the lists of the numbers,
+// the strings and the bools, whose random values testing/quick generates. The other types have no property tests
+func (p plan) propertyKind(typeName string) (propertyFunctions, bool) {
+	greatest := fmt.Sprintf(`func(a, b %[1]s) %[1]s {
+                if a > b {
+                    return a
+                }
+                return b
+            }`, typeName)
+	switch {
+	case typeName == "bool":
+		return propertyFunctions{
+			f:  "func(x bool) bool { return !x }",
+			g:  "func(x bool) bool { return x }",
+			p:  "func(x bool) bool { return x }",
+			q:  "func(x bool) bool { return !x }",
+			op: "func(a, b bool) bool { return a || b }",
+		}, true
+	case p.ordering(typeName) == "number":
+		return propertyFunctions{
+			f:  fmt.Sprintf("func(x %s) %[1]s { return x + 1 }", typeName),
+			g:  fmt.Sprintf("func(x %s) %[1]s { return x * 2 }", typeName),
+			p:  fmt.Sprintf("func(x %s) bool { return x > 0 }", typeName),
+			q:  fmt.Sprintf("func(x %s) bool { return x < 100 }", typeName),
+			op: greatest,
+		}, true
+	case p.ordering(typeName) == "string":
+		return propertyFunctions{
+			f:  fmt.Sprintf(`func(x %s) %[1]s { return x + "a" }`, typeName),
+			g:  fmt.Sprintf(`func(x %s) %[1]s { return "b" + x }`, typeName),
+			p:  fmt.Sprintf("func(x %s) bool { return len(x)%%2 == 0 }", typeName),
+			q:  fmt.Sprintf(`func(x %s) bool { return x < "m" }`, typeName),
+			op: greatest,
+		}, true
+	}
+	return propertyFunctions{}, false
+}
+
+// generatePropertyTests - generate the property tests of the laws of the selected methods of the lists of the types whose
+// random values testing/quick generates (see propertyKind), for -with-properties. Every law gets a test, eg:
+// 'TestIntListLawMapComposition', which checks it with quick.Check on random lists of the type
+func generatePropertyTests(packageName string, selected map[string]string, p plan) string {
+	code := fmt.Sprintf(`package %[1]s
+            `, packageName)
+
+	for _, typeName := range sortedTypes(selected) {
+		functions, ok := p.propertyKind(typeName)
+		if !ok {
+			continue
+		}
+		listName := strings.TrimPrefix(selected[typeName], "*") + "List"
+		methods := p.methodsOf(listName)
+
+		for _, law := range propertyLaws {
+			if !hasMethods(methods, law.methods) {
+				continue
+			}
+			code += fmt.Sprintf(`
+            // Test%[1]sLaw%[2]s checks on random lists that %[3]s
+            func Test%[1]sLaw%[2]s(t *testing.T) {
+                law := func(l %[4]s) bool {
+                    %[5]s
+                }
+                if err := quick.Check(law, nil); err != nil {
+                    t.Error(err)
+                }
+            }
+            `, strings.Title(listName), law.name, law.description, listName, law.check(listName, typeName, functions))
+		}
+	}
+	return code
+}
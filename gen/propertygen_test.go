@@ -0,0 +1,53 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePropertyTests(t *testing.T) {
+	m := map[string]string{"int": "int", "string": "Str", "bool": "bool", "point": "point"}
+	src := f(generatePropertyTests("main", m, planOf("Map,Filter,Reduce,ReduceRight")))
+
+	for _, expected := range []string{
+		"func TestIntListLawMapIdentity(t *testing.T) {", "func TestIntListLawMapComposition(t *testing.T) {",
+		"func TestIntListLawFilterComposition(t *testing.T) {", "func TestIntListLawReduceReduceRight(t *testing.T) {",
+		"func TestStrListLawMapComposition(t *testing.T) {", "func TestBoolListLawReduceReduceRight(t *testing.T) {",
+		"law := func(l intList) bool {", "if err := quick.Check(law, nil); err != nil {",
+		"return l.Reduce(initial, op) == l.ReduceRight(initial, op)", "op := func(a, b bool) bool { return a || b }",
+	} {
+		if !strings.Contains(src, expected) {
+			t.Errorf("missing %s", expected)
+		}
+	}
+	// testing/quick cannot generate the points
+	if strings.Contains(src, "pointList") {
+		t.Error("unexpected pointList")
+	}
+
+	// the laws are only checked for the lists which have all their methods
+	if src := generatePropertyTests("main", map[string]string{"int": "int"}, planOf("Map,Reduce")); strings.Contains(src, "ReduceReduceRight") || strings.Contains(src, "FilterComposition") || !strings.Contains(src, "MapComposition") {
+		t.Error(src)
+	}
+}
+
+func TestGeneratePropertyTestsRenamed(t *testing.T) {
+	src, err := GeneratePropertyTests(Spec{Package: "main", Types: map[string]string{"int": "int"}, Methods: []string{"Map", "Filter"}, Suffix: "X", Pointer: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"members = members.MapX(f)", "l.FilterX(func(x int) bool", `"reflect"`, `"testing/quick"`} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("missing %s in:\n%s", expected, src)
+		}
+	}
+}
+
+func TestGeneratePropertyTestsImports(t *testing.T) {
+	// the structs of another package have no property tests, and their package is not imported
+	spec := Spec{Package: "main", Types: map[string]string{"int": "int", "example.com/t3/models.User": "MU"}, Methods: []string{"Map", "Filter"}}
+	src, err := GeneratePropertyTests(spec)
+	if err != nil || strings.Contains(string(src), "example.com/t3/models") || !strings.Contains(string(src), "TestIntListLaw") {
+		t.Fatal(err, string(src))
+	}
+}
@@ -0,0 +1,179 @@
+package gen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+)
+
+// renameMethods - add the Prefix and the Suffix of a Spec to the names of the methods declared in code on the lists,
+// the maps and the arrays generated, to their doc comments, and to the calls of these methods in code, eg: 'l.Map(f)'
+// or 'doubled.Take(1)' in a test. The calls are resolved by type-checking code with declarations, the code generated
+// for the types (see declarations), so the calls of the methods of the other types, like a Set or a package, are left
+// as they are
+func (p plan) renameMethods(code string, spec Spec, declarations string) string {
+	if spec.Prefix == "" && spec.Suffix == "" {
+		return code
+	}
+	rename := func(name string) string {
+		return spec.Prefix + name + spec.Suffix
+	}
+
+	// the code of the types has no package clause
+	prefix := ""
+	if !strings.HasPrefix(strings.TrimSpace(code), "package ") {
+		prefix = "package p\n"
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", prefix+code, parser.ParseComments)
+	if err != nil {
+		// the errors are reported by the formatting of the code
+		return code
+	}
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset - len(prefix)
+	}
+
+	files := []*ast.File{file}
+	if declarations != "" {
+		declared, err := parser.ParseFile(fset, "", "package "+file.Name.Name+"\n"+declarations, 0)
+		if err == nil {
+			files = append(files, declared)
+		}
+	}
+	generated := p.generatedTypes()
+	if stubs := undeclaredTypes(files, generated); stubs != "" {
+		// the types declared by the package of the lists (see Spec.Declared) only need a name to resolve the calls
+		if stubbed, err := parser.ParseFile(fset, "", "package "+file.Name.Name+"\n"+stubs, 0); err == nil {
+			files = append(files, stubbed)
+		}
+	}
+	info := &types.Info{Selections: map[*ast.SelectorExpr]*types.Selection{}}
+	config := types.Config{
+		// the element types of other packages and of the package of the lists are not known, the expressions using
+		// them are left untyped
+		Error:    func(error) {},
+		Importer: emptyImporter{},
+	}
+	config.Check(file.Name.Name, fset, files, info)
+
+	edits := []edit{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || !generated[receiverType(fn.Recv.List[0].Type)] {
+			continue
+		}
+		name := fn.Name.Name
+		edits = append(edits, edit{offset(fn.Name.Pos()), offset(fn.Name.End()), rename(name)})
+		if fn.Doc != nil && strings.HasPrefix(fn.Doc.List[0].Text, "// "+name+" ") {
+			start := offset(fn.Doc.List[0].Pos()) + len("// ")
+			edits = append(edits, edit{start, start + len(name), rename(name)})
+		}
+	}
+	for expr, selection := range info.Selections {
+		if selection.Kind() == types.FieldVal || fset.File(expr.Pos()) != fset.File(file.Pos()) {
+			continue
+		}
+		recv := selection.Obj().Type().(*types.Signature).Recv()
+		if recv == nil {
+			continue
+		}
+		recvType := recv.Type()
+		if pointer, ok := recvType.(*types.Pointer); ok {
+			recvType = pointer.Elem()
+		}
+		if named, ok := recvType.(*types.Named); ok && generated[named.Obj().Name()] {
+			edits = append(edits, edit{offset(expr.Sel.Pos()), offset(expr.Sel.End()), rename(expr.Sel.Name)})
+		}
+	}
+
+	// the edits are applied from the end so that the offsets of the others stay valid
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, e := range edits {
+		code = code[:e.start] + e.text + code[e.end:]
+	}
+	return code
+}
+
+// receiverType - get the name of the type of a receiver, eg: 'intList' for 'l intList' and 'lp *intList'
+func receiverType(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// undeclaredTypes - get the declarations of the types not declared by the files, as empty structs
+func undeclaredTypes(files []*ast.File, names map[string]bool) string {
+	declared := map[string]bool{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+				for _, spec := range gen.Specs {
+					declared[spec.(*ast.TypeSpec).Name.Name] = true
+				}
+			}
+		}
+	}
+	undeclared := []string{}
+	for name := range names {
+		if !declared[name] {
+			undeclared = append(undeclared, "type "+name+" struct{}\n")
+		}
+	}
+	sort.Strings(undeclared)
+	return strings.Join(undeclared, "")
+}
+
+// generatedTypes - get the names of the types whose methods are renamed with the Prefix and the Suffix: the lists, the
+// maps and the arrays
+func (p plan) generatedTypes() map[string]bool {
+	result := map[string]bool{}
+	for listName := range p.lists() {
+		result[listName] = true
+	}
+	for _, names := range []map[string]string{p.maps, p.arrays} {
+		for _, name := range names {
+			result[name] = true
+		}
+	}
+	return result
+}
+
+// declarations - get the code generated for the lists, the maps and the arrays of a Spec, with the names of their
+// methods not renamed yet, which the calls of the methods are resolved with (see renameMethods). It is only generated
+// with a Prefix or a Suffix
+func (p plan) declarations(spec Spec) string {
+	if spec.Prefix == "" && spec.Suffix == "" {
+		return ""
+	}
+	code := p.aliasDeclarations()
+	for _, typeName := range sortedTypes(p.types) {
+		listName := strings.TrimPrefix(p.types[typeName], "*") + "List"
+		code += generate(typeName, listName, p.targets, p, spec.Chunked, spec.Pooled)
+	}
+	for _, typeName := range sortedTypes(p.maps) {
+		code += generateMap(typeName, p.maps[typeName], p)
+	}
+	for _, typeName := range sortedTypes(p.arrays) {
+		code += generateArray(typeName, p.arrays[typeName], p)
+	}
+	return code
+}
+
+// emptyImporter - import every package as an empty package named like the last element of its path, so that the
+// generated code is type-checked without the packages of the element types
+type emptyImporter struct{}
+
+func (emptyImporter) Import(importPath string) (*types.Package, error) {
+	pkg := types.NewPackage(importPath, path.Base(importPath))
+	pkg.MarkComplete()
+	return pkg, nil
+}
@@ -0,0 +1,49 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenameMethods(t *testing.T) {
+	p := plan{types: map[string]string{"string": "string"}}
+	spec := Spec{Prefix: "F", Suffix: "X"}
+	checkGolden(t, "RenameMethods", p.renameMethods(getTakeFunction("stringList", "string", "", ""), spec, ""))
+
+	declarations := "type stringList []string\n" + getTakeFunction("stringList", "string", "", "") + `
+        type stringSet map[string]bool
+
+        func (s stringSet) Take(n int) stringSet {
+            return s
+        }`
+	code := `package main
+
+        func TestTake(t *testing.T) {
+            l := stringList{"a", "b"}
+            taken := l.Take(1)
+            take := taken.Take
+            s := stringSet{}
+            if s.Take(1) == nil || strings.Map(unicode.ToUpper, "a") == "" || len(take(1)) != 1 {
+                t.Fail()
+            }
+        }`
+	result := p.renameMethods(code, spec, declarations)
+	for _, expected := range []string{"l.FTakeX(1)", "taken.FTakeX\n", "s.Take(1)", "strings.Map("} {
+		if !strings.Contains(result, expected) {
+			t.Error(expected)
+		}
+	}
+
+	if p.renameMethods(code, Spec{}, declarations) != code {
+		t.Fail()
+	}
+}
+
+func TestRenameMethodsDeclared(t *testing.T) {
+	// the lists declared by the package of the lists have no declaration in the generated code
+	p := plan{types: map[string]string{"int": "int"}, declared: map[string]bool{"intList": true}}
+	result := p.renameMethods("func (l intList) Sum() int {\n\treturn 0\n}\n\nfunc (l intList) Average() int {\n\treturn l.Sum() / len(l)\n}\n", Spec{Prefix: "F"}, "")
+	if !strings.Contains(result, "func (l intList) FSum() int {") || !strings.Contains(result, "return l.FSum() / len(l)") {
+		t.Error(result)
+	}
+}
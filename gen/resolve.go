@@ -0,0 +1,164 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// splitQualified - split an element type qualified with a package, eg: '*[]model.User' or
+// '[]github.com/user/app/model.User', into its prefix ('*[]'), the name or the import path of its package and the name
+// of the type. The package is empty if the type is not a qualified type name, eg: 'int' or 'map[string]time.Time'
+func splitQualified(typeName string) (string, string, string) {
+	prefix := typeName[:len(typeName)-len(strings.TrimLeft(typeName, "*[]"))]
+	qualified := typeName[len(prefix):]
+	dot := strings.LastIndex(qualified, ".")
+	if dot < 0 || !validName.MatchString(qualified[dot+1:]) {
+		return prefix, "", qualified
+	}
+	pkg := qualified[:dot]
+	if !validName.MatchString(pkg) && (!strings.Contains(pkg, "/") || strings.ContainsAny(pkg, "[]()*, ")) {
+		// eg: 'map[string]time.Time'
+		return prefix, "", qualified
+	}
+	return prefix, pkg, qualified[dot+1:]
+}
+
+// importedPackages - get the import paths of the packages imported by the non-test Go files of a directory, by the
+// last element of their paths, which is the name the generated code refers to them with
+func importedPackages(dir string) (map[string]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]string{}
+	for _, info := range files {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") || strings.HasSuffix(info.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, info.Name()), nil, parser.ImportsOnly)
+		if err != nil {
+			// the files which cannot be parsed are reported by the type-checking of the generated code
+			continue
+		}
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err == nil {
+				result[path.Base(importPath)] = importPath
+			}
+		}
+	}
+	return result, nil
+}
+
+// ResolveTypes - qualify the element types given with the name of their package, eg: 'model.User', with the import
+// path of the package, eg: 'github.com/user/app/model.User', so that the generated code imports it (see QualifyTypes).
+// The packages are the standard packages the generated code refers to and the packages imported by the Go files of dir.
+// The key and the value types of a map type are resolved too
+func ResolveTypes(dir string, m map[string]string) (map[string]string, error) {
+	imported, err := importedPackages(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolve func(typeName string) (string, error)
+	resolve = func(typeName string) (string, error) {
+		prefix, pkg, name := splitQualified(typeName)
+		if key, value, ok := splitMapType(name); ok && pkg == "" {
+			var err error
+			if key, err = resolve(key); err == nil {
+				value, err = resolve(value)
+			}
+			return prefix + "map[" + key + "]" + value, err
+		}
+		if _, ok := standardPackages[pkg]; pkg == "" || ok || strings.Contains(pkg, "/") {
+			return typeName, nil
+		}
+		importPath, ok := imported[pkg]
+		if !ok {
+			return "", fmt.Errorf("'%s' is not valid: the package '%s' is not imported by the files of the package, give its import path, eg: 'github.com/user/app/%s.%s'", typeName, pkg, pkg, name)
+		}
+		return prefix + importPath + "." + name, nil
+	}
+
+	result := map[string]string{}
+	for _, typeName := range sortedTypes(m) {
+		resolved, err := resolve(typeName)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := result[resolved]; ok {
+			return nil, fmt.Errorf("'%s' is not valid: it is the same type as '%s'", typeName, other)
+		}
+		result[resolved] = m[typeName]
+	}
+	return result, nil
+}
+
+// CheckTypes - check that the packages of the element types qualified with a package exist and declare the types,
+// exported. The types are given with the import path of their package or with the name of a standard package (see
+// ResolveTypes). It returns the types which are not valid, and an error if a package cannot be loaded, eg: because it
+// is not downloaded, in which case its types are not checked
+func CheckTypes(m map[string]string) ([]string, error) {
+	imp := importer.ForCompiler(token.NewFileSet(), "source", nil)
+	packages := map[string]*types.Package{}
+	invalid := []string{}
+	var loadErr error
+	for _, typeName := range sortedTypes(elementTypes(m)) {
+		_, pkg, name := splitQualified(typeName)
+		if pkg == "" {
+			continue
+		}
+		importPath := pkg
+		if standard, ok := standardPackages[pkg]; ok {
+			importPath = standard
+		}
+
+		loaded, ok := packages[importPath]
+		if !ok {
+			var err error
+			if loaded, err = imp.Import(importPath); err != nil && loadErr == nil {
+				loadErr = fmt.Errorf("the package '%s' of '%s' cannot be loaded: %s", importPath, typeName, err)
+			}
+			packages[importPath] = loaded
+		}
+		if loaded == nil {
+			continue
+		}
+		if !ast.IsExported(name) {
+			invalid = append(invalid, fmt.Sprintf("'%s' is not valid: '%s' is not exported", typeName, name))
+		} else if _, ok := loaded.Scope().Lookup(name).(*types.TypeName); !ok {
+			invalid = append(invalid, fmt.Sprintf("'%s' is not valid: the package '%s' has no type '%s'", typeName, importPath, name))
+		}
+	}
+	return invalid, loadErr
+}
+
+// elementTypes - get the types of the type map, with the key and the value types of the map types instead of the map
+// types, eg: 'string' and 'model.User' for 'map[string]model.User'
+func elementTypes(m map[string]string) map[string]string {
+	result := map[string]string{}
+	var add func(typeName string)
+	add = func(typeName string) {
+		_, pkg, name := splitQualified(typeName)
+		if key, value, ok := splitMapType(name); ok && pkg == "" {
+			add(key)
+			add(value)
+			return
+		}
+		result[typeName] = m[typeName]
+	}
+	for typeName := range m {
+		add(typeName)
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package gen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSplitQualified(t *testing.T) {
+	for typeName, expected := range map[string][3]string{
+		"int":                                {"", "", "int"},
+		"*[]model.User":                      {"*[]", "model", "User"},
+		"[]github.com/user/app/model.User":   {"[]", "github.com/user/app/model", "User"},
+		"map[string]time.Time":               {"", "", "map[string]time.Time"},
+		"func(github.com/user/app/model.T)":  {"", "", "func(github.com/user/app/model.T)"},
+		"*github.com/user/app/model.Account": {"*", "github.com/user/app/model", "Account"},
+	} {
+		prefix, pkg, name := splitQualified(typeName)
+		if [3]string{prefix, pkg, name} != expected {
+			t.Error(typeName, prefix, pkg, name)
+		}
+	}
+}
+
+func TestResolveTypes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := "package models\n\nimport (\n\t\"net/url\"\n\t\"github.com/user/app/model\"\n)\n\nvar _ url.URL\nvar _ model.User\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "models.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := map[string]string{"int": "int", "time.Time": "Time", "url.URL": "URL", "*model.User": "*User", "github.com/user/app/model.T": "T"}
+	result, err := ResolveTypes(dir, m)
+	expected := map[string]string{"int": "int", "time.Time": "Time", "net/url.URL": "URL", "*github.com/user/app/model.User": "*User", "github.com/user/app/model.T": "T"}
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Error(err, result)
+	}
+
+	result, err = ResolveTypes(dir, map[string]string{"map[string]*model.User": "users", "map[url.URL]time.Time": "times"})
+	expected = map[string]string{"map[string]*github.com/user/app/model.User": "users", "map[net/url.URL]time.Time": "times"}
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Error(err, result)
+	}
+
+	if _, err := ResolveTypes(dir, map[string]string{"other.User": "User"}); err == nil {
+		t.Fail()
+	}
+	if _, err := ResolveTypes(dir, map[string]string{"model.User": "User", "github.com/user/app/model.User": "U"}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCheckTypes(t *testing.T) {
+	invalid, err := CheckTypes(map[string]string{"int": "int", "time.Time": "Time", "*time.Duration": "*D", "time.Tme": "Tme", "time.month": "M", "net/url.URL": "URL"})
+	if err != nil || len(invalid) != 2 {
+		t.Error(err, invalid)
+	}
+
+	invalid, err = CheckTypes(map[string]string{"map[string]time.Tme": "index", "map[time.Month]time.Time": "months"})
+	if err != nil || len(invalid) != 1 {
+		t.Error(err, invalid)
+	}
+
+	invalid, err = CheckTypes(map[string]string{"example.com/missing.User": "User", "time.Tme": "Tme"})
+	if err == nil || len(invalid) != 1 {
+		t.Error(err, invalid)
+	}
+}
@@ -0,0 +1,138 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+// safeName - get the name of the thread-safe wrapper of a list (see safeWrapper), eg: 'safeIntList' for 'intList' and
+// 'SafeUserList' for 'UserList'
+func safeName(listName string) string {
+	if ast.IsExported(listName) {
+		return "Safe" + listName
+	}
+	return "safe" + strings.Title(listName)
+}
+
+var safeTypeTemplate = render.Template("SafeType")
+
+var safeMethodTemplate = render.Template("SafeMethod")
+
+// safeWrapper - generate the thread-safe wrapper of a list: a struct embedding a sync.RWMutex, holding the list, with
+// a method for every method of the list in code, which calls it with the lock held: the write lock for the methods
+// replacing the list by their result with a pointer receiver (see pointerReceivers) and for the methods in mutating,
+// which change the members of the list, and the read lock for the others. The mutating methods with a value receiver
+// which return the list, like FilterInPlace, replace the list of the wrapper by their result. The methods returning the
+// list, or a slice of it like Take, return a copy made with the lock held, so that their result does not share its
+// members with the list of the wrapper, and the methods in retaining, whose result reads the list after they return,
+// like Iter, are called on a copy of the list. Snapshot gets a copy of the list, and ReplaceAll replaces it by a copy of
+// another list
+func safeWrapper(code, listName string, inPlace, mutating, retaining map[string]bool) (string, error) {
+	const prefix = "package p\n"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", prefix+code, 0)
+	if err != nil {
+		return "", err
+	}
+	text := func(node ast.Node) string {
+		return code[fset.Position(node.Pos()).Offset-len(prefix) : fset.Position(node.End()).Offset-len(prefix)]
+	}
+
+	name := safeName(listName)
+	result := render.Declarations(safeTypeTemplate, struct{ Name, ListName string }{name, listName})
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || !ast.IsExported(fn.Name.Name) {
+			continue
+		}
+		recv := fn.Recv.List[0].Type
+		star, pointer := recv.(*ast.StarExpr)
+		if pointer {
+			recv = star.X
+		}
+		if ident, ok := recv.(*ast.Ident); !ok || ident.Name != listName {
+			continue
+		}
+
+		params, args := []string{}, []string{}
+		for _, field := range fn.Type.Params.List {
+			names := []string{}
+			for _, ident := range field.Names {
+				names = append(names, ident.Name)
+			}
+			if len(names) == 0 {
+				names = append(names, "")
+			}
+			for _, paramName := range names {
+				// the receiver of the wrapper is s
+				if paramName == "" || paramName == "_" || paramName == "s" {
+					paramName = fmt.Sprintf("p%d", len(args))
+				}
+				params = append(params, paramName+" "+text(field.Type))
+				if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+					paramName += "..."
+				}
+				args = append(args, paramName)
+			}
+		}
+		method := fmt.Sprintf("%s(%s)", fn.Name.Name, strings.Join(args, ", "))
+		results, call := "", "s.list."+method
+		if fn.Type.Results != nil {
+			results, call = " "+text(fn.Type.Results), "return "+call
+		}
+		if retaining[fn.Name.Name] {
+			call = fmt.Sprintf("l := make(%[1]s, len(s.list))\n\tcopy(l, s.list)\n\treturn l.%[2]s", listName, method)
+		}
+
+		lock, unlock, held := "RLock", "RUnlock", "the read lock"
+		// the result is copied with the lock held, from the list of the wrapper once it is replaced by the result
+		update, source := "l := s.list."+method, "l"
+		if pointer && inPlace[fn.Name.Name] && returnsList(fn, listName) {
+			lock, unlock, held = "Lock", "Unlock", "the write lock"
+		} else if mutating[fn.Name.Name] {
+			lock, unlock, held = "Lock", "Unlock", "the write lock"
+			if !pointer && returnsList(fn, listName) {
+				update, source = "s.list = s.list."+method, "s.list"
+			}
+		}
+		if returnsList(fn, listName) {
+			call = fmt.Sprintf("%[1]s\n\tl2 := make(%[2]s, len(%[3]s))\n\tcopy(l2, %[3]s)\n\treturn l2", update, listName, source)
+		}
+		result += render.Declarations(safeMethodTemplate, struct{ Name, Method, ListName, Held, Params, Results, Lock, Unlock, Call string }{
+			name, fn.Name.Name, listName, held, strings.Join(params, ", "), results, lock, unlock, call,
+		})
+	}
+	return result, nil
+}
+
+// retainingMethods - get the names of the methods whose result reads the list after they return, with the suffixes of
+// the targets, the -prefix and the -suffix
+func retainingMethods(prefix, suffix string, targets map[string]string) map[string]bool {
+	result := map[string]bool{}
+	generators.Each(func(gen Generator) {
+		if gen.retaining {
+			result[prefix+gen.name+suffix] = true
+			for targetType, targetTypeName := range targets {
+				result[prefix+gen.name+newTemplateData("", "", targetType, targetTypeName).Suffix+suffix] = true
+			}
+		}
+	})
+	return result
+}
+
+// mutatingMethods - get the names of the methods which change the members of the list, with the -prefix and -suffix
+func mutatingMethods(prefix, suffix string) map[string]bool {
+	result := map[string]bool{}
+	generators.Each(func(gen Generator) {
+		if gen.mutating {
+			result[prefix+gen.name+suffix] = true
+		}
+	})
+	return result
+}
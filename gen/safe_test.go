@@ -0,0 +1,67 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSafeName(t *testing.T) {
+	if safeName("intList") != "safeIntList" || safeName("UserList") != "SafeUserList" {
+		t.Fail()
+	}
+}
+
+func TestSafeWrapper(t *testing.T) {
+	code := `
+        func (l intList) Take(n int) intList { return l }
+        func (lp *intList) Filter(f func(int) bool) intList { return *lp }
+        func (l intList) Each(func(int)) {}
+        func (l intList) Pool(s int, pool ...*intListPool) (int, bool) { return 0, false }
+        func (l stringList) Map(f func(string) string) stringList { return l }
+        func (l intList) unexported() {}
+        func (l intList) FilterInPlace(f func(int) bool) intList { return l }
+        func (lp *intList) ReverseInPlace() intList { return *lp }
+        func (l intList) Values() iter.Seq[int] { return nil }
+        func intListFromChan(in <-chan int) intList { return nil }
+        `
+	result, err := safeWrapper(code, "intList", map[string]bool{"Take": true, "Filter": true}, map[string]bool{"FilterInPlace": true, "ReverseInPlace": true}, map[string]bool{"Values": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"type safeIntList struct {\n\tsync.RWMutex\n\tlist intList\n}",
+		"func (s *safeIntList) Snapshot() intList {",
+		"func (s *safeIntList) ReplaceAll(l intList) {",
+		"func (s *safeIntList) Take(n int) intList {\n\ts.RLock()\n\tdefer s.RUnlock()\n\tl := s.list.Take(n)\n\tl2 := make(intList, len(l))\n\tcopy(l2, l)\n\treturn l2",
+		"func (s *safeIntList) Filter(f func(int) bool) intList {\n\ts.Lock()\n\tdefer s.Unlock()\n\tl := s.list.Filter(f)\n\tl2 := make(intList, len(l))",
+		"func (s *safeIntList) Each(p0 func(int)) {\n\ts.RLock()\n\tdefer s.RUnlock()\n\ts.list.Each(p0)",
+		"func (s *safeIntList) Pool(p0 int, pool ...*intListPool) (int, bool) {",
+		"return s.list.Pool(p0, pool...)",
+		"func (s *safeIntList) FilterInPlace(f func(int) bool) intList {\n\ts.Lock()\n\tdefer s.Unlock()\n\ts.list = s.list.FilterInPlace(f)\n\tl2 := make(intList, len(s.list))\n\tcopy(l2, s.list)\n\treturn l2",
+		"func (s *safeIntList) ReverseInPlace() intList {\n\ts.Lock()\n\tdefer s.Unlock()\n\tl := s.list.ReverseInPlace()\n\tl2 := make(intList, len(l))",
+		"func (s *safeIntList) Values() iter.Seq[int] {\n\ts.RLock()\n\tdefer s.RUnlock()\n\tl := make(intList, len(s.list))\n\tcopy(l, s.list)\n\treturn l.Values()",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Error(expected, result)
+		}
+	}
+	for _, unexpected := range []string{"Map", "unexported", "FromChan"} {
+		if strings.Contains(result, unexpected) {
+			t.Error(unexpected)
+		}
+	}
+}
+
+func TestGenerateSafe(t *testing.T) {
+	spec := Spec{Package: "main", Types: map[string]string{"int": "int"}, Methods: []string{"Map", "Filter"}, Safe: true, Pointer: true, Prefix: "F"}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, expected := range []string{"\"sync\"", "type safeIntList struct {", "func (s *safeIntList) FMap(f func(int) int) intList {", "calls FFilter on its intList with the write lock held"} {
+		if !strings.Contains(code, expected) {
+			t.Error(expected)
+		}
+	}
+}
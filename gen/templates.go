@@ -0,0 +1,166 @@
+package gen
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+// templateExtension - the extension of the template files in the -templates directory
+const templateExtension = ".tmpl"
+
+// TemplateData - the data which the templates of the -templates directory are executed with
+type TemplateData struct {
+	// ListName - the name of the list type, eg: 'stringList'
+	ListName string
+	// TypeName - the type of the members of the list, eg: 'string'
+	TypeName string
+	// TargetType - the type of the members of the target list for the methods which map to other types, eg: 'int'. It
+	// is the same as TypeName for the method mapping to the same type
+	TargetType string
+	// TargetListName - the name of the target list, eg: 'intList'
+	TargetListName string
+	// Suffix - the suffix of the method name for the target type, eg: 'Int' (in 'MapInt'). It is empty for the method
+	// mapping to the same type
+	Suffix string
+}
+
+// newTemplateData - get the template data for the arguments of a generator function
+func newTemplateData(listName, typeName, targetType, targetTypeName string) TemplateData {
+	data := TemplateData{ListName: listName, TypeName: typeName, TargetType: targetType, TargetListName: listName}
+	if targetTypeName != "" {
+		name := strings.TrimPrefix(targetTypeName, "*")
+		data.TargetListName = name + "List"
+		data.Suffix = strings.Title(name)
+	}
+	if data.TargetType == "" {
+		data.TargetType = typeName
+	}
+	return data
+}
+
+// templateMethod - get a generator function which executes the template
+func templateMethod(tmpl *template.Template) func(_, _, _, _ string) string {
+	return func(listName, typeName, targetType, targetTypeName string) string {
+		return "\n" + render.Execute(tmpl, newTemplateData(listName, typeName, targetType, targetTypeName)) + "\n"
+	}
+}
+
+// loadedTemplate - a template of the -templates directory, with the packages it imports with '{{import "sync"}}' and
+// whether it is executed for every target type with '{{perTarget}}'
+type loadedTemplate struct {
+	name      string
+	tmpl      *template.Template
+	imports   []string
+	perTarget bool
+}
+
+// loadTemplates - parse the template files (eg: 'Filter.tmpl') of a directory, in the order of their names
+func loadTemplates(dir string) ([]loadedTemplate, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := []loadedTemplate{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != templateExtension {
+			continue
+		}
+
+		name := strings.TrimSuffix(file.Name(), templateExtension)
+		if !validName.MatchString(name) {
+			return nil, fmt.Errorf("%s: '%s' is not a valid method name", filepath.Join(dir, file.Name()), name)
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		loaded, collecting := loadedTemplate{name: name}, true
+		funcs := template.FuncMap{
+			"title": strings.Title,
+			"import": func(path string) string {
+				if collecting {
+					loaded.imports = append(loaded.imports, path)
+				}
+				return ""
+			},
+			"perTarget": func() string {
+				loaded.perTarget = true
+				return ""
+			},
+		}
+		loaded.tmpl, err = template.New(name).Funcs(funcs).Parse(string(content))
+		if err != nil {
+			return nil, err
+		}
+		// the imports are collected by executing the template once
+		if err := loaded.tmpl.Execute(ioutil.Discard, newTemplateData("TList", "T", "U", "U")); err != nil {
+			return nil, err
+		}
+		collecting = false
+		templates = append(templates, loaded)
+	}
+	return templates, nil
+}
+
+// Templates - the templates of a -templates directory, loaded by LoadTemplates, which override the built-in methods or
+// add extra methods to the Specs generated with them (see Spec.Templates). Without templates, the built-in methods are
+// generated
+type Templates struct {
+	loaded []loadedTemplate
+}
+
+// LoadTemplates - load the text/template files (eg: 'Filter.tmpl') of a directory, overriding the built-in methods
+// named like them or adding extra methods
+func LoadTemplates(dir string) (Templates, error) {
+	loaded, err := loadTemplates(dir)
+	if err != nil {
+		return Templates{}, err
+	}
+	return Templates{loaded}, nil
+}
+
+// generators - get the generators of the built-in methods with the templates: the built-in templates replaced by the
+// ones of the directory, and the other templates of the directory added as extra methods after the built-in ones. The
+// built-in generators are not changed. The overridden methods use their template whether or not -chunked or -pool is
+// set. The extra methods are generated once for every list, or once for every target type like Map if they call
+// '{{perTarget}}'
+func (t Templates) generators() GeneratorList {
+	if len(t.loaded) == 0 {
+		return generators
+	}
+
+	result := append(GeneratorList{}, generators...)
+	for _, loaded := range t.loaded {
+		found := false
+		for i := range result {
+			if result[i].name == loaded.name {
+				result[i].method = templateMethod(loaded.tmpl)
+				result[i].chunkedMethod = nil
+				result[i].pooledMethod = nil
+				result[i].grownMethod = nil
+				result[i].copiedMethod = nil
+				result[i].twoPassMethod = nil
+				result[i].test = nil
+				result[i].example = nil
+				result[i].imports = loaded.imports
+				found = true
+			}
+		}
+		if !found {
+			result = append(result, Generator{
+				name:         loaded.name,
+				method:       templateMethod(loaded.tmpl),
+				imports:      loaded.imports,
+				needMapToMap: loaded.perTarget,
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,128 @@
+package gen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateData(t *testing.T) {
+	data := newTemplateData("stringList", "string", "int", "I")
+	if data != (TemplateData{ListName: "stringList", TypeName: "string", TargetType: "int", TargetListName: "IList", Suffix: "I"}) {
+		t.Fail()
+	}
+
+	data = newTemplateData("stringList", "string", "string", "")
+	if data != (TemplateData{ListName: "stringList", TypeName: "string", TargetType: "string", TargetListName: "stringList"}) {
+		t.Fail()
+	}
+
+	data = newTemplateData("stringList", "string", "*point", "*point")
+	if data.TargetListName != "pointList" || data.Suffix != "Point" {
+		t.Fail()
+	}
+}
+
+func TestTemplateMethod(t *testing.T) {
+	dir, err := ioutil.TempDir("", "templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := `{{import "sort"}}{{perTarget -}}
+// Sorted{{.Suffix}} is a method on {{.ListName}} that maps the members and sorts the result
+func (l {{.ListName}}) Sorted{{.Suffix}}(f func({{.TypeName}}) {{.TargetType}}, less func({{.TargetType}}, {{.TargetType}}) bool) {{.TargetListName}} {
+	l2 := l.Map{{.Suffix}}(f)
+	sort.SliceStable(l2, func(i, j int) bool { return less(l2[i], l2[j]) })
+	return l2
+}
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "Sorted.tmpl"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := loadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].name != "Sorted" || len(templates[0].imports) != 1 || templates[0].imports[0] != "sort" || !templates[0].perTarget {
+		t.Fatal(templates)
+	}
+
+	checkGolden(t, "TemplateMethod", templateMethod(templates[0].tmpl)("stringList", "string", "int", "I"))
+
+	if len(templates[0].imports) != 1 {
+		t.Fail()
+	}
+}
+
+func TestSpecTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"Filter.tmpl": "// Filter is a method on {{.ListName}} that keeps every member\nfunc (l {{.ListName}}) Filter(f func({{.TypeName}}) bool) {{.ListName}} {\n\treturn l\n}\n",
+		"Len.tmpl":    "// Len is a method on {{.ListName}} that returns its length\nfunc (l {{.ListName}}) Len() int {\n\treturn len(l)\n}\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	templates, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	methods := templates.Methods()
+	if len(methods) != len(Methods())+1 || methods[len(methods)-1].Name != "Len" {
+		t.Fatal(methods)
+	}
+	spec := Spec{Package: "main", Types: map[string]string{"int": "int"}, Methods: []string{"Filter", "Len"}, Templates: templates}
+	src, err := Generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "that keeps every member") || !strings.Contains(string(src), "func (l intList) Len() int {") {
+		t.Error(string(src))
+	}
+
+	// the templates only change the Specs generated with them
+	spec.Methods, spec.Templates = []string{"Filter"}, Templates{}
+	if src, err = Generate(spec); err != nil || strings.Contains(string(src), "that keeps every member") {
+		t.Error(string(src), err)
+	}
+	for _, method := range Methods() {
+		if method.Name == "Len" {
+			t.Fail()
+		}
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the template fails for the lists of ints only, after it is loaded
+	content := "{{if eq .TypeName \"int\"}}{{.Missing}}{{end}}\nfunc (l {{.ListName}}) Len() int {\n\treturn len(l)\n}\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "Len.tmpl"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	templates, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Generate(Spec{Package: "main", Types: map[string]string{"int": "int", "string": "string"}, Methods: []string{"Len"}, Templates: templates})
+	if err == nil || !strings.HasPrefix(err.Error(), "template 'Len': ") {
+		t.Error(err)
+	}
+}
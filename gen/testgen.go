@@ -0,0 +1,906 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// generateTests - generate the tests of the selected methods for the lists of the types, for -with-tests. Every test
+// runs the method on the lists returned by the test cases function of the list, eg: 'intListTestCases', and checks the
+// length of the result, or the result itself when it is known for every type
+func generateTests(packageName string, m map[string]string, selected map[string]string, p plan) string {
+	code := fmt.Sprintf(`package %[1]s
+            `, packageName)
+
+	for _, typeName := range sortedTypes(selected) {
+		listName := strings.TrimPrefix(selected[typeName], "*") + "List"
+		methods := p.methodsOf(listName)
+		tested := p.generators.Filter(func(gen Generator) bool {
+			return methods[gen.name] && gen.test != nil
+		})
+		code += getTestCases(listName, typeName)
+
+		tested.Each(func(gen Generator) {
+			targets := map[string]string{typeName: ""}
+			if gen.needMapToMap {
+				targets = map[string]string{}
+				for k, v := range m {
+					if p.mapsTo(listName, k) {
+						targets[k] = v
+					}
+				}
+				targets[typeName] = ""
+			}
+
+			for _, targetType := range sortedTypes(targets) {
+				if gen.keyed && !p.keyable(targetType) {
+					continue
+				}
+				body := gen.test(listName, typeName, targetType, targets[targetType])
+				if body == "" {
+					continue
+				}
+				name := gen.name + getTestSuffix(targets[targetType])
+
+				code += fmt.Sprintf(`
+            // Test%[1]s%[2]s tests the %[2]s method of %[3]s
+            func Test%[1]s%[2]s(t *testing.T) {
+                for _, l := range %[3]sTestCases() {
+                    %[4]s
+                }
+            }
+            `, strings.Title(listName), name, listName, body)
+			}
+		})
+
+		for _, field := range p.fields[listName] {
+			code += fmt.Sprintf(`
+            // Test%[1]sPluck%[2]s tests the Pluck%[2]s method of %[3]s
+            func Test%[1]sPluck%[2]s(t *testing.T) {
+                for _, l := range %[3]sTestCases() {
+                    %[4]s
+                }
+            }
+            `, strings.Title(listName), field.Name, listName, getPluckTest(typeName, field))
+		}
+	}
+
+	return code
+}
+
+// testValues - get three literals of a type to fill the lists of the test cases, or three zero values if there is no
+// literal for the type
+func testValues(typeName string) []string {
+	switch typeName {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune":
+		return []string{"1", "2", "3"}
+	case "float32", "float64":
+		return []string{"1.5", "2.5", "3.5"}
+	case "string":
+		return []string{`"a"`, `"b"`, `"c"`}
+	case "bool":
+		return []string{"true", "false", "true"}
+	}
+	return []string{"zero", "zero", "zero"}
+}
+
+func getTestCases(listName, typeName string) string {
+	values := testValues(typeName)
+	zero := ""
+	if values[0] == "zero" {
+		zero = fmt.Sprintf("var zero %s", typeName)
+	}
+
+	return fmt.Sprintf(`
+        // %[1]sTestCases returns the lists which the tests of the methods on %[1]s are run with: an empty list, a list with one member and a list with three members
+        func %[1]sTestCases() []%[1]s {
+            %[2]s
+            return []%[1]s{{}, {%[3]s}, {%[4]s}}
+        }
+        `, listName, zero, values[0], strings.Join(values, ", "))
+}
+
+// getTestSuffix - get the suffix of the method name for a target type, eg: 'Str' for 'MapStr'
+func getTestSuffix(targetTypeName string) string {
+	return strings.Title(strings.TrimPrefix(targetTypeName, "*"))
+}
+
+// getLengthTest - get the test of a method returning a list of the same length as the list, given the call of the
+// method on 'l' with the function 'f'
+func getLengthTest(call, typeName, targetType string) string {
+	return fmt.Sprintf(`f := func(%[2]s) %[3]s {
+                var u %[3]s
+                return u
+            }
+            if result := %[1]s; len(result) != len(l) {
+                t.Errorf("%%v: got %%d members, expected %%d", l, len(result), len(l))
+            }`, call, typeName, targetType)
+}
+
+func getMapTest(listName, typeName, targetType, targetTypeName string) string {
+	suffix := getTestSuffix(targetTypeName)
+	return getLengthTest("l.Map"+suffix+"(f)", typeName, targetType)
+}
+
+func getPMapTest(listName, typeName, targetType, targetTypeName string) string {
+	suffix := getTestSuffix(targetTypeName)
+	return getLengthTest("l.PMap"+suffix+"(f)", typeName, targetType)
+}
+
+func getPMapRateTest(listName, typeName, targetType, targetTypeName string) string {
+	suffix := getTestSuffix(targetTypeName)
+	// the rates which a ticker cannot represent are not throttled
+	return getLengthTest("l.PMapRate"+suffix+"(f, 0)", typeName, targetType) + fmt.Sprintf(`
+            if result := l.PMapRate%[1]s(f, 2000000000); len(result) != len(l) {
+                t.Errorf("%%v: got %%d members, expected %%d", l, len(result), len(l))
+            }`, suffix)
+}
+
+func getMapAsyncTest(listName, typeName, targetType, targetTypeName string) string {
+	suffix := getTestSuffix(targetTypeName)
+	return getLengthTest("l.MapAsync"+suffix+"(f).Wait()", typeName, targetType)
+}
+
+func getPMapTimeoutTest(listName, typeName, targetType, targetTypeName string) string {
+	suffix := getTestSuffix(targetTypeName)
+	return fmt.Sprintf(`result, timedOut := l.PMapTimeout%[1]s(time.Minute, func(%[2]s) %[3]s {
+                var u %[3]s
+                return u
+            })
+            if len(result) != len(l) || timedOut {
+                t.Errorf("%%v: got %%d members and %%v, expected %%d members and false", l, len(result), timedOut, len(l))
+            }`, suffix, typeName, targetType)
+}
+
+func getPMapRetryTest(listName, typeName, targetType, targetTypeName string) string {
+	suffix := getTestSuffix(targetTypeName)
+	return fmt.Sprintf(`result, err := l.PMapRetry%[1]s(func(%[2]s) (%[3]s, error) {
+                var u %[3]s
+                return u, nil
+            }, 1, 0)
+            if len(result) != len(l) || err != nil {
+                t.Errorf("%%v: got %%d members and %%v, expected %%d members and no error", l, len(result), err, len(l))
+            }`, suffix, typeName, targetType)
+}
+
+func getPFlatMapTest(listName, typeName, targetType, targetTypeName string) string {
+	suffix := getTestSuffix(targetTypeName)
+	return fmt.Sprintf(`result := l.PFlatMap%[1]s(func(%[2]s) []%[3]s {
+                return make([]%[3]s, 2)
+            })
+            if len(result) != 2*len(l) {
+                t.Errorf("%%v: got %%d members, expected %%d", l, len(result), 2*len(l))
+            }`, suffix, typeName, targetType)
+}
+
+func getPGroupByTest(listName, typeName, targetType, targetTypeName string) string {
+	suffix := getTestSuffix(targetTypeName)
+	return fmt.Sprintf(`groups := l.PGroupBy%[1]s(func(%[2]s) %[3]s {
+                var u %[3]s
+                return u
+            })
+            members := 0
+            for _, group := range groups {
+                members += len(group)
+            }
+            if members != len(l) || len(groups) > 1 {
+                t.Errorf("%%v: got %%d groups with %%d members, expected at most 1 group with %%d members", l, len(groups), members, len(l))
+            }
+            for _, group := range groups {
+                if !reflect.DeepEqual(group, l) {
+                    t.Errorf("%%v: got the group %%v, expected the members in their order", l, group)
+                }
+            }`, suffix, typeName, targetType)
+}
+
+// getKeepTest - get the test of a method filtering the list with a function, like Filter or TakeWhile. The list is
+// first filtered with a function always returning true, and then with a function always returning false
+func getKeepTest(method, typeName string, keepAll, keepNone string) string {
+	return fmt.Sprintf(`if result := l.%[1]s(func(%[2]s) bool { return true }); len(result) != %[3]s {
+                t.Errorf("%%v: got %%d members when keeping all of them", l, len(result))
+            }
+            if result := l.%[1]s(func(%[2]s) bool { return false }); len(result) != %[4]s {
+                t.Errorf("%%v: got %%d members when keeping none of them", l, len(result))
+            }`, method, typeName, keepAll, keepNone)
+}
+
+func getFilterTest(_, typeName, _, _ string) string {
+	return getKeepTest("Filter", typeName, "len(l)", "0")
+}
+
+func getPFilterTest(_, typeName, _, _ string) string {
+	return getKeepTest("PFilter", typeName, "len(l)", "0")
+}
+
+func getTakeWhileTest(_, typeName, _, _ string) string {
+	return getKeepTest("TakeWhile", typeName, "len(l)", "0")
+}
+
+func getDropWhileTest(_, typeName, _, _ string) string {
+	return fmt.Sprintf(`if result := l.DropWhile(func(%[1]s) bool { return false }); len(result) != len(l) {
+                t.Errorf("%%v: got %%d members when dropping none of them", l, len(result))
+            }
+            if result := l.DropWhile(func(%[1]s) bool { return true }); len(result) != 0 {
+                t.Errorf("%%v: got %%d members when dropping all of them", l, len(result))
+            }`, typeName)
+}
+
+// getSliceTest - get the test of Take or Drop, with the expected length for n = 2
+func getSliceTest(method, expected string) string {
+	return fmt.Sprintf(`expected := %[2]s
+            if expected < 0 {
+                expected = 0
+            } else if expected > len(l) {
+                expected = len(l)
+            }
+            if result := l.%[1]s(2); len(result) != expected {
+                t.Errorf("%%v: got %%d members, expected %%d", l, len(result), expected)
+            }`, method, expected)
+}
+
+func getTakeTest(_, _, _, _ string) string {
+	return getSliceTest("Take", "2")
+}
+
+func getDropTest(_, _, _, _ string) string {
+	return getSliceTest("Drop", "len(l) - 2")
+}
+
+// funcType - whether a type is a function type, judging by the type expression. The functions cannot be compared, and
+// go vet rejects printing them with %v, so their tests compare whether they are nil and print them with %p
+func funcType(typeName string) bool {
+	return strings.HasPrefix(typeName, "func(")
+}
+
+// memberVerb - get the verb printing the members of a type in the messages of the tests: %p for the functions (see
+// funcType), or %v
+func memberVerb(typeName string) string {
+	if funcType(typeName) {
+		return "%p"
+	}
+	return "%v"
+}
+
+// differentMembers - get the condition that two members of a type differ: whether one of them is nil for the functions
+// (see funcType), or whether they are printed differently
+func differentMembers(typeName, a, b string) string {
+	if funcType(typeName) {
+		return fmt.Sprintf("(%s == nil) != (%s == nil)", a, b)
+	}
+	return fmt.Sprintf("fmt.Sprint(%s) != fmt.Sprint(%s)", a, b)
+}
+
+// getFoldTest - get the test of Reduce or ReduceRight with a function always returning the accumulated value, its
+// argument at the index accumulator, so that the result is the initial value
+func getFoldTest(method, typeName string, accumulator int) string {
+	arguments := []string{"_", "_"}
+	arguments[accumulator] = "acc"
+	return fmt.Sprintf(`var initial %[2]s
+            if result := l.%[1]s(initial, func(%[3]s %[2]s) %[2]s { return acc }); %[4]s {
+                t.Errorf("%%v: got %[5]s, expected %[5]s", l, result, initial)
+            }`, method, typeName, strings.Join(arguments, ", "), differentMembers(typeName, "result", "initial"), memberVerb(typeName))
+}
+
+func getReduceTest(_, typeName, _, _ string) string {
+	return getFoldTest("Reduce", typeName, 0)
+}
+
+func getReduceRightTest(_, typeName, _, _ string) string {
+	return getFoldTest("ReduceRight", typeName, 1)
+}
+
+func getEachTest(_, typeName, _, _ string) string {
+	return fmt.Sprintf(`calls := 0
+            if result := l.Each(func(%[1]s) { calls++ }); calls != len(l) || len(result) != len(l) {
+                t.Errorf("%%v: got %%d calls and %%d members, expected %%d", l, calls, len(result), len(l))
+            }`, typeName)
+}
+
+func getEachITest(_, typeName, _, _ string) string {
+	return fmt.Sprintf(`calls := 0
+            l.EachI(func(i int, _ %[1]s) {
+                if i != calls {
+                    t.Errorf("%%v: got index %%d, expected %%d", l, i, calls)
+                }
+                calls++
+            })
+            if calls != len(l) {
+                t.Errorf("%%v: got %%d calls, expected %%d", l, calls, len(l))
+            }`, typeName)
+}
+
+// getPredicateTest - get the test of a method checking the members with a function, like All or PAny, for functions
+// always returning true and always returning false
+func getPredicateTest(method, typeName, whenTrue, whenFalse string) string {
+	return fmt.Sprintf(`if result := l.%[1]s(func(%[2]s) bool { return true }); result != (%[3]s) {
+                t.Errorf("%%v: got %%v when all the members satisfy the function", l, result)
+            }
+            if result := l.%[1]s(func(%[2]s) bool { return false }); result != (%[4]s) {
+                t.Errorf("%%v: got %%v when no member satisfies the function", l, result)
+            }`, method, typeName, whenTrue, whenFalse)
+}
+
+func getAllTest(_, typeName, _, _ string) string {
+	return getPredicateTest("All", typeName, "true", "len(l) == 0")
+}
+
+func getAnyTest(_, typeName, _, _ string) string {
+	return getPredicateTest("Any", typeName, "len(l) > 0", "false")
+}
+
+func getPAllTest(_, typeName, _, _ string) string {
+	return getPredicateTest("PAll", typeName, "true", "len(l) == 0")
+}
+
+func getPAnyTest(_, typeName, _, _ string) string {
+	return getPredicateTest("PAny", typeName, "len(l) > 0", "false")
+}
+
+func getPCountTest(_, typeName, _, _ string) string {
+	return getPredicateTest("PCount", typeName, "len(l)", "0")
+}
+
+func getPSortTest(_, typeName, _, _ string) string {
+	return getStableSortTest("PSort", typeName)
+}
+
+// getStableSortTest - get the test of a method sorting a copy of the list, like PSort, which must keep the order of
+// the members which are equal
+func getStableSortTest(method, typeName string) string {
+	return fmt.Sprintf(`expected := fmt.Sprint(l)
+            if result := l.%[1]s(func(_, _ %[2]s) bool { return false }); fmt.Sprint(result) != expected {
+                t.Errorf("%%v: got %%v, expected the same order since the sort is stable", l, result)
+            }`, method, typeName)
+}
+
+func getToChanTest(_, _, _, _ string) string {
+	return `members := 0
+            for range l.ToChan() {
+                members++
+            }
+            if members != len(l) {
+                t.Errorf("%v: got %d members, expected %d", l, members, len(l))
+            }`
+}
+
+// getTestChannel - get the code sending the members of l to a closed channel 'ch'
+func getTestChannel(typeName string) string {
+	return fmt.Sprintf(`ch := make(chan %[1]s, len(l))
+            for _, member := range l {
+                ch <- member
+            }
+            close(ch)`, typeName)
+}
+
+func getFromChanTest(listName, typeName, _, _ string) string {
+	return getTestChannel(typeName) + fmt.Sprintf(`
+            if result := %[1]sFromChan(ch); len(result) != len(l) {
+                t.Errorf("%%v: got %%d members, expected %%d", l, len(result), len(l))
+            }`, listName)
+}
+
+func getMapChanTest(listName, typeName, targetType, targetTypeName string) string {
+	suffix := getTestSuffix(targetTypeName)
+	return getTestChannel(typeName) + fmt.Sprintf(`
+            members := 0
+            for range %[1]sMapChan%[2]s(ch, func(%[3]s) %[4]s {
+                var u %[4]s
+                return u
+            }) {
+                members++
+            }
+            if members != len(l) {
+                t.Errorf("%%v: got %%d members, expected %%d", l, members, len(l))
+            }`, listName, suffix, typeName, targetType)
+}
+
+func getFilterChanTest(listName, typeName, _, _ string) string {
+	return getTestChannel(typeName) + fmt.Sprintf(`
+            members := 0
+            for range %[1]sFilterChan(ch, func(%[2]s) bool { return true }) {
+                members++
+            }
+            if members != len(l) {
+                t.Errorf("%%v: got %%d members, expected %%d", l, members, len(l))
+            }`, listName, typeName)
+}
+
+func getPipelineTest(listName, typeName, targetType, targetTypeName string) string {
+	suffix := getTestSuffix(targetTypeName)
+	return getLengthTest(fmt.Sprintf("l.Pipeline().Filter(func(%s) bool { return true }).Map%s(f).Collect()", typeName, suffix), typeName, targetType)
+}
+
+// getMapFilteredTest - get the test of FilterMap or PFilterMap, which are only generated for the other types
+func getMapFilteredTest(method, listName, typeName, targetType, targetTypeName string) string {
+	if targetTypeName == "" {
+		return ""
+	}
+	suffix := getTestSuffix(targetTypeName)
+	return getLengthTest(fmt.Sprintf("l.%s%s(f, func(%s) bool { return true })", method, suffix, typeName), typeName, targetType)
+}
+
+func getFilterMapTest(listName, typeName, targetType, targetTypeName string) string {
+	return getMapFilteredTest("FilterMap", listName, typeName, targetType, targetTypeName)
+}
+
+func getPFilterMapTest(listName, typeName, targetType, targetTypeName string) string {
+	return getMapFilteredTest("PFilterMap", listName, typeName, targetType, targetTypeName)
+}
+
+func getToSetTest(listName, typeName, _, _ string) string {
+	return `s := l.ToSet()
+            if s.Len() == 0 && len(l) > 0 || s.Len() > len(l) || len(s.ToList()) != s.Len() {
+                t.Errorf("%v: got %d members in the set and %d in its list", l, s.Len(), len(s.ToList()))
+            }
+            for _, member := range l {
+                if !s.Contains(member) {
+                    t.Errorf("%v: the set does not contain %v", l, member)
+                }
+            }
+            if s.Union(s).Len() != s.Len() || s.Intersection(s).Len() != s.Len() || s.Difference(s).Len() != 0 {
+                t.Errorf("%v: the union, the intersection and the difference of the set with itself are not valid", l)
+            }`
+}
+
+func getToStackTest(_, _, _, _ string) string {
+	return `s := l.ToStack()
+            s.Push(l...)
+            if s.Len() != 2*len(l) || len(s.ToList()) != 2*len(l) {
+                t.Errorf("%v: got %d members in the stack, expected %d", l, s.Len(), 2*len(l))
+            }
+            for i := len(l) - 1; i >= 0; i-- {
+                if _, ok := s.Peek(); !ok {
+                    t.Errorf("%v: the stack is empty", l)
+                }
+                if _, ok := s.Pop(); !ok {
+                    t.Errorf("%v: the stack is empty", l)
+                }
+            }
+            if s.Len() != len(l) {
+                t.Errorf("%v: got %d members in the stack after popping, expected %d", l, s.Len(), len(l))
+            }`
+}
+
+func getToImmutableTest(_, typeName, _, _ string) string {
+	return fmt.Sprintf(`im := l.ToImmutable()
+            appended := im.Append(l...)
+            if im.Len() != len(l) || appended.Len() != 2*len(l) || len(appended.Insert(len(l), l...).ToList()) != 3*len(l) {
+                t.Errorf("%%v: got %%d and %%d members, expected %%d and %%d", l, im.Len(), appended.Len(), len(l), 2*len(l))
+            }
+            for i := range l {
+                if removed := appended.Remove(i); removed.Len() != 2*len(l)-1 || appended.Len() != 2*len(l) {
+                    t.Errorf("%%v: got %%d members after removing one, expected %%d", l, removed.Len(), 2*len(l)-1)
+                }
+                if set := im.Set(i, l[i]); fmt.Sprint(set.ToList()) != fmt.Sprint(im.ToList()) || %[1]s {
+                    t.Errorf("%%v: got %%v after setting the member %%d", l, set.ToList(), i)
+                }
+            }
+            if sliced := appended.Slice(len(l), 2*len(l)); fmt.Sprint(sliced.ToList()) != fmt.Sprint(l) {
+                t.Errorf("%%v: got %%v for the second half", l, sliced.ToList())
+            }`, differentMembers(typeName, "set.At(i)", "l[i]"))
+}
+
+func getToSortedTest(_, typeName, _, _ string) string {
+	return fmt.Sprintf(`s := l.ToSorted(func(_, _ %[1]s) bool { return false })
+            s.Insert(l...)
+            if s.Len() != 2*len(l) || len(s.ToList()) != 2*len(l) {
+                t.Errorf("%%v: got %%d members, expected %%d", l, s.Len(), 2*len(l))
+            }
+            for _, member := range l {
+                if !s.Contains(member) || len(s.Range(member, member)) != s.Len() {
+                    t.Errorf("%%v: expected to contain %[2]s, equal to every member", l, member)
+                }
+            }
+            for range l {
+                if !s.Remove(l[0]) {
+                    t.Errorf("%%v: expected to remove %[2]s", l, l[0])
+                }
+            }
+            if s.Len() != len(l) {
+                t.Errorf("%%v: got %%d members after removing %%d members, expected %%d", l, s.Len(), len(l), len(l))
+            }`, typeName, memberVerb(typeName))
+}
+
+func getToQueueTest(_, _, _, _ string) string {
+	return `q := l.ToQueue()
+            q.Enqueue(l...)
+            if q.Len() != 2*len(l) || len(q.ToList()) != 2*len(l) {
+                t.Errorf("%v: got %d members in the queue, expected %d", l, q.Len(), 2*len(l))
+            }
+            for range l {
+                if _, ok := q.Peek(); !ok {
+                    t.Errorf("%v: the queue is empty", l)
+                }
+                if _, ok := q.Dequeue(); !ok {
+                    t.Errorf("%v: the queue is empty", l)
+                }
+            }
+            if q.Len() != len(l) {
+                t.Errorf("%v: got %d members in the queue after dequeuing, expected %d", l, q.Len(), len(l))
+            }`
+}
+
+func getToDequeTest(_, typeName, _, _ string) string {
+	return fmt.Sprintf(`d := l.ToDeque()
+            for _, member := range l {
+                d.PushFront(member)
+                d.PushBack(member)
+            }
+            if d.Len() != 3*len(l) || len(d.ToList()) != 3*len(l) {
+                t.Errorf("%%v: got %%d members in the deque, expected %%d", l, d.Len(), 3*len(l))
+            }
+            for i := range l {
+                if !reflect.DeepEqual(d.At(len(l)+i), l[i]) {
+                    t.Errorf("%%v: got %[1]s at %%d, expected %[1]s", l, d.At(len(l)+i), len(l)+i, l[i])
+                }
+            }
+            for range l {
+                _, front := d.PopFront()
+                _, back := d.PopBack()
+                if !front || !back {
+                    t.Errorf("%%v: the deque is empty", l)
+                }
+            }
+            if d.Len() != len(l) {
+                t.Errorf("%%v: got %%d members in the deque after popping, expected %%d", l, d.Len(), len(l))
+            }`, memberVerb(typeName))
+}
+
+func getFindTest(_, typeName, _, _ string) string {
+	return fmt.Sprintf(`if o := l.Find(func(%[1]s) bool { return true }); o.IsSome() != (len(l) > 0) {
+                t.Errorf("%%v: got %%v when finding any member", l, o.IsSome())
+            }
+            if o := l.Find(func(%[1]s) bool { return false }); o.IsSome() {
+                t.Errorf("%%v: found a member when finding none of them", l)
+            }`, typeName)
+}
+
+func getFirstTest(_, typeName, _, _ string) string {
+	return getEndTest("First", typeName, "0")
+}
+
+func getLastTest(_, typeName, _, _ string) string {
+	return getEndTest("Last", typeName, "len(l)-1")
+}
+
+// getEndTest - get the test of First or Last, with the index of the member they return
+func getEndTest(method, typeName, index string) string {
+	return fmt.Sprintf(`o := l.%[1]s()
+            if o.IsSome() != (len(l) > 0) {
+                t.Errorf("%%v: got %%v from %[1]s", l, o.IsSome())
+            }
+            if len(l) > 0 {
+                if member, _ := o.Get(); !reflect.DeepEqual(member, l[%[2]s]) {
+                    t.Errorf("%%v: got %[3]s from %[1]s, expected %[3]s", l, member, l[%[2]s])
+                }
+            }`, method, index, memberVerb(typeName))
+}
+
+func getMapResultTest(_, typeName, targetType, targetTypeName string) string {
+	return fmt.Sprintf(`failed := errors.New("failed")
+            results := l.MapResult%[1]s(func(%[2]s) (%[3]s, error) {
+                var u %[3]s
+                return u, failed
+            })
+            if len(results) != len(l) {
+                t.Errorf("%%v: got %%d results, expected %%d", l, len(results), len(l))
+            }
+            for _, result := range results {
+                if result.IsOk() || result.Err() != failed || result.Map(func(u %[3]s) %[3]s { return u }).Err() != failed {
+                    t.Errorf("%%v: got the error %%v, expected %%v", l, result.Err(), failed)
+                }
+            }`, getTestSuffix(targetTypeName), typeName, targetType)
+}
+
+func getIterTest(listName, typeName, targetType, targetTypeName string) string {
+	return fmt.Sprintf(`it := l.Iter().Filter(func(%[2]s) bool { return true }).Map%[1]s(func(%[2]s) %[3]s {
+                var u %[3]s
+                return u
+            })
+            n := 0
+            for _, ok := it.Next(); ok; _, ok = it.Next() {
+                n++
+            }
+            if n != len(l) {
+                t.Errorf("%%v: got %%d members from the iterator, expected %%d", l, n, len(l))
+            }
+            taken := 2
+            if len(l) < taken {
+                taken = len(l)
+            }
+            if result := l.CollectFromIter(l.Iter().Take(2)); len(result) != len(l)+taken {
+                t.Errorf("%%v: got %%d members when collecting 2 members, expected %%d", l, len(result), len(l)+taken)
+            }`, getTestSuffix(targetTypeName), typeName, targetType)
+}
+
+func getValuesTest(_, _, _, _ string) string {
+	return `members := 0
+            for range l.Values() {
+                members++
+            }
+            if members != len(l) {
+                t.Errorf("%v: got %d members, expected %d", l, members, len(l))
+            }
+            for range l.Values() {
+                break
+            }`
+}
+
+func getEnumeratedTest(_, typeName, _, _ string) string {
+	return fmt.Sprintf(`for i, member := range l.Enumerated() {
+                if !reflect.DeepEqual(member, l[i]) {
+                    t.Errorf("%%v: got %[1]s at %%d, expected %[1]s", l, member, i, l[i])
+                }
+            }`, memberVerb(typeName))
+}
+
+func getFromSeqTest(listName, typeName, _, _ string) string {
+	return fmt.Sprintf(`seq := func(yield func(%[2]s) bool) {
+                for _, member := range l {
+                    if !yield(member) {
+                        return
+                    }
+                }
+            }
+            if result := %[1]sFromSeq(seq); !reflect.DeepEqual(result, append(%[1]s{}, l...)) {
+                t.Errorf("%%v: got %%v", l, result)
+            }`, listName, typeName)
+}
+
+func getFlattenTest(listName, typeName, _, _ string) string {
+	return fmt.Sprintf(`total := 0
+            for _, member := range l {
+                total += len(member)
+            }
+            if result := l.Flatten(); len(result) != total {
+                t.Errorf("%%v: got %%d members, expected %%d", l, len(result), total)
+            }
+            nested := %[1]s{make(%[2]s, 2), make(%[2]s, 1)}
+            if result := nested.Flatten(); len(result) != 3 {
+                t.Errorf("%%v: got %%d members, expected 3", nested, len(result))
+            }`, listName, typeName)
+}
+
+func getFlatMapTest(listName, typeName, targetType, targetTypeName string) string {
+	return fmt.Sprintf(`result := l.FlatMap%[1]s(func(%[2]s) []%[3]s {
+                return make([]%[3]s, 2)
+            })
+            if len(result) != 2*len(l) {
+                t.Errorf("%%v: got %%d members, expected %%d", l, len(result), 2*len(l))
+            }`, getTestSuffix(targetTypeName), typeName, targetType)
+}
+
+func getContainsTest(listName, typeName, _, _ string) string {
+	return fmt.Sprintf(`for _, member := range l {
+                if !l.Contains(member) {
+                    t.Errorf("%%v: got false for %%v, expected true", l, member)
+                }
+            }
+            var missing %[1]s
+            if len(l) == 0 && l.Contains(missing) {
+                t.Errorf("%%v: got true for %%v, expected false", l, missing)
+            }`, typeName)
+}
+
+func getUniqueTest(listName, _, _, _ string) string {
+	return fmt.Sprintf(`members := len(l)
+            doubled := append(append(%[1]s{}, l...), l...)
+            unique := doubled.Unique()
+            if result := l.Unique(); !reflect.DeepEqual(result, unique) || len(result) > members {
+                t.Errorf("%%v: got %%v, and %%v for the list repeated twice", l, result, unique)
+            }`, listName)
+}
+
+func getUniqueByTest(listName, typeName, targetType, targetTypeName string) string {
+	return fmt.Sprintf(`expected := len(l)
+            if expected > 1 {
+                expected = 1
+            }
+            result := l.UniqueBy%[1]s(func(%[2]s) %[3]s {
+                var u %[3]s
+                return u
+            })
+            if len(result) != expected {
+                t.Errorf("%%v: got %%d members with the same key, expected %%d", l, len(result), expected)
+            }`, getTestSuffix(targetTypeName), typeName, targetType)
+}
+
+func getSortByTest(_, typeName, _, _ string) string {
+	return getStableSortTest("SortBy", typeName)
+}
+
+func getFilterInPlaceTest(listName, typeName, _, _ string) string {
+	return fmt.Sprintf(`members := append(%[1]s{}, l...)
+            if result := members.FilterInPlace(func(_ %[2]s) bool { return true }); fmt.Sprint(result) != fmt.Sprint(l) {
+                t.Errorf("%%v: got %%v, expected all the members", l, result)
+            }
+            if result := members.FilterInPlace(func(_ %[2]s) bool { return false }); len(result) != 0 {
+                t.Errorf("%%v: got %%v, expected no member", l, result)
+            }`, listName, typeName)
+}
+
+func getMapInPlaceTest(listName, typeName, _, _ string) string {
+	return fmt.Sprintf(`members := append(%[1]s{}, l...)
+            if result := members.MapInPlace(func(t %[2]s) %[2]s { return t }); len(result) != len(l) || fmt.Sprint(members) != fmt.Sprint(l) {
+                t.Errorf("%%v: got %%v, expected the same members", l, result)
+            }`, listName, typeName)
+}
+
+func getReverseInPlaceTest(listName, typeName, _, _ string) string {
+	return fmt.Sprintf(`members := append(%[1]s{}, l...)
+            members.ReverseInPlace()
+            if len(l) > 0 && %[2]s {
+                t.Errorf("%%v: got %%v, expected the last member first", l, members)
+            }
+            if members.ReverseInPlace(); fmt.Sprint(members) != fmt.Sprint(l) {
+                t.Errorf("%%v: got %%v after reversing twice", l, members)
+            }`, listName, differentMembers(typeName, "members[0]", "l[len(l)-1]"))
+}
+
+func getSortInPlaceTest(_, typeName, _, _ string) string {
+	return getStableSortTest("SortInPlace", typeName)
+}
+
+func getSampleWeightedTest(_, typeName, _, _ string) string {
+	return fmt.Sprintf(`r := rand.New(rand.NewSource(1))
+            if member, ok := l.SampleWeighted(r, func(_ %[1]s) float64 { return 0 }); ok {
+                t.Errorf("%%v: got %[2]s, expected no member without weights", l, member)
+            }
+            for last := range l {
+                i := -1
+                member, ok := l.SampleWeighted(r, func(_ %[1]s) float64 {
+                    if i++; i == last {
+                        return 1
+                    }
+                    return 0
+                })
+                if !ok || !reflect.DeepEqual(member, l[last]) {
+                    t.Errorf("%%v: got %[2]s, expected the member %%d, the only one with a weight", l, member, last)
+                }
+            }`, typeName, memberVerb(typeName))
+}
+
+func getCompactNilTest(_, _, _, _ string) string {
+	return `expected := len(l)
+            for _, member := range l {
+                if member == nil {
+                    expected--
+                }
+            }
+            if result := l.CompactNil(); len(result) != expected {
+                t.Errorf("%v: got %d members, expected %d", l, len(result), expected)
+            }`
+}
+
+func getDerefOrTest(listName, typeName, _, _ string) string {
+	return fmt.Sprintf(`var def %[2]s
+            if result := l.DerefOr(def); len(result) != len(l) {
+                t.Errorf("%%v: got %%d values, expected %%d", l, len(result), len(l))
+            }
+            members := %[1]s{nil, new(%[2]s)}
+            if result := members.DerefOr(def); len(result) != 2 || !reflect.DeepEqual(result[0], def) {
+                t.Errorf("%%v: got %%v", members, result)
+            }`, listName, strings.TrimPrefix(typeName, "*"))
+}
+
+func getPluckTest(typeName string, field Field) string {
+	// the nil members of the lists of pointers get the zero value
+	expected := "expected := member." + field.Name
+	if strings.HasPrefix(typeName, "*") {
+		expected = fmt.Sprintf(`var expected %[2]s
+                if member != nil {
+                    expected = member.%[1]s
+                }`, field.Name, field.Type)
+	}
+	return fmt.Sprintf(`result := l.Pluck%[1]s()
+            if len(result) != len(l) {
+                t.Errorf("%%v: got %%d fields, expected %%d", l, len(result), len(l))
+                continue
+            }
+            for i, member := range l {
+                %[2]s
+                if !reflect.DeepEqual(result[i], expected) {
+                    t.Errorf("%%v: got another %[1]s field at %%d", l, i)
+                }
+            }`, field.Name, expected)
+}
+
+func getSumTest(listName, _, _, _ string) string {
+	return fmt.Sprintf(`doubled := append(append(%[1]s{}, l...), l...)
+            if result := l.Sum(); doubled.Sum() != result+result || len(l) == 0 && result != 0 {
+                t.Errorf("%%v: got %%v, and %%v for the list repeated twice", l, result, doubled.Sum())
+            }`, listName)
+}
+
+func getAverageTest(listName, _, _, _ string) string {
+	return fmt.Sprintf(`doubled := append(append(%[1]s{}, l...), l...)
+            if result := l.Average(); doubled.Average() != result || len(l) == 0 && result != 0 {
+                t.Errorf("%%v: got %%v, and %%v for the list repeated twice", l, result, doubled.Average())
+            }`, listName)
+}
+
+// getExtremeTest - get the test of Min (with '<') or Max (with '>'): the result is a member, and no member is beyond it
+func getExtremeTest(method, operator string) string {
+	return fmt.Sprintf(`result, ok := l.%[1]s()
+            if ok != (len(l) > 0) {
+                t.Errorf("%%v: got %%v, expected %%v", l, ok, len(l) > 0)
+            }
+            found := !ok
+            for _, member := range l {
+                if member %[2]s result {
+                    t.Errorf("%%v: got %%v, but %%v is beyond it", l, result, member)
+                }
+                found = found || member == result
+            }
+            if !found {
+                t.Errorf("%%v: got %%v, which is not a member", l, result)
+            }`, method, operator)
+}
+
+func getMinTest(_, _, _, _ string) string {
+	return getExtremeTest("Min", "<")
+}
+
+func getMaxTest(_, _, _, _ string) string {
+	return getExtremeTest("Max", ">")
+}
+
+// getStringMapTest - get the test of TrimSpaceAll, ToLowerAll or ToUpperAll: the result has the same length, and is
+// unchanged by the method applied again
+func getStringMapTest(method string) string {
+	return fmt.Sprintf(`members := l.%[1]s()
+            if len(members) != len(l) || fmt.Sprint(members.%[1]s()) != fmt.Sprint(members) {
+                t.Errorf("%%v: got %%v, which %[1]s changes again", l, members)
+            }`, method)
+}
+
+func getTrimSpaceAllTest(_, _, _, _ string) string {
+	return getStringMapTest("TrimSpaceAll")
+}
+
+func getToLowerAllTest(_, _, _, _ string) string {
+	return getStringMapTest("ToLowerAll")
+}
+
+func getToUpperAllTest(_, _, _, _ string) string {
+	return getStringMapTest("ToUpperAll")
+}
+
+func getNonEmptyTest(listName, _, _, _ string) string {
+	return fmt.Sprintf(`members := append(%[1]s{""}, l...)
+            expected := 0
+            for _, member := range l {
+                if member != "" {
+                    expected++
+                }
+            }
+            if result := members.NonEmpty(); len(result) != expected {
+                t.Errorf("%%v: got %%d members, expected %%d", members, len(result), expected)
+            }`, listName)
+}
+
+func getJoinNonEmptyTest(listName, _, _, _ string) string {
+	return fmt.Sprintf(`members := append(%[1]s{""}, l...)
+            if result := members.JoinNonEmpty(","); result != l.JoinNonEmpty(",") || strings.HasPrefix(result, ",") || strings.HasSuffix(result, ",") {
+                t.Errorf("%%v: got %%q, expected the empty members left out", members, result)
+            }`, listName)
+}
+
+func getSortedTest(_, _, _, _ string) string {
+	return `members := len(l)
+            result := l.Sort()
+            if len(result) != members || !sort.SliceIsSorted(result, func(i, j int) bool { return result[i] < result[j] }) {
+                t.Errorf("got %v, expected the %d members sorted", result, members)
+            }`
+}
+
+func getZipTest(listName, _, _, targetTypeName string) string {
+	targetListName := strings.TrimPrefix(targetTypeName, "*") + "List"
+	if targetTypeName == "" {
+		targetListName = listName
+	}
+	return fmt.Sprintf(`pairs := l.Zip%[1]s(make(%[2]s, len(l)+1))
+            if len(pairs) != len(l) || fmt.Sprint(pairs.Firsts()) != fmt.Sprint(l) || len(pairs.Seconds()) != len(l) {
+                t.Errorf("%%v: got %%v, expected %%d pairs of the members", l, pairs, len(l))
+            }`, getTestSuffix(targetTypeName), targetListName)
+}
@@ -0,0 +1,59 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTestValues(t *testing.T) {
+	if strings.Join(testValues("int"), ",") != "1,2,3" || strings.Join(testValues("string"), ",") != `"a","b","c"` {
+		t.Fail()
+	}
+	if strings.Join(testValues("*point"), ",") != "zero,zero,zero" {
+		t.Fail()
+	}
+}
+
+func TestGenerateTests(t *testing.T) {
+	m := map[string]string{"int": "int", "string": "Str"}
+	src := f(generateTests("main", m, map[string]string{"int": "int"}, planOf("Map,Filter,Reduce")))
+
+	for _, name := range []string{"func intListTestCases() []intList", "func TestIntListMap(", "func TestIntListMapStr(", "func TestIntListFilter(", "func TestIntListReduce("} {
+		if !strings.Contains(src, name) {
+			t.Errorf("missing %s", name)
+		}
+	}
+	if strings.Contains(src, "TestStrList") || strings.Contains(src, "TestIntListPMap") {
+		t.Fail()
+	}
+
+	checkGolden(t, "TakeTests", generateTests("main", map[string]string{"*point": "*point"}, map[string]string{"*point": "*point"}, planOf("Take")))
+}
+
+func TestGenerateTestsPrefix(t *testing.T) {
+	tests, err := GenerateTests(Spec{Package: "main", Types: map[string]string{"int": "int", "[]int": "ints"}, Methods: []string{"Unique", "Sum", "Flatten", "Zip"}, Prefix: "F"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"doubled.FUnique()", "doubled.FSum()", "nested.FFlatten()", "pairs.Firsts()"} {
+		if !strings.Contains(string(tests), expected) {
+			t.Error(expected)
+		}
+	}
+}
+
+func TestGenerateTestsFunctions(t *testing.T) {
+	// go vet rejects printing the functions with %v, so the tests of the lists of functions compare whether they are nil
+	src, err := GenerateTests(Spec{Package: "main", Types: map[string]string{"func(int) int": "funcs"}, Methods: []string{"Reduce", "ReduceRight", "SampleWeighted", "ReverseInPlace"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(result == nil) != (initial == nil)", "got %p, expected %p", "(members[0] == nil) != (l[len(l)-1] == nil)"} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("missing %s in:\n%s", expected, src)
+		}
+	}
+	if strings.Contains(string(src), "fmt.Sprint(result)") {
+		t.Error(string(src))
+	}
+}
@@ -0,0 +1,96 @@
+package gen
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGeneratedCodeVet - run go vet over the code generated for representative element types, including the types
+// named like the variables of the generated methods and the functions, the maps, the channels and the interfaces, with
+// every method and the main options, and its tests, examples, benchmarks, fuzz tests and property tests
+func TestGeneratedCodeVet(t *testing.T) {
+	if testing.Short() {
+		t.Skip("go vet is not run in short mode")
+	}
+	goCommand, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+
+	// the code is vetted with the Go version of the toolchain, without the methods of -seq before Go 1.23, since they
+	// need the iter package
+	output, err := exec.Command(goCommand, "env", "GOVERSION").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	minor := 18
+	fmt.Sscanf(strings.TrimSpace(string(output)), "go1.%d", &minor)
+	methods := []string{}
+	for _, method := range Methods() {
+		if method.OptIn != "seq" || minor >= 23 {
+			methods = append(methods, method.Name)
+		}
+	}
+	types := map[string]string{
+		"int": "int", "string": "string", "float64": "float64", "point": "point", "*point": "pp", "t": "t", "[]f": "fs",
+		"func(int) int": "funcs", "map[string]int": "counts", "chan int": "chans", "error": "errs",
+	}
+	specs := map[string]Spec{
+		"default":  {},
+		"all":      {Methods: methods, Equality: map[string]Equality{"ppList": {Eq: "samePoint"}}},
+		"pointer":  {Methods: methods, Pointer: true, Safe: true, Prefix: "F"},
+		"generics": {Generics: true, Grow: true, Copy: true},
+		"chunked":  {Chunked: true, TwoPass: true, Deref: true},
+		"pooled":   {Pooled: true, Maps: map[string]string{"map[string]t": "tIndex"}, Arrays: map[string]string{"[4]point": "quad"}, Concurrent: true},
+	}
+
+	for name, spec := range specs {
+		dir, err := ioutil.TempDir("", "fungen")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		spec.Package, spec.Types = "models", types
+		files := map[string]string{
+			"go.mod":    fmt.Sprintf("module models\n\ngo 1.%d\n", minor),
+			"models.go": "package models\n\ntype point struct{ x, y int }\n\ntype t struct{ name string }\n\ntype f int\n\nfunc samePoint(a, b *point) bool { return *a == *b }\n",
+		}
+		generated := map[string]func(Spec) ([]byte, error){
+			"fungen_auto.go":               Generate,
+			"fungen_auto_test.go":          GenerateTests,
+			"fungen_auto_example_test.go":  GenerateExamples,
+			"fungen_auto_bench_test.go":    GenerateBenchmarks,
+			"fungen_auto_fuzz_test.go":     GenerateFuzzTests,
+			"fungen_auto_property_test.go": GeneratePropertyTests,
+			"fungen_auto_generics.go":      GenerateGenerics,
+		}
+		for filename, generate := range generated {
+			if filename == "fungen_auto_generics.go" && !spec.Generics {
+				continue
+			}
+			src, err := generate(spec)
+			if err != nil {
+				t.Fatalf("%s: %s: %s", name, filename, err)
+			}
+			files[filename] = string(src)
+		}
+		for filename, src := range files {
+			if err := ioutil.WriteFile(filepath.Join(dir, filename), []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		cmd := exec.Command(goCommand, "vet", ".")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOTOOLCHAIN=local", "GOFLAGS=")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("%s: go vet: %s\n%s", name, err, output)
+		}
+	}
+}
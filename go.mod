@@ -0,0 +1,3 @@
+module github.com/kulshekhar/fungen
+
+go 1.18
@@ -0,0 +1,5 @@
+package render
+
+// Files and Templates - the embedded templates and the parsed ones, for the tests of the package render_test, which
+// imports the packages parsing the templates
+var Files, Templates = files, templates
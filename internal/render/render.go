@@ -0,0 +1,93 @@
+// Package render - execute the templates of the code generated by fungen, embedded from templates/<name>.tmpl, and
+// check that the generated declarations parse before they are added to a file. A template which fails panics with an
+// Error, which the generation recovers and returns (see Recover), since the templates are executed deep in the
+// generators of the methods
+package render
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+)
+
+// funcs - the functions which the templates of the generated code can call, like the templates of the -templates
+// directory
+var funcs = template.FuncMap{"title": strings.Title}
+
+// files - the templates of the generated code, in templates/<name>.tmpl, eg: templates/Filter.tmpl
+//
+//go:embed templates/*.tmpl
+var files embed.FS
+
+// templates - the templates of the generated code by name, parsed by Template
+var templates = map[string]*template.Template{}
+
+// Template - parse the template templates/<name>.tmpl of the generated code. The templates are parsed when the
+// packages using them are initialized, so that a template which cannot be parsed fails every run and every test. The
+// final newline of the file is not part of the template, so that the fragments of code can end at the end of a line
+func Template(name string) *template.Template {
+	text, err := files.ReadFile("templates/" + name + ".tmpl")
+	if err != nil {
+		panic(err)
+	}
+	tmpl := template.Must(template.New(name).Funcs(funcs).Parse(strings.TrimSuffix(string(text), "\n")))
+	templates[name] = tmpl
+	return tmpl
+}
+
+// Error - the failure of a template: the template cannot be executed with its data, or the code it generates does not
+// parse
+type Error struct {
+	// Template - the name of the template, eg: 'Filter'
+	Template string
+	// Err - the error of the execution or of the parsing
+	Err error
+	// Code - the code which does not parse, or nothing
+	Code string
+}
+
+func (e Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("template '%s' generates code which does not parse: %s\n%s", e.Template, e.Err, e.Code)
+	}
+	return fmt.Sprintf("template '%s': %s", e.Template, e.Err)
+}
+
+// Recover - recover the Error of a template failing in the function deferring it, and set err to it. The other panics
+// are not recovered
+func Recover(err *error) {
+	if r := recover(); r != nil {
+		failure, ok := r.(Error)
+		if !ok {
+			panic(r)
+		}
+		*err = failure
+	}
+}
+
+// Execute - execute a template of the generated code with its data, eg: a gen.TemplateData. It panics with an Error if
+// the template fails
+func Execute(tmpl *template.Template, data interface{}) string {
+	var code bytes.Buffer
+	if err := tmpl.Execute(&code, data); err != nil {
+		panic(Error{Template: tmpl.Name(), Err: err})
+	}
+	return code.String()
+}
+
+// Declarations - execute a template of the generated declarations with its data, and check that the code parses and
+// format it, so that a typo in a template fails with the name of the template instead of generating code which does
+// not compile, and that the whitespace of the template is not in the generated code. It panics with an Error if the
+// template fails
+func Declarations(tmpl *template.Template, data interface{}) string {
+	const clause = "package gen\n"
+	code := Execute(tmpl, data)
+	formatted, err := format.Source([]byte(clause + code))
+	if err != nil {
+		panic(Error{Template: tmpl.Name(), Err: err, Code: code})
+	}
+	return "\n" + strings.TrimLeft(strings.TrimPrefix(string(formatted), clause), "\n")
+}
@@ -0,0 +1,45 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestRender(t *testing.T) {
+	tmpl := template.Must(template.New("Test").Funcs(funcs).Parse(`
+        // {{title .ListName}}Len is a method on {{.ListName}} that returns its length
+        func (l {{.ListName}}) Len() int {
+            return len(l)
+        }
+        `))
+	code := Declarations(tmpl, struct{ ListName string }{"intList"})
+	expected := "\n// IntListLen is a method on intList that returns its length\nfunc (l intList) Len() int {\n\treturn len(l)\n}\n"
+	if code != expected {
+		t.Errorf("%q", code)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tmpl := template.Must(template.New("Test").Parse("for i := range {{.ListName}} {"))
+	if code := Execute(tmpl, struct{ ListName string }{"l"}); code != "for i := range l {" {
+		t.Errorf("%q", code)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	declarations := func(text string) (code string, err error) {
+		defer Recover(&err)
+		return Declarations(template.Must(template.New("Test").Parse(text)), struct{ ListName string }{"l"}), nil
+	}
+
+	if _, err := declarations("func (l {{.ListName}}) Len() int {"); err == nil || !strings.HasPrefix(err.Error(), "template 'Test' generates code which does not parse: ") {
+		t.Error(err)
+	}
+	if _, err := declarations("{{.Missing}}"); err == nil || !strings.HasPrefix(err.Error(), "template 'Test': ") {
+		t.Error(err)
+	}
+	if code, err := declarations("type {{.ListName}} []int"); err != nil || code != "\ntype l []int\n" {
+		t.Error(code, err)
+	}
+}
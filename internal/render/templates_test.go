@@ -0,0 +1,27 @@
+package render_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	_ "github.com/kulshekhar/fungen/gen"
+	"github.com/kulshekhar/fungen/internal/render"
+)
+
+// TestTemplateFiles - every file of the templates directory is the template of some code generated by gen
+func TestTemplateFiles(t *testing.T) {
+	filenames, err := fs.Glob(render.Files, "templates/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range filenames {
+		name := strings.TrimSuffix(strings.TrimPrefix(filename, "templates/"), ".tmpl")
+		if render.Templates[name] == nil {
+			t.Errorf("%s is not used", filename)
+		}
+	}
+	if len(filenames) != len(render.Templates) {
+		t.Errorf("%d files for %d templates", len(filenames), len(render.Templates))
+	}
+}
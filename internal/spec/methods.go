@@ -0,0 +1,57 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+// Methods - get selected methods from -methods option, among the methods given (eg: gen.Methods()), or return all the
+// methods given except the opt-in ones
+func Methods(methodsStr string, methods []gen.Method) (map[string]bool, error) {
+	result := map[string]bool{}
+	if methodsStr == "" {
+		for _, method := range methods {
+			if method.OptIn == "" {
+				result[method.Name] = true
+			}
+		}
+		return result, nil
+	}
+
+	validMethods := map[string]bool{}
+	for _, method := range methods {
+		validMethods[method.Name] = true
+	}
+
+	for _, method := range strings.Split(methodsStr, ",") {
+		if _, ok := validMethods[method]; !ok {
+			return nil, fmt.Errorf("unknown method '%s' in -methods", method)
+		}
+		result[method] = true
+	}
+
+	return result, nil
+}
+
+// Exclude - remove the methods given with the -exclude option, among the methods given (eg: gen.Methods()), from the
+// selected methods
+func Exclude(methodsMap map[string]bool, excludeStr string, methods []gen.Method) error {
+	if excludeStr == "" {
+		return nil
+	}
+
+	validMethods := map[string]bool{}
+	for _, method := range methods {
+		validMethods[method.Name] = true
+	}
+
+	for _, method := range strings.Split(excludeStr, ",") {
+		if _, ok := validMethods[method]; !ok {
+			return fmt.Errorf("unknown method '%s' in -exclude", method)
+		}
+		delete(methodsMap, method)
+	}
+	return nil
+}
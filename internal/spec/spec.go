@@ -0,0 +1,368 @@
+// Package spec - parse the -types directive of fungen, eg: 'int:I[Map,Filter],User -> string;userIndex:map[string]User',
+// and the method selection of -methods and -exclude, into the element types, the names and the methods which are
+// given to gen.Spec, so that the flags, the configuration files and the go:generate directives of the packages are all
+// parsed in the same way
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+var (
+	validName = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+	arrayType = regexp.MustCompile(`^\[([0-9]+|[A-Za-z_][\w.]*)\].+$`)
+)
+
+// Types - the types of a -types directive, by Parse
+type Types struct {
+	// Lists - the names of the lists by element type, eg: {"int": "I"} for the list 'IList' of 'int:I', or
+	// {"*User": "*User"} for the list 'UserList' of '*User'
+	Lists map[string]string
+	// Maps - the names of the map and the array types by type, eg: {"map[string]User": "userIndex"} for
+	// 'userIndex:map[string]User'
+	Maps map[string]string
+	// Methods - the methods of the lists given with a method list, by list name, eg: {"IList": {"Map": true}} for
+	// 'int:I[Map]'. The method list of a type replaces -methods, -exclude and the options adding methods for its list
+	Methods map[string]map[string]bool
+	// Equality - the functions comparing the members of the lists given with the eq and hash options, by list name, eg:
+	// {"TaskList": {Eq: "sameTask"}} for 'Task[eq=sameTask]'
+	Equality map[string]gen.Equality
+	// Targets - the lists the methods mapping to other lists, like Map, map the lists given with Map targets to, by list
+	// name, eg: {"UserList": {"stringList"}} for 'User -> string'. The targets which are not in the directive are added
+	// to Lists
+	Targets map[string][]string
+}
+
+// Parse - parse a -types directive, whose method lists can select the methods given, eg: gen.Methods(). With export,
+// the lists named after their element type are exported, eg: 'StringList' for 'string' (see exportNames). The errors
+// start with the flag they are about, eg: '-types parameter'
+func Parse(directive string, export bool, methods []gen.Method) (Types, error) {
+	types := Types{Lists: getTypeMap(directive), Maps: getMapTypes(directive)}
+	if err := validateTypeMap(directive, types.Lists); err != nil {
+		return Types{}, fmt.Errorf("-types parameter %s", err)
+	}
+	if export {
+		if err := exportNames(types.Lists); err != nil {
+			return Types{}, fmt.Errorf("-export: %s", err)
+		}
+	}
+	var err error
+	if types.Methods, err = getTypeMethods(directive, types.Lists, methods); err != nil {
+		return Types{}, fmt.Errorf("-types parameter %s", err)
+	}
+	if types.Equality, err = getTypeEquality(directive, types.Lists); err != nil {
+		return Types{}, fmt.Errorf("-types parameter %s", err)
+	}
+	if types.Targets, err = getTypeTargets(directive, types.Lists, export); err != nil {
+		return Types{}, fmt.Errorf("-types parameter %s", err)
+	}
+	return types, nil
+}
+
+func getTypeMap(targets string) map[string]string {
+	m := map[string]string{}
+	if targets == "" {
+		return m
+	}
+
+	targetParts := Split(targets)
+	for _, t := range targetParts {
+		t, _ = typeMapTargets(t)
+		t, _ = typeMethodList(t)
+		if _, _, ok := parseMapType(t); ok {
+			continue
+		}
+		typeName, name := ParseType(t)
+		m[typeName] = name
+	}
+
+	return m
+}
+
+// getMapTypes - get the map and array types of the -types option, given as 'name:map[K]V' and 'name:[N]T', with their
+// names, eg: 'userIndex:map[string]User,vec4:[4]float32' -> {"map[string]User": "userIndex", "[4]float32": "vec4"}
+func getMapTypes(targets string) map[string]string {
+	m := map[string]string{}
+	if targets == "" {
+		return m
+	}
+
+	for _, t := range Split(targets) {
+		t, _ = typeMapTargets(t)
+		if typeName, name, ok := parseMapType(t); ok {
+			m[typeName] = name
+		}
+	}
+	return m
+}
+
+// parseMapType - split a map or an array type of the -types option, eg: 'userIndex:map[string]User' or
+// 'vec4:[4]float32', into the type and its name. A map or an array type given as 'map[K]V:Name' or '[N]T:Name' is the
+// element type of a list, like the other types
+func parseMapType(t string) (string, string, bool) {
+	parts := strings.SplitN(t, ":", 2)
+	if len(parts) != 2 || !strings.HasPrefix(parts[1], "map[") && !arrayType.MatchString(parts[1]) || strings.ContainsAny(parts[0], "[]*.") {
+		return "", "", false
+	}
+	return parts[1], parts[0], true
+}
+
+// ParseType - get the element type and the name of a type of the -types option, without its method list: 'type',
+// 'type:Name', or 'nameList:pkg.Type' for the types of other packages, eg: 'timeList:time.Time' or
+// 'userList:github.com/acme/app/models.User', or 'name:T' and 'nameList:T' for the slices and the interfaces, eg:
+// 'rowsList:[]string' and 'errList:error'.
+// The name of a type of another package is the name of the type by default, eg: 'Time' for 'time.Time' and '*User' for
+// '*github.com/acme/app/models.User', and the name of a slice is the name of its element type with a 'Slice' suffix, eg:
+// 'stringSlice' for '[]string'
+func ParseType(t string) (string, string) {
+	parts := strings.Split(t, ":")
+	if len(parts) == 2 && strings.HasSuffix(parts[0], "List") && !strings.Contains(parts[0], ".") && strings.Contains(parts[1], ".") {
+		return parts[1], strings.TrimSuffix(parts[0], "List")
+	}
+	if len(parts) == 2 && (strings.HasPrefix(parts[1], "[]") || gen.InterfaceType(parts[1])) && validName.MatchString(parts[0]) {
+		return parts[1], strings.TrimSuffix(parts[0], "List")
+	}
+	if len(parts) > 1 {
+		return parts[0], parts[len(parts)-1]
+	}
+
+	typeName := parts[0]
+	qualified := strings.TrimPrefix(typeName, "*")
+	if dot := strings.LastIndex(qualified, "."); dot >= 0 && !strings.ContainsAny(qualified, "[]()* ") && validName.MatchString(qualified[dot+1:]) {
+		return typeName, typeName[:len(typeName)-len(qualified)] + qualified[dot+1:]
+	}
+	if strings.HasPrefix(typeName, "[]") {
+		_, name := ParseType(typeName[2:])
+		return typeName, strings.TrimPrefix(name, "*") + "Slice"
+	}
+	return typeName, typeName
+}
+
+// qualifiedType - whether an element type of the -types option which is qualified with a package, eg: '*time.Time' or
+// '[]github.com/acme/app/models.User', names a type of a package given by its name or by an import path ending with
+// its name, unlike 'a.b.c'. The types which are not qualified names, eg: 'int' or 'func(time.Time)', are left to the
+// generated code
+func qualifiedType(typeName string) bool {
+	qualified := strings.TrimLeft(typeName, "*[]")
+	dot := strings.LastIndex(qualified, ".")
+	if dot < 0 || strings.ContainsAny(qualified, "[](){} ,") {
+		return true
+	}
+	pkg := qualified[:dot]
+	return validName.MatchString(qualified[dot+1:]) && validName.MatchString(pkg[strings.LastIndex(pkg, "/")+1:])
+}
+
+// validateTypeMap - check that every type of the -types option has a name which can be used in the names of the generated types and methods
+func validateTypeMap(targets string, m map[string]string) error {
+	if len(m) == 0 && len(getMapTypes(targets)) == 0 {
+		return fmt.Errorf("'%s' does not contain any type", targets)
+	}
+
+	names := map[string]string{}
+	// the lists are keyed by their element type, so that a type can only have one list
+	lists := map[string]string{}
+	for _, t := range Split(targets) {
+		withoutTargets, mapTargets := typeMapTargets(t)
+		if typeName := withoutTargets[strings.Index(withoutTargets, ":")+1:]; strings.HasPrefix(typeName, "map[") && !gen.MapType(typeName) {
+			return fmt.Errorf("'%s' is not valid: '%s' is not a map type, expected 'name:map[K]V'", t, typeName)
+		}
+		if typeName, name, ok := parseMapType(withoutTargets); ok {
+			if mapTargets != nil {
+				return fmt.Errorf("'%s' is not valid: the map and array types map their values to all the lists", t)
+			}
+			if !validName.MatchString(name) {
+				return fmt.Errorf("'%s' is not valid: '%s' is not a valid name for the type", t, name)
+			}
+			if other, ok := names[name]; ok {
+				return fmt.Errorf("'%s' is not valid: the name '%s' is already used by '%s'", t, name, other)
+			}
+			names[name] = typeName
+			continue
+		}
+		t, _ := typeMethodList(withoutTargets)
+		typeName, name := ParseType(t)
+		switch {
+		case strings.Count(t, ":") > 1:
+			return fmt.Errorf("'%s' is not valid: expected 'type', 'type:Name' or 'nameList:pkg.Type'", t)
+		case typeName == "":
+			return fmt.Errorf("'%s' is not valid: the type is missing", t)
+		case !qualifiedType(typeName):
+			return fmt.Errorf("'%s' is not valid: '%s' is not a type, expected 'pkg.Type' or an import path like 'github.com/user/app/pkg.Type'", t, typeName)
+		case !validName.MatchString(strings.TrimPrefix(name, "*")):
+			return fmt.Errorf("'%s' is not valid: '%s' cannot be used in the names of the generated types, add a name with 'type:Name'", t, name)
+		}
+
+		name = strings.TrimPrefix(name, "*")
+		if other, ok := names[name]; ok && other != typeName {
+			return fmt.Errorf("'%s' is not valid: the name '%s' is already used by '%s'", t, name, other)
+		}
+		if other, ok := lists[typeName]; ok && other != name {
+			return fmt.Errorf("'%s' is not valid: the type '%s' already has the name '%s', a type can only have one list", t, typeName, other)
+		}
+		names[name] = typeName
+		lists[typeName] = name
+	}
+	return nil
+}
+
+// Split - split the -types option at the commas which are not in brackets, so that the method lists of the
+// types stay with their types, eg: 'int:I[Map,Filter],string' -> 'int:I[Map,Filter]', 'string', and which do not
+// separate the Map targets of a type, which run to the next semicolon (see typeMapTargets), eg:
+// 'User -> string,int;Job' -> 'User -> string,int', 'Job'
+func Split(targets string) []string {
+	result := []string{}
+	depth, start, mapped := 0, 0, false
+	for i, c := range targets {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '>':
+			if depth == 0 && i > 0 && targets[i-1] == '-' {
+				mapped = true
+			}
+		case ',', ';':
+			if depth == 0 && (c == ';' || !mapped) {
+				result = append(result, strings.TrimSpace(targets[start:i]))
+				start, mapped = i+1, false
+			}
+		}
+	}
+	return append(result, strings.TrimSpace(targets[start:]))
+}
+
+// typeMethodList - split a type of the -types option from its method list, eg: 'int:I[Map,Filter]' -> 'int:I',
+// 'Map,Filter'. The method list is empty if the type has none
+func typeMethodList(t string) (string, string) {
+	open := strings.LastIndex(t, "[")
+	if !strings.HasSuffix(t, "]") || open < 0 {
+		return t, ""
+	}
+	return t[:open], t[open+1 : len(t)-1]
+}
+
+// getTypeMethods - get the methods selected for the lists of the types given with a method list in -types, by list
+// name. m maps the types to their names, and the method lists can select the methods given
+func getTypeMethods(targets string, m map[string]string, methods []gen.Method) (map[string]map[string]bool, error) {
+	validMethods := map[string]bool{}
+	for _, method := range methods {
+		validMethods[method.Name] = true
+	}
+
+	result := map[string]map[string]bool{}
+	for _, t := range Split(targets) {
+		t, _ = typeMapTargets(t)
+		withoutMethods, list := typeMethodList(t)
+		if withoutMethods == t {
+			continue
+		}
+		if _, _, ok := parseMapType(withoutMethods); ok {
+			return nil, fmt.Errorf("'%s' is not valid: the map and array types always have all their methods", t)
+		}
+		if strings.TrimSpace(list) == "" {
+			return nil, fmt.Errorf("'%s' is not valid: the method list is empty", t)
+		}
+		methodsMap := map[string]bool{}
+		for _, method := range strings.Split(list, ",") {
+			if strings.Contains(method, "=") {
+				// an option, see getTypeEquality
+				continue
+			}
+			if !validMethods[method] {
+				return nil, fmt.Errorf("'%s' is not valid: '%s' is not a method", t, method)
+			}
+			methodsMap[method] = true
+		}
+		if len(methodsMap) == 0 {
+			continue
+		}
+		typeName, _ := ParseType(withoutMethods)
+		result[strings.TrimPrefix(m[typeName], "*")+"List"] = methodsMap
+	}
+	return result, nil
+}
+
+// getTypeEquality - get the functions comparing the members of the lists of the types given with the 'eq=Func' and
+// 'hash=Func' options in their method list in -types, eg: 'Task[eq=sameTask,hash=hashTask]' or
+// 'Task[Map,Contains,eq=sameTask]', by list name. m maps the types to their names
+func getTypeEquality(targets string, m map[string]string) (map[string]gen.Equality, error) {
+	result := map[string]gen.Equality{}
+	for _, t := range Split(targets) {
+		t, _ = typeMapTargets(t)
+		withoutMethods, list := typeMethodList(t)
+		if withoutMethods == t {
+			continue
+		}
+		equality, given := gen.Equality{}, false
+		for _, option := range strings.Split(list, ",") {
+			parts := strings.SplitN(option, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			if !validName.MatchString(parts[1]) {
+				return nil, fmt.Errorf("'%s' is not valid: '%s' is not the name of a function", t, parts[1])
+			}
+			switch parts[0] {
+			case "eq":
+				equality.Eq = parts[1]
+			case "hash":
+				equality.Hash = parts[1]
+			default:
+				return nil, fmt.Errorf("'%s' is not valid: '%s' is not an option, expected eq=Func or hash=Func", t, parts[0])
+			}
+			given = true
+		}
+		if !given {
+			continue
+		}
+		if equality.Eq == "" {
+			return nil, fmt.Errorf("'%s' is not valid: hash needs eq, the function comparing the members with the same hash", t)
+		}
+		typeName, _ := ParseType(withoutMethods)
+		result[strings.TrimPrefix(m[typeName], "*")+"List"] = equality
+	}
+	return result, nil
+}
+
+// exportNames - capitalize the names of the types which are named after the type itself, eg: 'string' -> 'String', so
+// that their list types are exported
+func exportNames(m map[string]string) error {
+	names := map[string]string{}
+	for typeName, name := range m {
+		names[strings.TrimPrefix(name, "*")] = typeName
+	}
+
+	for _, typeName := range SortedTypes(m) {
+		name := m[typeName]
+		if name != typeName {
+			continue
+		}
+		pointer := strings.HasPrefix(name, "*")
+		exported := strings.Title(strings.TrimPrefix(name, "*"))
+		if other, ok := names[exported]; ok && other != typeName {
+			return fmt.Errorf("the name '%s' of '%s' is already used by '%s'", exported, typeName, other)
+		}
+		names[exported] = typeName
+		if pointer {
+			exported = "*" + exported
+		}
+		m[typeName] = exported
+	}
+	return nil
+}
+
+// SortedTypes - get the types of the type map in a stable order, so that the generated code is the same on every run
+func SortedTypes(m map[string]string) []string {
+	result := []string{}
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
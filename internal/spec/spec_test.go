@@ -0,0 +1,220 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+func TestMethodsMapSkipsOptInMethodsByDefault(t *testing.T) {
+	result, _ := Methods("", gen.Methods())
+
+	if !result["Map"] || result["MapChan"] || result["FilterChan"] || result["Pipeline"] {
+		t.Fail()
+	}
+
+	result, _ = Methods("MapChan", gen.Methods())
+
+	if !result["MapChan"] || result["Map"] {
+		t.Fail()
+	}
+}
+
+func TestValidateTypeMap(t *testing.T) {
+	for _, types := range []string{"int", "int,int", "int:I,string:Str", "*point:P,point:Pt", "timeList:time.Time,*github.com/acme/app/models.User", "userIndex:map[string]User", "int,index:map[string]int,map[string]int:M", "[]int,rowsList:[]string,[]time.Time"} {
+		if validateTypeMap(types, getTypeMap(types)) != nil {
+			t.Fail()
+		}
+	}
+
+	for _, types := range []string{"int:", ":I", "map[string]int", "int:I:J", "int,int8:int", "int,", "[]int,intSlice:[]int8", "time.Time,models.Time", "index:map[string]int,index:map[int]int", "int:index,index:map[int]int", "int:I,int:J", "*point,*point:P", "time.Time,timeList:time.Time", "a.b.c", "[]a.b.c", "cList:a.b.c", "github.com/acme/app/a.b.c", "map[string]", "index:map[string]", "index:map[string"} {
+		if validateTypeMap(types, getTypeMap(types)) == nil {
+			t.Fail()
+		}
+	}
+}
+
+func TestParseType(t *testing.T) {
+	for t1, expected := range map[string][2]string{
+		"int":                              {"int", "int"},
+		"int:I":                            {"int", "I"},
+		"time.Time":                        {"time.Time", "Time"},
+		"*github.com/acme/app/models.User": {"*github.com/acme/app/models.User", "*User"},
+		"timeList:time.Time":               {"time.Time", "time"},
+		"userList:github.com/acme/app/models.User": {"github.com/acme/app/models.User", "user"},
+		"intList:IL":           {"intList", "IL"},
+		"[]time.Time":          {"[]time.Time", "TimeSlice"},
+		"[][]int":              {"[][]int", "intSliceSlice"},
+		"rowsList:[]string":    {"[]string", "rows"},
+		"rows:[]string":        {"[]string", "rows"},
+		"[]string:Rows":        {"[]string", "Rows"},
+		"errList:error":        {"error", "err"},
+		"error:E":              {"error", "E"},
+		"map[string]time.Time": {"map[string]time.Time", "map[string]time.Time"},
+	} {
+		typeName, name := ParseType(t1)
+		if [2]string{typeName, name} != expected {
+			t.Error(t1, typeName, name)
+		}
+	}
+}
+
+func TestGetMapTypes(t *testing.T) {
+	m := getMapTypes("int,userIndex:map[string]User,map[string]int:M,counts:map[string]map[int]bool")
+	if !reflect.DeepEqual(m, map[string]string{"map[string]User": "userIndex", "map[string]map[int]bool": "counts"}) {
+		t.Error(m)
+	}
+	if lists := getTypeMap("int,userIndex:map[string]User,map[string]int:M"); !reflect.DeepEqual(lists, map[string]string{"int": "int", "map[string]int": "M"}) {
+		t.Error(lists)
+	}
+	if _, err := getTypeMethods("userIndex:map[string]User[Keys]", map[string]string{}, gen.Methods()); err == nil {
+		t.Fail()
+	}
+
+	types := "float32,vec4:[4]float32,grid:[Size]Cell,[4]float32:Vec4"
+	if m := getMapTypes(types); !reflect.DeepEqual(m, map[string]string{"[4]float32": "vec4", "[Size]Cell": "grid"}) {
+		t.Error(m)
+	}
+	if lists := getTypeMap(types); !reflect.DeepEqual(lists, map[string]string{"float32": "float32", "[4]float32": "Vec4"}) || validateTypeMap(types, lists) != nil {
+		t.Error(lists)
+	}
+}
+
+func TestTypeMethods(t *testing.T) {
+	types := "int:I[Map,Filter],[]int:Sl,*point[Take],string"
+	if !reflect.DeepEqual(Split(types), []string{"int:I[Map,Filter]", "[]int:Sl", "*point[Take]", "string"}) {
+		t.Error(Split(types))
+	}
+	if typeName, list := typeMethodList("int:I[Map,Filter]"); typeName != "int:I" || list != "Map,Filter" {
+		t.Fail()
+	}
+	if typeName, list := typeMethodList("[]int:Sl"); typeName != "[]int:Sl" || list != "" {
+		t.Fail()
+	}
+
+	m := getTypeMap(types)
+	if !reflect.DeepEqual(m, map[string]string{"int": "I", "[]int": "Sl", "*point": "*point", "string": "string"}) || validateTypeMap(types, m) != nil {
+		t.Error(m)
+	}
+	selected, err := getTypeMethods(types, m, gen.Methods())
+	if err != nil || !reflect.DeepEqual(selected, map[string]map[string]bool{"IList": {"Map": true, "Filter": true}, "pointList": {"Take": true}}) {
+		t.Error(err, selected)
+	}
+	for _, invalid := range []string{"int[Map,Filtre]", "int[]"} {
+		if _, err := getTypeMethods(invalid, getTypeMap(invalid), gen.Methods()); err == nil {
+			t.Error(invalid)
+		}
+	}
+}
+
+func TestTypeEquality(t *testing.T) {
+	types := "Task[eq=sameTask,hash=hashTask],*Job:JobPtr[Map,Contains,eq=sameJob],int[Map]"
+	m := getTypeMap(types)
+	if !reflect.DeepEqual(m, map[string]string{"Task": "Task", "*Job": "JobPtr", "int": "int"}) || validateTypeMap(types, m) != nil {
+		t.Error(m)
+	}
+	selected, err := getTypeMethods(types, m, gen.Methods())
+	if err != nil || !reflect.DeepEqual(selected, map[string]map[string]bool{"JobPtrList": {"Map": true, "Contains": true}, "intList": {"Map": true}}) {
+		t.Error(err, selected)
+	}
+	equality, err := getTypeEquality(types, m)
+	if err != nil || !reflect.DeepEqual(equality, map[string]gen.Equality{"TaskList": {Eq: "sameTask", Hash: "hashTask"}, "JobPtrList": {Eq: "sameJob"}}) {
+		t.Error(err, equality)
+	}
+	for _, invalid := range []string{"Task[hash=hashTask]", "Task[eq=same.Task]", "Task[cmp=sameTask]"} {
+		if _, err := getTypeEquality(invalid, getTypeMap(invalid)); err == nil {
+			t.Error(invalid)
+		}
+	}
+}
+
+func TestExcludeMethods(t *testing.T) {
+	result, _ := Methods("", gen.Methods())
+	if err := Exclude(result, "PFilter,PMap", gen.Methods()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !result["Map"] || !result["Filter"] || result["PFilter"] || result["PMap"] {
+		t.Fail()
+	}
+
+	result, _ = Methods("Map,Filter,Take", gen.Methods())
+	if err := Exclude(result, "Take", gen.Methods()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 || !result["Map"] || !result["Filter"] {
+		t.Fail()
+	}
+}
+
+func TestExportNames(t *testing.T) {
+	m := map[string]string{"int": "int", "*point": "*point", "string": "str", "User": "User"}
+	if err := exportNames(m); err != nil {
+		t.Fail()
+	}
+	if m["int"] != "Int" || m["*point"] != "*Point" || m["string"] != "str" || m["User"] != "User" {
+		t.Fail()
+	}
+
+	if err := exportNames(map[string]string{"int": "int", "Int": "Int"}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestParse(t *testing.T) {
+	types, err := Parse("int:I[Map],Task[eq=sameTask],string -> int;userIndex:map[string]Task", true, gen.Methods())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Types{
+		Lists:    map[string]string{"int": "I", "Task": "Task", "string": "String"},
+		Maps:     map[string]string{"map[string]Task": "userIndex"},
+		Methods:  map[string]map[string]bool{"IList": {"Map": true}},
+		Equality: map[string]gen.Equality{"TaskList": {Eq: "sameTask"}},
+		Targets:  map[string][]string{"StringList": {"IList"}},
+	}
+	if !reflect.DeepEqual(types, expected) {
+		t.Error(types)
+	}
+
+	for directive, message := range map[string]string{
+		"int,":             "-types parameter ",
+		"int[Filtre]":      "-types parameter ",
+		"int,Int":          "-export: ",
+		"Task[hash=h]":     "-types parameter ",
+		"string -> int:I,": "-types parameter ",
+	} {
+		if _, err := Parse(directive, true, gen.Methods()); err == nil || !strings.HasPrefix(err.Error(), message) {
+			t.Error(directive, err)
+		}
+	}
+	if _, err := Methods("Map,Filtre", gen.Methods()); err == nil || err.Error() != "unknown method 'Filtre' in -methods" {
+		t.Error(err)
+	}
+	if err := Exclude(map[string]bool{"Map": true}, "Mapp", gen.Methods()); err == nil {
+		t.Fail()
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, n := range []int{1, 10, 100, 500} {
+		// a -types directive with the names, the method lists and the targets of the lists, eg: 'int,string,
+		// Type0:T0[Map,Filter] -> int,string;Type1:T1[Map,Filter] -> int,string'
+		types := []string{}
+		for i := 0; i < n; i++ {
+			types = append(types, fmt.Sprintf("Type%d:T%d[Map,Filter] -> int,string", i, i))
+		}
+		directive := "int,string," + strings.Join(types, ";")
+		b.Run(fmt.Sprintf("types=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := Parse(directive, false, gen.Methods()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// typeMapTargets - split a type of the -types option from its Map targets, eg: 'User:user -> string,int' ->
+// 'User:user', 'string', 'int'. The targets are nil if the type has none
+func typeMapTargets(t string) (string, []string) {
+	arrow := strings.Index(t, "->")
+	if arrow < 0 {
+		return t, nil
+	}
+	targets := []string{}
+	for _, target := range strings.Split(t[arrow+2:], ",") {
+		targets = append(targets, strings.TrimSpace(target))
+	}
+	return strings.TrimSpace(t[:arrow]), targets
+}
+
+// Join - join the types of the -types option with commas, and with a semicolon after the types with Map targets,
+// so that Split splits them again
+func Join(types []string) string {
+	result := ""
+	for i, t := range types {
+		if i > 0 {
+			if _, targets := typeMapTargets(types[i-1]); targets != nil {
+				result += ";"
+			} else {
+				result += ","
+			}
+		}
+		result += t
+	}
+	return result
+}
+
+// getTypeTargets - get the lists the methods mapping to other lists, like Map, map the lists of the types given with
+// Map targets in -types to, eg: 'User:user -> string,int,float64' generates MapString, MapInt and MapFloat64 for
+// userList, but not the Map methods of the other lists, by list name. m maps the types to their names, and the targets
+// which are not in it are added to it, so that their lists are generated too, named like the types of -types unless
+// the name is used by another list (see targetName)
+func getTypeTargets(targets string, m map[string]string, export bool) (map[string][]string, error) {
+	names := map[string]bool{}
+	for _, name := range m {
+		names[strings.TrimPrefix(name, "*")] = true
+	}
+
+	result := map[string][]string{}
+	for _, t := range Split(targets) {
+		withoutTargets, mapTargets := typeMapTargets(t)
+		if mapTargets == nil {
+			continue
+		}
+		withoutMethods, _ := typeMethodList(withoutTargets)
+		typeName, _ := ParseType(withoutMethods)
+		listName := strings.TrimPrefix(m[typeName], "*") + "List"
+
+		lists := []string{}
+		for _, target := range mapTargets {
+			if target == "" {
+				return nil, fmt.Errorf("'%s' is not valid: a Map target is missing", t)
+			}
+			targetType, name := ParseType(target)
+			if _, ok := m[targetType]; !ok {
+				if export && name == targetType {
+					name = strings.Title(name)
+				}
+				if !validName.MatchString(strings.TrimPrefix(name, "*")) {
+					return nil, fmt.Errorf("'%s' is not valid: the Map target '%s' cannot be used in the names of the generated types, add a name with 'type:Name'", t, target)
+				}
+				name = targetName(targetType, name, names)
+				m[targetType] = name
+				names[strings.TrimPrefix(name, "*")] = true
+			}
+			lists = append(lists, strings.TrimPrefix(m[targetType], "*")+"List")
+		}
+		result[listName] = lists
+	}
+	return result, nil
+}
+
+// targetName - get the name of the list of a Map target which is not in -types: its name, or, if another list uses it,
+// the name prefixed with the name of the package of the type, eg: 'modelUser' for 'github.com/acme/app/model.User',
+// followed by 'Ptr' for the pointers, eg: 'UserPtr' for '*User', or followed by a number, eg: 'User2'
+func targetName(typeName, name string, names map[string]bool) string {
+	base := strings.TrimPrefix(name, "*")
+	if !names[base] {
+		return name
+	}
+
+	candidates := []string{}
+	qualified := strings.TrimPrefix(typeName, "*")
+	if dot := strings.LastIndex(qualified, "."); dot >= 0 {
+		pkg := qualified[strings.LastIndex(qualified[:dot], "/")+1 : dot]
+		candidates = append(candidates, pkg+strings.Title(base))
+	}
+	if strings.HasPrefix(typeName, "*") {
+		candidates = append(candidates, base+"Ptr")
+	}
+	for _, candidate := range candidates {
+		if validName.MatchString(candidate) && !names[candidate] {
+			return candidate
+		}
+	}
+	for i := 2; ; i++ {
+		if candidate := base + strconv.Itoa(i); !names[candidate] {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypeTargets(t *testing.T) {
+	types := "User:user[Map,Filter] -> string,int:I, float64;bool,*User -> User"
+	if !reflect.DeepEqual(Split(types), []string{"User:user[Map,Filter] -> string,int:I, float64", "bool", "*User -> User"}) {
+		t.Error(Split(types))
+	}
+	if typeName, targets := typeMapTargets("User:user[Map] -> string, int"); typeName != "User:user[Map]" || !reflect.DeepEqual(targets, []string{"string", "int"}) {
+		t.Error(typeName, targets)
+	}
+	if Join(Split(types)) != "User:user[Map,Filter] -> string,int:I, float64;bool,*User -> User" {
+		t.Error(Join(Split(types)))
+	}
+
+	m := getTypeMap(types)
+	if !reflect.DeepEqual(m, map[string]string{"User": "user", "bool": "bool", "*User": "*User"}) || validateTypeMap(types, m) != nil {
+		t.Error(m)
+	}
+	targets, err := getTypeTargets(types, m, false)
+	if err != nil || !reflect.DeepEqual(targets, map[string][]string{"userList": {"stringList", "IList", "float64List"}, "UserList": {"userList"}}) {
+		t.Error(err, targets)
+	}
+	if !reflect.DeepEqual(m, map[string]string{"User": "user", "bool": "bool", "*User": "*User", "string": "string", "int": "I", "float64": "float64"}) {
+		t.Error(m)
+	}
+
+	for _, invalid := range []string{"User -> string,", "User -> map[string]int", "index:map[string]User -> string"} {
+		if _, err := getTypeTargets(invalid, getTypeMap(invalid), false); err == nil && validateTypeMap(invalid, getTypeMap(invalid)) == nil {
+			t.Error(invalid)
+		}
+	}
+}
+
+func TestTargetName(t *testing.T) {
+	names := map[string]bool{"User": true, "modelUser": true, "Time": true}
+	for typeName, expected := range map[string]string{"string": "string", "github.com/acme/app/model.User": "User2", "*User": "UserPtr", "time.Time": "timeTime"} {
+		_, name := ParseType(typeName)
+		if result := targetName(typeName, name, names); result != expected {
+			t.Error(typeName, result)
+		}
+	}
+}
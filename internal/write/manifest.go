@@ -0,0 +1,87 @@
+package write
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HashOf - get the hexadecimal SHA-256 hash of a content
+func HashOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// ManifestName - get the name of a generated file in the manifest, relative to the directory of the manifest file and
+// with slashes, so that the manifest does not depend on the directory fungen is run from
+func ManifestName(manifestFile, filename string) string {
+	name, err := filepath.Rel(filepath.Dir(manifestFile), filename)
+	if err != nil {
+		name = filename
+	}
+	return filepath.ToSlash(name)
+}
+
+// ReadManifest - read the hashes recorded in a manifest file, in the format of sha256sum ('<hash>  <name>' lines). A
+// missing manifest file is empty
+func ReadManifest(filename string) (map[string]string, error) {
+	result := map[string]string{}
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		parts := strings.SplitN(text, "  ", 2)
+		if len(parts) != 2 || len(parts[0]) != 2*sha256.Size {
+			return nil, fmt.Errorf("line %d of %s is not '<sha256>  <file>'", line, filename)
+		}
+		result[parts[1]] = parts[0]
+	}
+	return result, scanner.Err()
+}
+
+// formatManifest - format the hashes of the files as a manifest, sorted by file name
+func formatManifest(m map[string]string) string {
+	names := []string{}
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var result strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&result, "%s  %s\n", m[name], name)
+	}
+	return result.String()
+}
+
+// WriteManifest - write the hashes recorded for the files which still exist to the manifest file, if they changed
+func WriteManifest(filename string, m map[string]string) error {
+	existing := map[string]string{}
+	for name, hash := range m {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(filename), filepath.FromSlash(name))); err == nil {
+			existing[name] = hash
+		}
+	}
+
+	content := formatManifest(existing)
+	if previous, err := ioutil.ReadFile(filename); err == nil && string(previous) == content {
+		return nil
+	}
+	return ioutil.WriteFile(filename, []byte(content), 0644)
+}
@@ -0,0 +1,55 @@
+package write
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestManifestName(t *testing.T) {
+	if ManifestName("fungen.sum", "fungen_auto.go") != "fungen_auto.go" {
+		t.Fail()
+	}
+	if ManifestName("models/fungen.sum", filepath.Join("models", "lists", "int.go")) != "lists/int.go" {
+		t.Fail()
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "int.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "fungen.sum")
+	recorded, err := ReadManifest(filename)
+	if err != nil || len(recorded) != 0 {
+		t.Fatal(err, recorded)
+	}
+
+	hash := HashOf([]byte("package main\n"))
+	if err := WriteManifest(filename, map[string]string{"int.go": hash, "removed.go": hash}); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil || string(content) != hash+"  int.go\n" {
+		t.Fatal(err, string(content))
+	}
+	recorded, err = ReadManifest(filename)
+	if err != nil || !reflect.DeepEqual(recorded, map[string]string{"int.go": hash}) {
+		t.Fatal(err, recorded)
+	}
+
+	if err := ioutil.WriteFile(filename, []byte("not a manifest\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadManifest(filename); err == nil {
+		t.Fail()
+	}
+}
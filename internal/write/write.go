@@ -0,0 +1,80 @@
+// Package write - write the files generated by fungen, or display them with -test, without rewriting the files which
+// are unchanged, and record their hashes in the -manifest
+package write
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"strings"
+)
+
+// Output - how the generated files are written
+type Output struct {
+	// Test - whether the files are displayed on the standard output instead of being written, for -test
+	Test bool
+	// ManifestFile - the -manifest file, which the names of the files in the Manifest are relative to
+	ManifestFile string
+	// Manifest - the SHA-256 hashes of the generated files, by their names relative to the directory of the
+	// ManifestFile (see ManifestName), updated with the files written
+	Manifest map[string]string
+	// Infof and Warnf - report progress and warnings
+	Infof, Warnf func(format string, args ...interface{})
+}
+
+// File - write the generated source to the file, or to the standard output if filename is '-', or display it if Test
+// is set. A file whose content is unchanged is not rewritten, so that its modification time does not change. It
+// returns whether the source was written
+func (o *Output) File(filename, src string) (bool, error) {
+	if filename == "-" {
+		fmt.Print(src)
+		return true, nil
+	}
+
+	if o.Test {
+		fmt.Println(filename)
+		fmt.Println(src)
+		return true, nil
+	}
+
+	hash := HashOf([]byte(src))
+	name := ManifestName(o.ManifestFile, filename)
+	existing, err := ioutil.ReadFile(filename)
+	if err == nil {
+		existingHash := HashOf(existing)
+		if recorded, ok := o.Manifest[name]; ok && recorded != existingHash && existingHash != hash {
+			o.Warnf("%s was changed since it was generated, the changes are overwritten", filename)
+		}
+		if existingHash == hash {
+			o.Manifest[name] = hash
+			o.Infof("%s is unchanged", filename)
+			return false, nil
+		}
+	}
+
+	if err := ioutil.WriteFile(filename, []byte(src), 0644); err != nil {
+		return false, err
+	}
+	o.Manifest[name] = hash
+	return true, nil
+}
+
+// ReadHeader - read the file given with -header-file and check that it only contains comments, so that the
+// generated files are still valid
+func ReadHeader(filename string) (string, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+
+	header := strings.TrimSpace(string(content))
+	if header == "" {
+		return "", nil
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), filename, header+"\n\npackage p\n", parser.ParseComments)
+	if err != nil || file.Package != token.Pos(len(header)+3) {
+		return "", fmt.Errorf("'%s' must only contain comments", filename)
+	}
+	return header + "\n\n", nil
+}
@@ -0,0 +1,71 @@
+package write
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadHeader(t *testing.T) {
+	file, err := ioutil.TempFile("", "header")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	for content, expected := range map[string]string{
+		"// Copyright ACME\n// All rights reserved\n": "// Copyright ACME\n// All rights reserved\n\n",
+		"/*\nCopyright ACME\n*/":                      "/*\nCopyright ACME\n*/\n\n",
+		"\n\n":                                        "",
+		"Copyright ACME\n":                            "error",
+		"// Copyright ACME\npackage main\n":           "error",
+	} {
+		if err := ioutil.WriteFile(file.Name(), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		result, err := ReadHeader(file.Name())
+		if err != nil {
+			result = "error"
+		}
+		if result != expected {
+			t.Fail()
+		}
+	}
+}
+
+func TestOutputFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	warnings := []string{}
+	out := Output{
+		ManifestFile: filepath.Join(dir, "fungen.sum"),
+		Manifest:     map[string]string{},
+		Infof:        func(string, ...interface{}) {},
+		Warnf:        func(format string, args ...interface{}) { warnings = append(warnings, format) },
+	}
+	filename := filepath.Join(dir, "int.go")
+	if written, err := out.File(filename, "package main\n"); !written || err != nil {
+		t.Fatal(written, err)
+	}
+	if written, err := out.File(filename, "package main\n"); written || err != nil {
+		t.Fatal(written, err)
+	}
+	if out.Manifest["int.go"] != HashOf([]byte("package main\n")) {
+		t.Error(out.Manifest)
+	}
+
+	if err := ioutil.WriteFile(filename, []byte("package main // edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if written, err := out.File(filename, "package main\n\nvar x int\n"); !written || err != nil || len(warnings) != 1 {
+		t.Error(written, err, warnings)
+	}
+	if content, err := ioutil.ReadFile(filename); err != nil || string(content) != "package main\n\nvar x int\n" {
+		t.Error(err, string(content))
+	}
+}
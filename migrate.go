@@ -0,0 +1,333 @@
+package main
+
+import (
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+// standardFunctions - the functions of the standard library which 'fungen migrate' calls instead of the generic
+// functions they are equivalent to, with their package
+var standardFunctions = map[string]string{
+	"Any": "slices.ContainsFunc",
+}
+
+// migratedFile - the source of a file whose calls of the generated methods were rewritten, with the number of calls
+type migratedFile struct {
+	src   string
+	calls int
+}
+
+// migration - a call of a generated method which is rewritten to a call of a function: the function, eg: 'Map' or
+// 'slices.ContainsFunc', whether the receiver is a pointer to the list, and the list type the result is converted to
+// when the type of the result matters, eg: 'x := intList(Filter(l, f))'
+type migration struct {
+	call     *ast.CallExpr
+	function string
+	deref    bool
+	convert  string
+}
+
+// migrateDirectories - rewrite the calls of the generated methods which have a generic function with -generics, eg:
+// 'l.MapStr(f)', to calls of the generic functions, eg: 'Map(l, f)', or of the equivalent functions of the standard
+// library, in the packages of the directories matching the patterns. The files generated by fungen are left as they
+// are. It returns the rewritten files, by name
+func migrateDirectories(patterns []string) (map[string]migratedFile, error) {
+	result := map[string]migratedFile{}
+	for _, pattern := range patterns {
+		err := walkDirectories(pattern, func(dir string) error {
+			migrated, err := migrateDir(dir)
+			for filename, file := range migrated {
+				result[filename] = file
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// migrateDir - rewrite the calls of the generated methods in the packages of a directory, including the test files of
+// the packages. The files which cannot be parsed are left out
+func migrateDir(dir string) (map[string]migratedFile, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	packages := map[string][]*ast.File{}
+	names := []string{}
+	sources := map[*ast.File][]byte{}
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") {
+			continue
+		}
+		path := filepath.Join(dir, info.Name())
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		if _, ok := packages[file.Name.Name]; !ok {
+			names = append(names, file.Name.Name)
+		}
+		packages[file.Name.Name] = append(packages[file.Name.Name], file)
+		sources[file] = src
+	}
+	sort.Strings(names)
+
+	result := map[string]migratedFile{}
+	for _, name := range names {
+		migrated, err := migratePackage(fset, name, packages[name], sources)
+		if err != nil {
+			return nil, err
+		}
+		for filename, file := range migrated {
+			result[filename] = file
+		}
+	}
+	return result, nil
+}
+
+// migratePackage - rewrite the calls of the methods generated in the files of a package which have a generic
+// function. The calls are found by type-checking the package; the calls of the in-place methods with a pointer
+// receiver (see -pointer), which replace the list, and the calls of Each and EachI whose result is used, which the
+// generic functions do not return, are left as they are
+func migratePackage(fset *token.FileSet, name string, files []*ast.File, sources map[*ast.File][]byte) (map[string]migratedFile, error) {
+	generated := map[string]bool{}
+	lists := map[string]bool{}
+	for _, file := range files {
+		if !generatedByFungen(file) {
+			continue
+		}
+		generated[fset.Position(file.Pos()).Filename] = true
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+				lists[gen.DeclarationName(fn)] = true
+			}
+		}
+	}
+	if len(lists) == 0 {
+		return nil, nil
+	}
+	targets := []string{}
+	for listName := range lists {
+		targets = append(targets, strings.Title(strings.TrimSuffix(listName, "List")))
+	}
+	inPlace := map[string]bool{}
+	for _, method := range gen.Methods() {
+		inPlace[method.Name] = method.InPlace
+	}
+
+	info := &gotypes.Info{Selections: map[*ast.SelectorExpr]*gotypes.Selection{}, Types: map[ast.Expr]gotypes.TypeAndValue{}}
+	config := gotypes.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		// the calls which cannot be type-checked are not rewritten
+		Error: func(error) {},
+	}
+	config.Check(name, fset, files, info)
+
+	result := map[string]migratedFile{}
+	for _, file := range files {
+		filename := fset.Position(file.Pos()).Filename
+		if generated[filename] {
+			continue
+		}
+
+		parents := map[ast.Node]ast.Node{}
+		stack := []ast.Node{}
+		migrations := []migration{}
+		migrated := map[*ast.CallExpr]bool{}
+		ast.Inspect(file, func(node ast.Node) bool {
+			if node == nil {
+				stack = stack[:len(stack)-1]
+				return true
+			}
+			if len(stack) > 0 {
+				parents[node] = stack[len(stack)-1]
+			}
+			stack = append(stack, node)
+			return true
+		})
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			selection, ok := info.Selections[sel]
+			if !ok || selection.Kind() != gotypes.MethodVal || !generated[fset.Position(selection.Obj().Pos()).Filename] {
+				return true
+			}
+			signature := selection.Obj().Type().(*gotypes.Signature)
+			recv, pointer := signature.Recv().Type(), false
+			if ptr, ok := recv.(*gotypes.Pointer); ok {
+				recv, pointer = ptr.Elem(), true
+			}
+			named, ok := recv.(*gotypes.Named)
+			if !ok || !lists[named.Obj().Name()] {
+				return true
+			}
+			function := gen.GenericFunction(sel.Sel.Name, targets)
+			if function == "" || pointer && inPlace[function] {
+				return true
+			}
+			if _, statement := parents[call].(*ast.ExprStmt); (function == "Each" || function == "EachI") && !statement {
+				return true
+			}
+
+			m := migration{call: call, function: function}
+			if standard, ok := standardFunctions[function]; ok {
+				m.function = standard
+			}
+			if tv, ok := info.Types[sel.X]; ok {
+				_, m.deref = tv.Type.(*gotypes.Pointer)
+			}
+			if results := signature.Results(); results.Len() == 1 && resultTypeMatters(call, parents, migrated) {
+				if list, ok := results.At(0).Type().(*gotypes.Named); ok && lists[list.Obj().Name()] {
+					m.convert = list.Obj().Name()
+				}
+			}
+			migrations = append(migrations, m)
+			migrated[call] = true
+			return true
+		})
+		if len(migrations) == 0 {
+			continue
+		}
+
+		src, err := renderMigrations(fset, sources[file], migrations)
+		if err != nil {
+			return nil, err
+		}
+		result[filename] = migratedFile{src, len(migrations)}
+	}
+	return result, nil
+}
+
+// resultTypeMatters - whether the type of the result of a call matters, so that the named list type it has is kept:
+// when a method which is not migrated is called on it, or when it declares a variable without a type. The calls are
+// visited before the calls in their receivers, so the migrated calls are known
+func resultTypeMatters(call *ast.CallExpr, parents map[ast.Node]ast.Node, migrated map[*ast.CallExpr]bool) bool {
+	switch parent := parents[call].(type) {
+	case *ast.SelectorExpr:
+		outer, ok := parents[parent].(*ast.CallExpr)
+		return parent.X == call && !(ok && migrated[outer])
+	case *ast.AssignStmt:
+		return parent.Tok == token.DEFINE
+	case *ast.ValueSpec:
+		return parent.Type == nil
+	}
+	return false
+}
+
+// renderMigrations - get the formatted source of a file with the calls of the migrations rewritten, including the
+// calls in the receivers and the arguments of the other calls, eg: 'l.Filter(f).Map(g)', and with the imports of the
+// functions of the standard library
+func renderMigrations(fset *token.FileSet, src []byte, migrations []migration) (string, error) {
+	// the calls containing other calls come first
+	sort.Slice(migrations, func(i, j int) bool {
+		if migrations[i].call.Pos() != migrations[j].call.Pos() {
+			return migrations[i].call.Pos() < migrations[j].call.Pos()
+		}
+		return migrations[i].call.End() > migrations[j].call.End()
+	})
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+
+	var render func(start, end int) string
+	render = func(start, end int) string {
+		text := ""
+		at := start
+		for _, m := range migrations {
+			if offset(m.call.Pos()) < at || offset(m.call.End()) > end {
+				continue
+			}
+			sel := m.call.Fun.(*ast.SelectorExpr)
+			args := []string{render(offset(sel.X.Pos()), offset(sel.X.End()))}
+			if m.deref {
+				args[0] = "*" + args[0]
+			}
+			for _, arg := range m.call.Args {
+				args = append(args, render(offset(arg.Pos()), offset(arg.End())))
+			}
+			call := m.function + "(" + strings.Join(args, ", ")
+			if m.call.Ellipsis.IsValid() {
+				call += "..."
+			}
+			call += ")"
+			if m.convert != "" {
+				call = m.convert + "(" + call + ")"
+			}
+			text += string(src[at:offset(m.call.Pos())]) + call
+			at = offset(m.call.End())
+		}
+		return text + string(src[at:end])
+	}
+	code := render(0, len(src))
+
+	imports := map[string]bool{}
+	for _, m := range migrations {
+		if dot := strings.Index(m.function, "."); dot >= 0 {
+			imports[m.function[:dot]] = true
+		}
+	}
+	for path := range imports {
+		var err error
+		if code, err = addImport(code, path); err != nil {
+			return "", err
+		}
+	}
+
+	formatted, err := format.Source([]byte(code))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+// addImport - add the import of a standard package to a source file, unless it imports it already
+func addImport(src, path string) (string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly)
+	if err != nil {
+		return "", err
+	}
+	for _, spec := range file.Imports {
+		if imported, _ := strconv.Unquote(spec.Path.Value); imported == path {
+			return src, nil
+		}
+	}
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.IMPORT {
+			continue
+		}
+		if decl.Lparen.IsValid() {
+			at := int(decl.Lparen)
+			return src[:at] + "\n\t" + strconv.Quote(path) + src[at:], nil
+		}
+		// a single import, eg: 'import "fmt"', is turned into a block
+		spec := src[decl.Specs[0].Pos()-1 : decl.End()-1]
+		return src[:decl.Pos()-1] + "import (\n\t" + spec + "\n\t" + strconv.Quote(path) + "\n)" + src[decl.End()-1:], nil
+	}
+	at := int(file.Name.End()) - 1
+	return src[:at] + "\n\nimport " + strconv.Quote(path) + src[at:], nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+func TestMigrateDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	generated, err := gen.Generate(gen.Spec{Package: "models", Header: "// Code generated by fungen; DO NOT EDIT.\n\n", Types: map[string]string{"int": "int", "string": "Str"}, Methods: []string{"Map", "PMap", "Filter", "Each", "Any"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"fungen_auto.go": string(generated),
+		"models.go": `package models
+
+func evens(l intList) (StrList, bool) {
+	p := &l
+	l.Each(func(int) {})
+	labels := l.Filter(func(i int) bool { return i%2 == 0 }).MapStr(func(int) string { return "" })
+	return labels, p.Any(func(i int) bool { return i > 2 }) && len(l.Filter(func(int) bool { return true }).PMap(func(i int) int { return i })) > 0
+}
+`,
+		"unrelated.go": "package models\n\nfunc unrelated(l []int) int {\n\treturn len(l)\n}\n",
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migrated, err := migrateDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrated) != 1 {
+		t.Fatal(migrated)
+	}
+	expected := `package models
+
+import "slices"
+
+func evens(l intList) (StrList, bool) {
+	p := &l
+	Each(l, func(int) {})
+	labels := StrList(Map(Filter(l, func(i int) bool { return i%2 == 0 }), func(int) string { return "" }))
+	return labels, slices.ContainsFunc(*p, func(i int) bool { return i > 2 }) && len(intList(Filter(l, func(int) bool { return true })).PMap(func(i int) int { return i })) > 0
+}
+`
+	file := migrated[filepath.Join(dir, "models.go")]
+	if file.src != expected || file.calls != 5 {
+		t.Error(file.calls, file.src)
+	}
+}
+
+func TestAddImport(t *testing.T) {
+	for src, expected := range map[string]string{
+		"package models\n":                              "package models\n\nimport \"slices\"\n",
+		"package models\n\nimport \"fmt\"\n":            "package models\n\nimport (\n\t\"fmt\"\n\t\"slices\"\n)\n",
+		"package models\n\nimport (\n\t\"fmt\"\n)\n":    "package models\n\nimport (\n\t\"slices\"\n\t\"fmt\"\n)\n",
+		"package models\n\nimport (\n\t\"slices\"\n)\n": "package models\n\nimport (\n\t\"slices\"\n)\n",
+	} {
+		if result, err := addImport(src, "slices"); err != nil || result != expected {
+			t.Errorf("%q: got %q, %v", src, result, err)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kulshekhar/fungen/gen"
+	"github.com/kulshekhar/fungen/internal/spec"
+)
+
+// pluckedFields - the fields of the struct element types selected by -pluck, by list name. The lists get a Pluck
+// method for each of them, eg: 'PluckName() stringList'
+var pluckedFields = map[string][]gen.Field{}
+
+// structFields - find the exported fields of the struct types declared in the Go files of a directory, except the test
+// files and the files generated by fungen, in the order of their declaration, by struct name. The types of the fields
+// are given as they are written, eg: 'time.Time', and the embedded fields are named after their type
+func structFields(dir string) (map[string][]gen.Field, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string][]gen.Field{}
+	for _, info := range files {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if generatedByFungen(file) {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok || typeSpec.TypeParams != nil {
+					continue
+				}
+
+				fields := []gen.Field{}
+				for _, field := range structType.Fields.List {
+					var typeName bytes.Buffer
+					if err := printer.Fprint(&typeName, fset, field.Type); err != nil {
+						return nil, err
+					}
+					names := []string{}
+					for _, ident := range field.Names {
+						names = append(names, ident.Name)
+					}
+					if len(field.Names) == 0 {
+						embedded := strings.TrimPrefix(typeName.String(), "*")
+						names = append(names, embedded[strings.LastIndex(embedded, ".")+1:])
+					}
+					for _, fieldName := range names {
+						if ast.IsExported(fieldName) {
+							fields = append(fields, gen.Field{Name: fieldName, Type: typeName.String()})
+						}
+					}
+				}
+				result[typeSpec.Name.Name] = fields
+			}
+		}
+	}
+	return result, nil
+}
+
+// getPluckedFields - get the fields of the -pluck option, given as 'Type' for all the exported fields of a struct or as
+// 'Type.Field' for one of them, eg: 'User,point.X', by the name of the lists of the types and of the pointers to them in
+// typeMap. The structs are the ones declared in the Go files of dir
+func getPluckedFields(dir, plucked string, typeMap map[string]string) (map[string][]gen.Field, error) {
+	structs, err := structFields(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := map[string][]gen.Field{}
+	for _, p := range strings.Split(plucked, ",") {
+		structName, fieldName := p, ""
+		if dot := strings.Index(p, "."); dot >= 0 {
+			structName, fieldName = p[:dot], p[dot+1:]
+		}
+		fields, ok := structs[structName]
+		if !ok {
+			return nil, fmt.Errorf("'%s' is not valid: '%s' is not a struct type declared in the package", p, structName)
+		}
+		if fieldName == "" {
+			selected[structName] = append(selected[structName], fields...)
+			continue
+		}
+		found := false
+		for _, field := range fields {
+			if field.Name == fieldName {
+				selected[structName] = append(selected[structName], field)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("'%s' is not valid: '%s' is not an exported field of '%s'", p, fieldName, structName)
+		}
+	}
+
+	result := map[string][]gen.Field{}
+	structNames := []string{}
+	for structName := range selected {
+		structNames = append(structNames, structName)
+	}
+	sort.Strings(structNames)
+	for _, structName := range structNames {
+		lists := 0
+		for _, typeName := range []string{structName, "*" + structName} {
+			name, ok := typeMap[typeName]
+			if !ok {
+				continue
+			}
+			listName := strings.TrimPrefix(name, "*") + "List"
+			for _, field := range selected[structName] {
+				if !containsField(result[listName], field) {
+					result[listName] = append(result[listName], field)
+				}
+			}
+			lists++
+		}
+		if lists == 0 {
+			return nil, fmt.Errorf("'%s' is not valid: the list of '%s' is not generated, add it to -types", structName, structName)
+		}
+	}
+	return result, nil
+}
+
+// containsField - whether a list of fields contains a field
+func containsField(fields []gen.Field, field gen.Field) bool {
+	for _, other := range fields {
+		if other == field {
+			return true
+		}
+	}
+	return false
+}
+
+// addFieldTypes - add the types of the plucked fields to the type map, so that the Pluck methods return their lists,
+// unless they are in it already or their name, like the name of a type of -types (see spec.ParseType), cannot be used in
+// the name of a list or is used by another type. The Pluck methods return a slice of these types
+func addFieldTypes(typeMap map[string]string, fields map[string][]gen.Field) {
+	names := map[string]bool{}
+	for _, name := range typeMap {
+		names[strings.TrimPrefix(name, "*")] = true
+	}
+
+	listNames := []string{}
+	for listName := range fields {
+		listNames = append(listNames, listName)
+	}
+	sort.Strings(listNames)
+	for _, listName := range listNames {
+		for _, field := range fields[listName] {
+			if _, ok := typeMap[field.Type]; ok {
+				continue
+			}
+			_, name := spec.ParseType(field.Type)
+			if *exportLists && name == field.Type {
+				name = strings.Title(name)
+			}
+			if !validName.MatchString(strings.TrimPrefix(name, "*")) || names[strings.TrimPrefix(name, "*")] {
+				continue
+			}
+			typeMap[field.Type] = name
+			names[strings.TrimPrefix(name, "*")] = true
+		}
+	}
+}
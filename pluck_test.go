@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+func TestPluckedFields(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"models.go": `package models
+
+import "time"
+
+type Base struct{ ID int }
+
+type User struct {
+	Name, Email string
+	age         int
+	Born        time.Time
+	*Base
+}
+`,
+		"models_test.go": "package models\n\ntype testing struct{ Name string }\n",
+		"fungen_auto.go": "// Code generated by fungen; DO NOT EDIT.\n\npackage models\n\ntype generated struct{ Name string }\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	structs, err := structFields(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []gen.Field{{Name: "Name", Type: "string"}, {Name: "Email", Type: "string"}, {Name: "Born", Type: "time.Time"}, {Name: "Base", Type: "*Base"}}
+	if len(structs) != 2 || !reflect.DeepEqual(structs["User"], expected) {
+		t.Error(structs)
+	}
+
+	typeMap := map[string]string{"User": "User", "*User": "UserPtr", "int": "int"}
+	fields, err := getPluckedFields(dir, "User", typeMap)
+	if err != nil || !reflect.DeepEqual(fields, map[string][]gen.Field{"UserList": expected, "UserPtrList": expected}) {
+		t.Error(fields, err)
+	}
+	fields, err = getPluckedFields(dir, "User.Born,User.Name,User.Born", typeMap)
+	if err != nil || !reflect.DeepEqual(fields["UserList"], []gen.Field{{Name: "Born", Type: "time.Time"}, {Name: "Name", Type: "string"}}) {
+		t.Error(fields, err)
+	}
+	for _, plucked := range []string{"Admin", "User.age", "User.Phone", "Base"} {
+		if _, err := getPluckedFields(dir, plucked, typeMap); err == nil {
+			t.Error(plucked)
+		}
+	}
+
+	addFieldTypes(typeMap, map[string][]gen.Field{"UserList": append(expected, gen.Field{Name: "Tags", Type: "map[string]int"})})
+	if !reflect.DeepEqual(typeMap, map[string]string{"User": "User", "*User": "UserPtr", "int": "int", "string": "string", "time.Time": "Time", "*Base": "*Base"}) {
+		t.Error(typeMap)
+	}
+}
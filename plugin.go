@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/kulshekhar/fungen/gen"
+	"github.com/kulshekhar/fungen/internal/spec"
+)
+
+// PluginPlan - the generation plan of a file, which the -plugin executable receives as JSON on its standard input
+type PluginPlan struct {
+	Package string       `json:"package"`
+	File    string       `json:"file"`
+	Types   []PluginType `json:"types"`
+	Methods []string     `json:"methods"`
+	Chunked bool         `json:"chunked"`
+	Pooled  bool         `json:"pooled"`
+}
+
+// PluginType - a list type of the generation plan
+type PluginType struct {
+	// TypeName - the type of the members of the list, eg: 'int'
+	TypeName string `json:"typeName"`
+	// ListName - the name of the list type, eg: 'intList'
+	ListName string `json:"listName"`
+	// Name - the name of the type used in the names of the methods, eg: 'Int' (in 'MapInt')
+	Name string `json:"name"`
+	// Methods - the methods generated for the list, if they are not the methods of the plan, eg: 'int[Map,Filter]'
+	Methods []string `json:"methods,omitempty"`
+}
+
+// newPluginPlan - get the generation plan of a file from its Spec, with the qualified types of the generated code, eg:
+// 'model.User'
+func newPluginPlan(filename string, fileSpec gen.Spec) PluginPlan {
+	plan := PluginPlan{Package: fileSpec.Package, File: filename, Types: []PluginType{}, Methods: fileSpec.Methods, Chunked: fileSpec.Chunked, Pooled: fileSpec.Pooled}
+	selected, _, _ := gen.QualifyTypes(fileSpec.Types)
+	for _, typeName := range spec.SortedTypes(selected) {
+		name := strings.TrimPrefix(selected[typeName], "*")
+		pluginType := PluginType{TypeName: typeName, ListName: name + "List", Name: strings.Title(name)}
+		if methods, ok := fileSpec.TypeMethods[pluginType.ListName]; ok {
+			pluginType.Methods = methods
+		}
+		plan.Types = append(plan.Types, pluginType)
+	}
+	return plan
+}
+
+// runPlugin - run the plugin command with the plan on its standard input, and get the code it writes to its standard
+// output. The messages it writes to its standard error are passed on
+func runPlugin(command string, plan PluginPlan) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", fmt.Errorf("the command is empty")
+	}
+
+	input, err := json.Marshal(plan)
+	if err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &output
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("'%s': %s", command, err)
+	}
+	return output.String(), nil
+}
+
+// appendPluginCode - append the code of a plugin to the generated source. The code can start with import declarations,
+// which are added to the imports of the source unless it already has them
+func appendPluginCode(src, code string) (string, error) {
+	const prefix = "package plugin\n"
+	pluginFile, err := parser.ParseFile(token.NewFileSet(), "", prefix+code, parser.ImportsOnly)
+	if err != nil {
+		return "", fmt.Errorf("the output is not valid Go code: %s", err)
+	}
+
+	fset := token.NewFileSet()
+	srcFile, err := parser.ParseFile(fset, "", src, parser.ImportsOnly)
+	if err != nil {
+		return "", err
+	}
+	existing := map[string]bool{}
+	for _, spec := range srcFile.Imports {
+		existing[importSpec(spec)] = true
+	}
+
+	missing := []string{}
+	for _, spec := range pluginFile.Imports {
+		if !existing[importSpec(spec)] {
+			existing[importSpec(spec)] = true
+			missing = append(missing, importSpec(spec))
+		}
+	}
+
+	// the declarations of the plugin start after its last import declaration
+	body := code
+	if len(pluginFile.Decls) > 0 {
+		body = code[int(pluginFile.Decls[len(pluginFile.Decls)-1].End())-1-len(prefix):]
+	}
+
+	if len(missing) == 0 {
+		return src + "\n" + body, nil
+	}
+
+	// the missing imports are added to the last import declaration of the source if it is parenthesized, or else after
+	// it or after the package clause
+	if len(srcFile.Decls) > 0 {
+		last := srcFile.Decls[len(srcFile.Decls)-1].(*ast.GenDecl)
+		if last.Rparen.IsValid() {
+			insertAt := fset.Position(last.Rparen).Offset
+			return src[:insertAt] + "\t" + strings.Join(missing, "\n\t") + "\n" + src[insertAt:] + "\n" + body, nil
+		}
+	}
+	insertAt := fset.Position(srcFile.Name.End()).Offset
+	if len(srcFile.Decls) > 0 {
+		insertAt = fset.Position(srcFile.Decls[len(srcFile.Decls)-1].End()).Offset
+	}
+	return src[:insertAt] + "\n\nimport (\n\t" + strings.Join(missing, "\n\t") + "\n)" + src[insertAt:] + "\n" + body, nil
+}
+
+// importSpec - get an import spec as it is written in an import declaration, eg: 'r "math/rand"'
+func importSpec(spec *ast.ImportSpec) string {
+	path, _ := strconv.Unquote(spec.Path.Value)
+	if spec.Name != nil {
+		return spec.Name.Name + " " + strconv.Quote(path)
+	}
+	return strconv.Quote(path)
+}
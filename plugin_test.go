@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+func TestAppendPluginCode(t *testing.T) {
+	src := "package main\n\nimport (\n\t\"sync\"\n)\n\ntype intList []int\n"
+	code := "import (\n\t\"sort\"\n\t\"sync\"\n)\n\nfunc (l intList) Sort() {\n\tsort.Ints(l)\n}\n"
+	expected := "package main\n\nimport (\n\t\"sync\"\n\t\"sort\"\n)\n\ntype intList []int\n\n\n\nfunc (l intList) Sort() {\n\tsort.Ints(l)\n}\n"
+	result, err := appendPluginCode(src, code)
+	if err != nil || result != expected {
+		t.Fail()
+	}
+
+	src = "package main\n\ntype intList []int\n"
+	code = "import r \"math/rand\"\n\nfunc (l intList) Shuffle() {\n\tr.Shuffle(len(l), func(i, j int) { l[i], l[j] = l[j], l[i] })\n}\n"
+	expected = "package main\n\nimport (\n\tr \"math/rand\"\n)\n\ntype intList []int\n\n\n\nfunc (l intList) Shuffle() {\n\tr.Shuffle(len(l), func(i, j int) { l[i], l[j] = l[j], l[i] })\n}\n"
+	result, err = appendPluginCode(src, code)
+	if err != nil || result != expected {
+		t.Fail()
+	}
+
+	result, err = appendPluginCode(src, "func (l intList) Len() int { return len(l) }\n")
+	if err != nil || result != src+"\nfunc (l intList) Len() int { return len(l) }\n" {
+		t.Fail()
+	}
+
+	if _, err := appendPluginCode(src, "import \"fmt\n"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestNewPluginPlan(t *testing.T) {
+	fileSpec := gen.Spec{Package: "models", Types: map[string]string{"string": "string", "*point": "*point"}, Methods: []string{"Map"}, Chunked: true}
+	plan := newPluginPlan("fungen_auto.go", fileSpec)
+	if len(plan.Types) != 2 || plan.Package != "models" || plan.File != "fungen_auto.go" || !reflect.DeepEqual(plan.Methods, []string{"Map"}) || !plan.Chunked {
+		t.Fail()
+	}
+	if !reflect.DeepEqual(plan.Types[0], PluginType{TypeName: "*point", ListName: "pointList", Name: "Point"}) {
+		t.Fail()
+	}
+	if !reflect.DeepEqual(plan.Types[1], PluginType{TypeName: "string", ListName: "stringList", Name: "String"}) {
+		t.Fail()
+	}
+
+	fileSpec = gen.Spec{Types: map[string]string{"string": "string"}, Methods: []string{"Map"}, TypeMethods: map[string][]string{"stringList": {"Filter", "Take"}}}
+	plan = newPluginPlan("fungen_auto.go", fileSpec)
+	if !reflect.DeepEqual(plan.Types[0].Methods, []string{"Filter", "Take"}) {
+		t.Error(plan.Types[0])
+	}
+
+	fileSpec = gen.Spec{Types: map[string]string{"github.com/acme/app/model.User": "User"}}
+	if plan := newPluginPlan("fungen_auto.go", fileSpec); plan.Types[0].TypeName != "model.User" {
+		t.Error(plan.Types[0])
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+// Report - the summary of a run of fungen written by -report=json
+type Report struct {
+	// Types - the number of element types processed
+	Types int `json:"types"`
+	// Methods - the number of methods emitted on the generated types, in all the files
+	Methods int `json:"methods"`
+	// Files - the files written, or only generated with -check
+	Files []ReportFile `json:"files"`
+	// Bytes - the total size of the files
+	Bytes int `json:"bytes"`
+	// DurationMs - the duration of the run in milliseconds
+	DurationMs float64 `json:"durationMs"`
+	// Warnings - the warnings reported during the run, even with -q
+	Warnings []string `json:"warnings"`
+	// Skipped - the requested methods which are not generated because they do not fit the element types
+	Skipped []ReportSkip `json:"skipped"`
+}
+
+// ReportFile - a generated file of the report
+type ReportFile struct {
+	Name    string `json:"name"`
+	Types   int    `json:"types"`
+	Methods int    `json:"methods"`
+	Bytes   int    `json:"bytes"`
+}
+
+// ReportSkip - a requested method which is not generated for a list, with the reason, eg: "the members of 'User' are
+// not numbers"
+type ReportSkip struct {
+	List   string `json:"list"`
+	Type   string `json:"type"`
+	Method string `json:"method"`
+	Reason string `json:"reason"`
+}
+
+// report - the summary of the current run
+var report = Report{Files: []ReportFile{}, Warnings: []string{}, Skipped: []ReportSkip{}}
+
+// warningsMutex - guard the warnings of the report, which are reported by the files generated concurrently
+var warningsMutex = sync.Mutex{}
+
+// addReportFile - add a generated file to the report
+func addReportFile(filename, src string, types int) {
+	methods := countMethods(src)
+	report.Files = append(report.Files, ReportFile{Name: filename, Types: types, Methods: methods, Bytes: len(src)})
+	report.Methods += methods
+	report.Bytes += len(src)
+}
+
+// addReportOmissions - add the requested methods which are not generated to the report
+func addReportOmissions(omissions []gen.Omission) {
+	for _, omission := range omissions {
+		report.Skipped = append(report.Skipped, ReportSkip{List: omission.List, Type: omission.Type, Method: omission.Method, Reason: omission.Reason})
+	}
+}
+
+// countMethods - count the method declarations (functions with a receiver) of a source file
+func countMethods(src string) int {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// warnf - report a warning, unless -q is set, and add it to the report
+func warnf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	warningsMutex.Lock()
+	defer warningsMutex.Unlock()
+	report.Warnings = append(report.Warnings, message)
+	infof("Warning: %s", message)
+}
+
+// writeReport - write the report in the -report format to the -report-file, or to the standard output if it is '-'
+func writeReport(start time.Time) {
+	report.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatalf("Error: -report: %s", err)
+	}
+	content = append(content, '\n')
+
+	if *reportOutput == "-" {
+		os.Stdout.Write(content)
+		return
+	}
+	if err := ioutil.WriteFile(*reportOutput, content, 0644); err != nil {
+		flagFailf("report-file", exitIO, "-report-file parameter %s", err)
+	}
+}
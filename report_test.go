@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCountMethods(t *testing.T) {
+	src := `package main
+
+type intList []int
+
+func (l intList) Len() int { return len(l) }
+
+func (l *intList) Push(i int) { *l = append(*l, i) }
+
+func newIntList() intList { return intList{} }
+`
+	if countMethods(src) != 2 {
+		t.Fail()
+	}
+	if countMethods("not go") != 0 {
+		t.Fail()
+	}
+}
+
+func TestAddReportFile(t *testing.T) {
+	defer func(saved Report) { report = saved }(report)
+	report = Report{}
+
+	src := "package main\n\ntype intList []int\n\nfunc (l intList) Len() int { return len(l) }\n"
+	addReportFile("a.go", src, 1)
+	addReportFile("b.go", src, 1)
+	if len(report.Files) != 2 || report.Methods != 2 || report.Bytes != 2*len(src) {
+		t.Fail()
+	}
+	if report.Files[1] != (ReportFile{Name: "b.go", Types: 1, Methods: 1, Bytes: len(src)}) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kulshekhar/fungen/gen"
+)
+
+// typeCheckGenerated - type-check the generated sources of files (by their names) together with the other Go files of
+// their package in the directory of the files, except the test files and the previous versions of the files, under their
+// name or another one, like the file of a previous run when the code is written to the standard output. It returns
+// the errors in the generated code, described with the declaration they are in and its element type (lists maps the
+// list names to the element types), and an error if a package cannot be type-checked, eg: because an import cannot be
+// found. The errors in the other files are ignored
+func typeCheckGenerated(sources map[string]string, packageName string, lists map[string]string) ([]string, error) {
+	dirs := map[string][]string{}
+	names := []string{}
+	for filename := range sources {
+		dir := filepath.Dir(filename)
+		if _, ok := dirs[dir]; !ok {
+			names = append(names, dir)
+		}
+		dirs[dir] = append(dirs[dir], filename)
+	}
+	sort.Strings(names)
+
+	result := []string{}
+	for _, dir := range names {
+		errs, err := typeCheckDir(dir, dirs[dir], sources, packageName, lists)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, errs...)
+	}
+	return result, nil
+}
+
+// typeCheckDir - type-check the generated sources of the files of a directory together with its other Go files
+func typeCheckDir(dir string, filenames []string, sources map[string]string, packageName string, lists map[string]string) ([]string, error) {
+	sort.Strings(filenames)
+	fset := token.NewFileSet()
+	generated := map[string]*ast.File{}
+	files := []*ast.File{}
+	for _, filename := range filenames {
+		file, err := parser.ParseFile(fset, filename, sources[filename], 0)
+		if err != nil {
+			return nil, err
+		}
+		generated[filename] = file
+		files = append(files, file)
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		name := info.Name()
+		path := filepath.Join(dir, name)
+		if _, ok := generated[path]; ok || info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil || file.Name.Name != packageName || generatedByFungen(file) && declaresList(file, lists) {
+			continue
+		}
+		files = append(files, file)
+	}
+
+	result := []string{}
+	var importErr error
+	config := gotypes.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			typeErr, ok := err.(gotypes.Error)
+			if !ok {
+				return
+			}
+			if strings.Contains(typeErr.Msg, "could not import") {
+				importErr = err
+				return
+			}
+			position := fset.Position(typeErr.Pos)
+			file, ok := generated[position.Filename]
+			if !ok {
+				return
+			}
+			result = append(result, fmt.Sprintf("%s: %s: %s", position, declarationAt(file, typeErr.Pos, lists), typeErr.Msg))
+		},
+	}
+	config.Check(packageName, fset, files, nil)
+	if importErr != nil {
+		return nil, importErr
+	}
+	return result, nil
+}
+
+// declaresList - whether a file declares one of the generated lists, as the previous versions of the generated files do
+func declaresList(file *ast.File, lists map[string]string) bool {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			if _, ok := lists[spec.(*ast.TypeSpec).Name.Name]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// declarationAt - describe the top-level declaration of the generated file containing a position, eg: 'MapStr of
+// intList (element type int)' or 'intListFromChan (element type int)'
+func declarationAt(file *ast.File, pos token.Pos, lists map[string]string) string {
+	for _, decl := range file.Decls {
+		if decl.Pos() > pos || decl.End() < pos {
+			continue
+		}
+		name := gen.DeclarationName(decl)
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+			name = fn.Name.Name + " of " + name
+		}
+		if listName := gen.ListOf(gen.DeclarationName(decl), lists); listName != "" {
+			name += fmt.Sprintf(" (element type %s)", lists[listName])
+		}
+		return name
+	}
+	return "the package"
+}
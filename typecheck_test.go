@@ -0,0 +1,102 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTypeCheckGenerated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"main.go":        "package main\n\ntype point struct{ X int }\n\nfunc main() {}\n",
+		"main_test.go":   "package main\n\nvar broken int = \"\"\n",
+		"fungen_auto.go": "package main\n\ntype intList []string\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lists := map[string]string{"intList": "int", "pointList": "*point"}
+	filename := filepath.Join(dir, "fungen_auto.go")
+	valid := map[string]string{
+		filename:                       "package main\n\ntype intList []int\n\nfunc (l intList) Len() int { return len(l) }\n",
+		filepath.Join(dir, "point.go"): "package main\n\ntype pointList []*point\n\nfunc (l pointList) Lengths() intList { return intList{len(l)} }\n",
+	}
+	errs, err := typeCheckGenerated(valid, "main", lists)
+	if err != nil || len(errs) != 0 {
+		t.Fatal(err, errs)
+	}
+
+	invalid := map[string]string{
+		filename: "package main\n\ntype intList []int\n\nfunc (l intList) Sum() point { return l[0] }\n",
+	}
+	errs, err = typeCheckGenerated(invalid, "main", lists)
+	if err != nil || len(errs) != 1 || !strings.Contains(errs[0], "fungen_auto.go:5:") || !strings.Contains(errs[0], ": Sum of intList (element type int): ") {
+		t.Fatal(err, errs)
+	}
+
+	// the file of a previous run is left out when the code is generated under another name, like the standard output,
+	// but not the lists of the other files generated by fungen
+	previous := "// Code generated by fungen; DO NOT EDIT.\n\npackage main\n\ntype intList []int\n"
+	other := "// Code generated by fungen; DO NOT EDIT.\n\npackage main\n\ntype stringList []string\n"
+	for name, content := range map[string]string{"fungen_auto.go": previous, "strings_fungen.go": other} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	renamed := map[string]string{
+		filepath.Join(dir, "lists.go"): "package main\n\ntype intList []int\n\nfunc (l intList) Strings() stringList { return nil }\n",
+	}
+	errs, err = typeCheckGenerated(renamed, "main", lists)
+	if err != nil || len(errs) != 0 {
+		t.Fatal(err, errs)
+	}
+
+	imports := map[string]string{filename: "package main\n\nimport \"example.com/missing\"\n\nvar _ = missing.Value\n"}
+	if _, err := typeCheckGenerated(imports, "main", lists); err == nil {
+		t.Fail()
+	}
+}
+
+func TestDeclarationAt(t *testing.T) {
+	src := `package main
+
+type intList []int
+
+func (l intList) Len() int { return len(l) }
+
+func intListFromChan(c chan int) intList { return nil }
+
+var _ = 1
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lists := map[string]string{"intList": "int"}
+	at := func(text string) string {
+		return declarationAt(file, file.Pos()+token.Pos(strings.Index(src, text)), lists)
+	}
+	if at("len(l)") != "Len of intList (element type int)" {
+		t.Error(at("len(l)"))
+	}
+	if at("return nil") != "intListFromChan (element type int)" {
+		t.Error(at("return nil"))
+	}
+	if at("[]int") != "intList (element type int)" {
+		t.Error(at("[]int"))
+	}
+}
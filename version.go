@@ -0,0 +1,36 @@
+package main
+
+import "runtime/debug"
+
+// fungenVersion - the version of fungen, stamped into the header of the generated files
+var fungenVersion = getVersion()
+
+// getVersion - get the module version and the VCS revision fungen was built from. It is '(devel)' for builds outside
+// of a module, eg: with GOPATH
+func getVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return "(devel)"
+	}
+
+	version := info.Main.Version
+	revision, modified := "", false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	if revision != "" {
+		version += " (" + revision + ")"
+	}
+	return version
+}
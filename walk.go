@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// job - one run of fungen: the arguments of a go:generate directive, or a configuration file, in a directory
+type job struct {
+	dir  string
+	file string
+	line int
+	pkg  string
+	args []string
+}
+
+// forwardedFlags - the flags given along with directories which are passed on to every run
+var forwardedFlags = []string{"check", "strict", "v", "q"}
+
+// isDirectoryPattern - whether a command line argument is a directory or a pattern like './...'
+func isDirectoryPattern(arg string) bool {
+	if arg == "..." || strings.HasSuffix(arg, "/...") {
+		return true
+	}
+	info, err := os.Stat(arg)
+	return err == nil && info.IsDir()
+}
+
+// runDirectories - run fungen for all the go:generate directives and configuration files found in the directories
+// matching the patterns, the directories in parallel and the jobs of a directory in order. It returns the highest exit status of the failed runs, or 0 if all of them
+// succeed, so that 'fungen ./...' tells the errors apart like a single run
+func runDirectories(patterns []string) int {
+	jobs := []job{}
+	for _, pattern := range patterns {
+		found, err := findJobs(pattern)
+		if err != nil {
+			failf(statusOf(err, exitParse), "%s", err)
+		}
+		jobs = append(jobs, found...)
+	}
+
+	forwarded := []string{}
+	flag.Visit(func(f *flag.Flag) {
+		for _, name := range forwardedFlags {
+			if f.Name == name {
+				forwarded = append(forwarded, "-"+f.Name+"="+f.Value.String())
+			}
+		}
+	})
+
+	self, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	status := 0
+	mutex := sync.Mutex{}
+	run := func(j job) {
+		cmd := exec.Command(self, append(j.args, forwarded...)...)
+		cmd.Dir = j.dir
+		cmd.Env = append(os.Environ(), "GOFILE="+j.file, "GOLINE="+strconv.Itoa(j.line), "GOPACKAGE="+j.pkg)
+		output, err := cmd.CombinedOutput()
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if len(output) > 0 {
+			os.Stderr.Write(output)
+		}
+		if err != nil {
+			failed := exitFailure
+			if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() > 0 {
+				failed = exitError.ExitCode()
+			}
+			if failed > status {
+				status = failed
+			}
+			log.Printf("Error: %s: %s", j, err)
+		}
+	}
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, runtime.NumCPU())
+	for _, dirJobs := range jobsByDirectory(jobs) {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(jobs []job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			for _, j := range jobs {
+				run(j)
+			}
+		}(dirJobs)
+	}
+	wg.Wait()
+
+	return status
+}
+
+// jobsByDirectory - group the jobs by directory, in the order of their first job, keeping the order of the jobs of every
+// directory. The jobs of a directory run one after the other, since they write and type-check the files of the same
+// package
+func jobsByDirectory(jobs []job) [][]job {
+	result := [][]job{}
+	indexes := map[string]int{}
+	for _, j := range jobs {
+		dir := filepath.Clean(j.dir)
+		i, ok := indexes[dir]
+		if !ok {
+			i = len(result)
+			indexes[dir] = i
+			result = append(result, nil)
+		}
+		result[i] = append(result[i], j)
+	}
+	return result
+}
+
+// String - describe the job in error messages
+func (j job) String() string {
+	if j.line == 0 {
+		return filepath.Join(j.dir, j.file)
+	}
+	return fmt.Sprintf("%s:%d", filepath.Join(j.dir, j.file), j.line)
+}
+
+// findJobs - find the go:generate directives which run fungen in the Go files of a directory, or of a directory and
+// all its subdirectories if the pattern ends with '/...'. A directory without directives but with a configuration
+// file is generated from the configuration file
+func findJobs(pattern string) ([]job, error) {
+	jobs := []job{}
+	err := walkDirectories(pattern, func(dir string) error {
+		found, err := findDirectoryJobs(dir)
+		jobs = append(jobs, found...)
+		return err
+	})
+	return jobs, err
+}
+
+// walkDirectories - call fn with a directory, or with a directory and all its subdirectories if the pattern ends with
+// '/...'. Like the go command, vendor and testdata directories and the directories starting with '.' or '_' are
+// skipped
+func walkDirectories(pattern string, fn func(dir string) error) error {
+	root, recursive := pattern, false
+	if pattern == "..." || strings.HasSuffix(pattern, "/...") {
+		root, recursive = strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/"), true
+		if root == "" {
+			root = "."
+		}
+	}
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root {
+			name := info.Name()
+			if !recursive || name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+		}
+		return fn(path)
+	})
+}
+
+// findDirectoryJobs - find the jobs of a single directory
+func findDirectoryJobs(dir string) ([]job, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := ""
+	jobs := []job{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".go") {
+			continue
+		}
+		found, filePkg, err := findFileJobs(dir, file.Name())
+		if err != nil {
+			return nil, err
+		}
+		if pkg == "" && !strings.HasSuffix(file.Name(), "_test.go") {
+			pkg = filePkg
+		}
+		jobs = append(jobs, found...)
+	}
+
+	if len(jobs) == 0 {
+		if _, err := os.Stat(filepath.Join(dir, defaultConfigName)); err == nil {
+			jobs = append(jobs, job{dir: dir, file: defaultConfigName, pkg: pkg, args: []string{"-config", defaultConfigName}})
+		}
+	}
+	return jobs, nil
+}
+
+// findFileJobs - find the go:generate directives which run fungen in a Go file, and get the package of the file
+func findFileJobs(dir, filename string) ([]job, string, error) {
+	path := filepath.Join(dir, filename)
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), path, src, parser.PackageClauseOnly)
+	if err != nil {
+		return nil, "", err
+	}
+	pkg := file.Name.Name
+
+	jobs := []job{}
+	scanner := bufio.NewScanner(strings.NewReader(string(src)))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		args, ok := parseDirective(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		env := map[string]string{"GOFILE": filename, "GOLINE": strconv.Itoa(lineNumber), "GOPACKAGE": pkg, "DOLLAR": "$"}
+		for i, arg := range args {
+			args[i] = os.Expand(arg, func(name string) string {
+				if value, ok := env[name]; ok {
+					return value
+				}
+				return os.Getenv(name)
+			})
+		}
+		jobs = append(jobs, job{dir: dir, file: filename, line: lineNumber, pkg: pkg, args: args})
+	}
+	return jobs, pkg, scanner.Err()
+}
+
+// parseDirective - get the arguments of a go:generate directive whose command is fungen. Like go generate, the words
+// are separated by spaces and double quoted words are Go strings
+func parseDirective(line string) ([]string, bool) {
+	if !strings.HasPrefix(line, "//go:generate ") && !strings.HasPrefix(line, "//go:generate\t") {
+		return nil, false
+	}
+
+	words := []string{}
+	rest := strings.TrimSpace(line[len("//go:generate "):])
+	for rest != "" {
+		word := ""
+		if rest[0] == '"' {
+			end := 1
+			for ; end < len(rest); end++ {
+				if rest[end] == '\\' {
+					end++
+				} else if rest[end] == '"' {
+					break
+				}
+			}
+			if end >= len(rest) {
+				return nil, false
+			}
+			unquoted, err := strconv.Unquote(rest[:end+1])
+			if err != nil {
+				return nil, false
+			}
+			word, rest = unquoted, rest[end+1:]
+		} else {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			word, rest = rest[:end], rest[end:]
+		}
+		words = append(words, word)
+		rest = strings.TrimLeft(rest, " \t")
+	}
+
+	if len(words) == 0 || strings.TrimSuffix(filepath.Base(words[0]), ".exe") != "fungen" {
+		return nil, false
+	}
+	return words[1:], true
+}
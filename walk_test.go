@@ -0,0 +1,79 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDirective(t *testing.T) {
+	args, ok := parseDirective(`//go:generate $GOPATH/bin/fungen -types "Generator" -methods Filter,Each`)
+	if !ok || strings.Join(args, " ") != "-types Generator -methods Filter,Each" {
+		t.Fail()
+	}
+
+	args, ok = parseDirective(`//go:generate fungen -types "a b\"c"`)
+	if !ok || len(args) != 2 || args[1] != `a b"c` {
+		t.Fail()
+	}
+
+	for _, line := range []string{`//go:generate go run gen.go`, `// go:generate fungen -types int`, `//go:generate fungen -types "int`} {
+		if _, ok := parseDirective(line); ok {
+			t.Fail()
+		}
+	}
+}
+
+func TestFindJobs(t *testing.T) {
+	root, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		"a/a.go":          "package a\n\n//go:generate fungen -types int -o ${GOFILE}_fungen.go\n",
+		"a/b/b.go":        "package b\n",
+		"a/b/fungen.yaml": "types: [int]\n",
+		"a/vendor/v/v.go": "package v\n\n//go:generate fungen -types int\n",
+		"a/_c/c.go":       "package c\n\n//go:generate fungen -types int\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs, err := findJobs(filepath.Join(root, "a") + "/...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 {
+		t.Fatal(jobs)
+	}
+	if jobs[0].pkg != "a" || jobs[0].line != 3 || strings.Join(jobs[0].args, " ") != "-types int -o a.go_fungen.go" {
+		t.Fail()
+	}
+	if jobs[1].pkg != "b" || strings.Join(jobs[1].args, " ") != "-config fungen.yaml" {
+		t.Fail()
+	}
+
+	jobs, err = findJobs(filepath.Join(root, "a"))
+	if err != nil || len(jobs) != 1 {
+		t.Fail()
+	}
+}
+
+func TestJobsByDirectory(t *testing.T) {
+	jobs := []job{{dir: "a", line: 1}, {dir: "b", line: 1}, {dir: "a/", line: 2}, {dir: "c"}, {dir: "b", line: 2}}
+	groups := jobsByDirectory(jobs)
+	if len(groups) != 3 || len(groups[0]) != 2 || groups[0][1].line != 2 || len(groups[1]) != 2 || groups[1][1].line != 2 || groups[2][0].dir != "c" {
+		t.Error(groups)
+	}
+}